@@ -0,0 +1,67 @@
+package myshoes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/whywaita/myshoes/pkg/web"
+)
+
+// CreateAPIToken create a scoped API token, Token in response is only returned at this time
+func (c *Client) CreateAPIToken(ctx context.Context, param web.APITokenCreateParam) (*web.UserAPIToken, error) {
+	spath := "/api_token"
+
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf("failed to json.Marshal: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+
+	var token web.UserAPIToken
+	if err := c.request(req, &token); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return &token, nil
+}
+
+// ListAPITokens get a list of scoped API tokens
+func (c *Client) ListAPITokens(ctx context.Context) ([]web.UserAPIToken, error) {
+	spath := "/api_token"
+
+	req, err := c.newRequest(ctx, http.MethodGet, spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+
+	var tokens []web.UserAPIToken
+	if err := c.request(req, &tokens); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return tokens, nil
+}
+
+// RevokeAPIToken revoke a scoped API token
+func (c *Client) RevokeAPIToken(ctx context.Context, tokenID string) error {
+	spath := fmt.Sprintf("/api_token/%s", tokenID)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, spath, nil)
+	if err != nil {
+		return fmt.Errorf(errCreateRequest, err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err := c.request(req, &i); err != nil {
+		return fmt.Errorf(errRequest, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package myshoes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+// ListAuditLogsOption is option of ListAuditLogs
+type ListAuditLogsOption struct {
+	Actor        string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Since        time.Time
+	Until        time.Time
+	Limit        int
+	Offset       int
+}
+
+// ListAuditLogs get a list of audit logs
+func (c *Client) ListAuditLogs(ctx context.Context, opt ListAuditLogsOption) ([]datastore.AuditLog, error) {
+	spath := "/audit_log"
+
+	req, err := c.newRequest(ctx, http.MethodGet, spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+	v := listValues("", "", opt.Since, opt.Until, opt.Limit, opt.Offset)
+	for key, value := range map[string]string{
+		"actor":         opt.Actor,
+		"action":        opt.Action,
+		"resource_type": opt.ResourceType,
+		"resource_id":   opt.ResourceID,
+	} {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	req.URL.RawQuery = v.Encode()
+
+	var logs []datastore.AuditLog
+	if err := c.request(req, &logs); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return logs, nil
+}
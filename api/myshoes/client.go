@@ -9,6 +9,9 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
+
+	"github.com/whywaita/myshoes/pkg/auth"
 )
 
 // Client is a client for myshoes
@@ -18,6 +21,12 @@ type Client struct {
 
 	UserAgent string
 	Logger    *log.Logger
+
+	// BearerToken is sent as "Authorization: Bearer" (static API token or JWT)
+	BearerToken string
+	// HMACKeyID and HMACSecret sign a request if set
+	HMACKeyID  string
+	HMACSecret string
 }
 
 const (
@@ -74,6 +83,19 @@ func (c *Client) newRequest(ctx context.Context, method, spath string, body io.R
 	return req, nil
 }
 
+// setCredentials set credentials to request, must be called after the request is completed (e.g. query)
+func (c *Client) setCredentials(req *http.Request) error {
+	if c.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.BearerToken)
+	}
+	if c.HMACKeyID != "" {
+		if err := auth.SignRequest(req, c.HMACKeyID, c.HMACSecret, time.Now()); err != nil {
+			return fmt.Errorf("failed to sign request: %w", err)
+		}
+	}
+	return nil
+}
+
 // Error values
 var (
 	errCreateRequest = "failed to create request: %w"
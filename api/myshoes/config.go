@@ -0,0 +1,51 @@
+package myshoes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/web"
+)
+
+// GetConfig get current runtime config
+func (c *Client) GetConfig(ctx context.Context) (*config.Runtime, error) {
+	spath := "/config"
+
+	req, err := c.newRequest(ctx, http.MethodGet, spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+
+	var rc config.Runtime
+	if err := c.request(req, &rc); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return &rc, nil
+}
+
+// UpdateConfig update runtime config, fields that are not set are not changed
+func (c *Client) UpdateConfig(ctx context.Context, param web.ConfigPatchParam) (*config.Runtime, error) {
+	spath := "/config"
+
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf("failed to json.Marshal: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPatch, spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+
+	var rc config.Runtime
+	if err := c.request(req, &rc); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return &rc, nil
+}
@@ -34,7 +34,11 @@ func decodeErrorBody(resp *http.Response) error {
 }
 
 func (c *Client) request(req *http.Request, out interface{}) error {
-	c.Logger.Printf("Do request: %+v", req)
+	if err := c.setCredentials(req); err != nil {
+		return fmt.Errorf("failed to set credentials: %w", err)
+	}
+
+	c.Logger.Printf("Do request: %s %s", req.Method, req.URL.String())
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to do HTTP request: %w", err)
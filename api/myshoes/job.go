@@ -0,0 +1,89 @@
+package myshoes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/whywaita/myshoes/pkg/web"
+)
+
+// ListJobsOption is option of ListJobs
+type ListJobsOption struct {
+	TargetID   string
+	Repository string
+	Since      time.Time
+	Until      time.Time
+	Limit      int
+	Offset     int
+}
+
+// ListJobs get a list of queued jobs
+func (c *Client) ListJobs(ctx context.Context, opt ListJobsOption) ([]web.UserJob, error) {
+	spath := "/job"
+
+	req, err := c.newRequest(ctx, http.MethodGet, spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+	req.URL.RawQuery = listValues(opt.TargetID, opt.Repository, opt.Since, opt.Until, opt.Limit, opt.Offset).Encode()
+
+	var jobs []web.UserJob
+	if err := c.request(req, &jobs); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return jobs, nil
+}
+
+// GetJob get a queued job
+func (c *Client) GetJob(ctx context.Context, jobID string) (*web.UserJobDetail, error) {
+	spath := fmt.Sprintf("/job/%s", jobID)
+
+	req, err := c.newRequest(ctx, http.MethodGet, spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+
+	var job web.UserJobDetail
+	if err := c.request(req, &job); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return &job, nil
+}
+
+// DeleteJob delete a queued job
+func (c *Client) DeleteJob(ctx context.Context, jobID string) error {
+	spath := fmt.Sprintf("/job/%s", jobID)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, spath, nil)
+	if err != nil {
+		return fmt.Errorf(errCreateRequest, err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err := c.request(req, &i); err != nil {
+		return fmt.Errorf(errRequest, err)
+	}
+
+	return nil
+}
+
+// RetryJob reset backoff of a queued job, the job is processed immediately
+func (c *Client) RetryJob(ctx context.Context, jobID string) (*web.UserJobDetail, error) {
+	spath := fmt.Sprintf("/job/%s/retry", jobID)
+
+	req, err := c.newRequest(ctx, http.MethodPost, spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+
+	var job web.UserJobDetail
+	if err := c.request(req, &job); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return &job, nil
+}
@@ -0,0 +1,67 @@
+package myshoes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/whywaita/myshoes/pkg/web"
+)
+
+// GetMaintenance get status of maintenance mode
+func (c *Client) GetMaintenance(ctx context.Context) (*web.MaintenanceStatus, error) {
+	spath := "/maintenance"
+
+	req, err := c.newRequest(ctx, http.MethodGet, spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+
+	var status web.MaintenanceStatus
+	if err := c.request(req, &status); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return &status, nil
+}
+
+// CreateMaintenanceWindow create a maintenance window, starter does not provision new runners in the window
+func (c *Client) CreateMaintenanceWindow(ctx context.Context, param web.MaintenanceWindowCreateParam) (*web.UserMaintenanceWindow, error) {
+	spath := "/maintenance"
+
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf("failed to json.Marshal: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+
+	var window web.UserMaintenanceWindow
+	if err := c.request(req, &window); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return &window, nil
+}
+
+// DeleteMaintenanceWindow delete a maintenance window, it ends maintenance if the window is active
+func (c *Client) DeleteMaintenanceWindow(ctx context.Context, windowID string) error {
+	spath := fmt.Sprintf("/maintenance/%s", windowID)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, spath, nil)
+	if err != nil {
+		return fmt.Errorf(errCreateRequest, err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err := c.request(req, &i); err != nil {
+		return fmt.Errorf(errRequest, err)
+	}
+
+	return nil
+}
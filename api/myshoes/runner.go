@@ -0,0 +1,114 @@
+package myshoes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/whywaita/myshoes/pkg/web"
+)
+
+// GetRunner get a runner
+func (c *Client) GetRunner(ctx context.Context, runnerID string) (*web.UserRunner, error) {
+	spath := fmt.Sprintf("/runner/%s", runnerID)
+
+	req, err := c.newRequest(ctx, http.MethodGet, spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+
+	var runner web.UserRunner
+	if err := c.request(req, &runner); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return &runner, nil
+}
+
+// DeleteRunner delete a runner manually
+func (c *Client) DeleteRunner(ctx context.Context, runnerID string) error {
+	spath := fmt.Sprintf("/runner/%s", runnerID)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, spath, nil)
+	if err != nil {
+		return fmt.Errorf(errCreateRequest, err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err := c.request(req, &i); err != nil {
+		return fmt.Errorf(errRequest, err)
+	}
+
+	return nil
+}
+
+// ListRunnersOption is option of ListRunners
+type ListRunnersOption struct {
+	// Status is "running" or "deleted", return all runners if empty
+	Status     string
+	TargetID   string
+	Repository string
+	Since      time.Time
+	Until      time.Time
+	Limit      int
+	Offset     int
+}
+
+func (o ListRunnersOption) values() url.Values {
+	v := listValues(o.TargetID, o.Repository, o.Since, o.Until, o.Limit, o.Offset)
+	if o.Status != "" {
+		v.Set("status", o.Status)
+	}
+	return v
+}
+
+// ListRunners get a list of runners
+func (c *Client) ListRunners(ctx context.Context, opt ListRunnersOption) ([]web.UserRunner, error) {
+	return c.listRunners(ctx, "/runner", opt)
+}
+
+// ListTargetRunners get a list of runners in a target
+func (c *Client) ListTargetRunners(ctx context.Context, targetID string, opt ListRunnersOption) ([]web.UserRunner, error) {
+	return c.listRunners(ctx, fmt.Sprintf("/target/%s/runners", targetID), opt)
+}
+
+func (c *Client) listRunners(ctx context.Context, spath string, opt ListRunnersOption) ([]web.UserRunner, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+	req.URL.RawQuery = opt.values().Encode()
+
+	var runners []web.UserRunner
+	if err := c.request(req, &runners); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return runners, nil
+}
+
+func listValues(targetID, repository string, since, until time.Time, limit, offset int) url.Values {
+	v := url.Values{}
+	if targetID != "" {
+		v.Set("target_id", targetID)
+	}
+	if repository != "" {
+		v.Set("repository", repository)
+	}
+	if !since.IsZero() {
+		v.Set("since", since.Format(time.RFC3339))
+	}
+	if !until.IsZero() {
+		v.Set("until", until.Format(time.RFC3339))
+	}
+	if limit != 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+	if offset != 0 {
+		v.Set("offset", strconv.Itoa(offset))
+	}
+	return v
+}
@@ -0,0 +1,106 @@
+package myshoes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+// CreateScriptTemplate create a script template
+func (c *Client) CreateScriptTemplate(ctx context.Context, param datastore.ScriptTemplate) (*datastore.ScriptTemplate, error) {
+	spath := "/script_template"
+
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf("failed to json.Marshal: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+
+	var st datastore.ScriptTemplate
+	if err := c.request(req, &st); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return &st, nil
+}
+
+// GetScriptTemplate get a script template
+func (c *Client) GetScriptTemplate(ctx context.Context, name string) (*datastore.ScriptTemplate, error) {
+	spath := fmt.Sprintf("/script_template/%s", name)
+
+	req, err := c.newRequest(ctx, http.MethodGet, spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+
+	var st datastore.ScriptTemplate
+	if err := c.request(req, &st); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return &st, nil
+}
+
+// UpdateScriptTemplate update a script template
+func (c *Client) UpdateScriptTemplate(ctx context.Context, name string, param datastore.ScriptTemplate) (*datastore.ScriptTemplate, error) {
+	spath := fmt.Sprintf("/script_template/%s", name)
+
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf("failed to json.Marshal: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+
+	var st datastore.ScriptTemplate
+	if err := c.request(req, &st); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return &st, nil
+}
+
+// DeleteScriptTemplate delete a script template
+func (c *Client) DeleteScriptTemplate(ctx context.Context, name string) error {
+	spath := fmt.Sprintf("/script_template/%s", name)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, spath, nil)
+	if err != nil {
+		return fmt.Errorf(errCreateRequest, err)
+	}
+
+	var i interface{} // this endpoint return N/A
+	if err := c.request(req, &i); err != nil {
+		return fmt.Errorf(errRequest, err)
+	}
+
+	return nil
+}
+
+// ListScriptTemplate get a list of script template
+func (c *Client) ListScriptTemplate(ctx context.Context) ([]datastore.ScriptTemplate, error) {
+	spath := "/script_template"
+
+	req, err := c.newRequest(ctx, http.MethodGet, spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+
+	var sts []datastore.ScriptTemplate
+	if err := c.request(req, &sts); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return sts, nil
+}
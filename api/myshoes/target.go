@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/whywaita/myshoes/pkg/web"
 )
@@ -104,3 +105,60 @@ func (c *Client) ListTarget(ctx context.Context) ([]web.UserTarget, error) {
 
 	return targets, nil
 }
+
+// PauseTarget pause a target, starter does not provision new runners for the target until resumed
+func (c *Client) PauseTarget(ctx context.Context, targetID string, param web.TargetPauseParam) (*web.UserTarget, error) {
+	spath := fmt.Sprintf("/target/%s/pause", targetID)
+
+	jb, err := json.Marshal(param)
+	if err != nil {
+		return nil, fmt.Errorf("failed to json.Marshal: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, spath, bytes.NewBuffer(jb))
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+
+	var target web.UserTarget
+	if err := c.request(req, &target); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return &target, nil
+}
+
+// ResumeTarget resume a paused target
+func (c *Client) ResumeTarget(ctx context.Context, targetID string) (*web.UserTarget, error) {
+	spath := fmt.Sprintf("/target/%s/resume", targetID)
+
+	req, err := c.newRequest(ctx, http.MethodPost, spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+
+	var target web.UserTarget
+	if err := c.request(req, &target); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return &target, nil
+}
+
+// ListTargetStatusHistory get status history of a target, newest first
+func (c *Client) ListTargetStatusHistory(ctx context.Context, targetID string, limit, offset int) ([]web.UserTargetStatusHistory, error) {
+	spath := fmt.Sprintf("/target/%s/status-history", targetID)
+
+	req, err := c.newRequest(ctx, http.MethodGet, spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+	req.URL.RawQuery = listValues("", "", time.Time{}, time.Time{}, limit, offset).Encode()
+
+	var histories []web.UserTargetStatusHistory
+	if err := c.request(req, &histories); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return histories, nil
+}
@@ -0,0 +1,51 @@
+package myshoes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+// GetUsageOption is option of GetUsage
+type GetUsageOption struct {
+	From    time.Time
+	To      time.Time
+	GroupBy []datastore.UsageGroupBy
+}
+
+// GetUsage get runner-minutes and estimated cost of runners
+func (c *Client) GetUsage(ctx context.Context, opt GetUsageOption) ([]datastore.Usage, error) {
+	spath := "/usage"
+
+	req, err := c.newRequest(ctx, http.MethodGet, spath, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errCreateRequest, err)
+	}
+	v := url.Values{}
+	if !opt.From.IsZero() {
+		v.Set("from", opt.From.Format(time.RFC3339))
+	}
+	if !opt.To.IsZero() {
+		v.Set("to", opt.To.Format(time.RFC3339))
+	}
+	if len(opt.GroupBy) != 0 {
+		groupBy := make([]string, 0, len(opt.GroupBy))
+		for _, g := range opt.GroupBy {
+			groupBy = append(groupBy, string(g))
+		}
+		v.Set("group_by", strings.Join(groupBy, ","))
+	}
+	req.URL.RawQuery = v.Encode()
+
+	var usages []datastore.Usage
+	if err := c.request(req, &usages); err != nil {
+		return nil, fmt.Errorf(errRequest, err)
+	}
+
+	return usages, nil
+}
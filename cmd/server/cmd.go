@@ -2,12 +2,17 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	_ "net/http/pprof" // register pprof handlers on the localhost:6060 DefaultServeMux
+	"os"
+	"os/signal"
 	"runtime"
 	"strings"
+	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/whywaita/myshoes/pkg/config"
@@ -15,37 +20,60 @@ import (
 	"github.com/whywaita/myshoes/pkg/datastore/mysql"
 	"github.com/whywaita/myshoes/pkg/gh"
 	"github.com/whywaita/myshoes/pkg/logger"
+	"github.com/whywaita/myshoes/pkg/metric"
+	"github.com/whywaita/myshoes/pkg/notify"
 	"github.com/whywaita/myshoes/pkg/runner"
 	"github.com/whywaita/myshoes/pkg/starter"
 	"github.com/whywaita/myshoes/pkg/starter/safety/unlimited"
+	"github.com/whywaita/myshoes/pkg/tracing"
 	"github.com/whywaita/myshoes/pkg/web"
 
 	"golang.org/x/sync/errgroup"
 )
 
-func init() {
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand(os.Args[2:]))
+	}
+
+	fs := flag.NewFlagSet("myshoes", flag.ExitOnError)
+	if err := parseConfigFlags(fs, os.Args[1:]); err != nil {
+		log.Fatalln(err)
+	}
 	config.Load()
+	if err := logger.SetFormat(config.Config.LogFormat); err != nil {
+		log.Fatalln(err)
+	}
 	mysqlURL := config.LoadMySQLURL()
 	config.Config.MySQLDSN = mysqlURL
 
 	if err := gh.InitializeCache(config.Config.GitHub.AppID, config.Config.GitHub.PEMByte); err != nil {
 		log.Panicf("failed to create a cache: %+v", err)
 	}
-}
 
-func main() {
 	runtime.SetBlockProfileRate(1)
 	runtime.SetMutexProfileFraction(1)
 	go func() {
 		log.Fatal(http.ListenAndServe("localhost:6060", nil))
 	}()
 
+	shutdownTracer, err := tracing.Init(context.Background(), config.Config.OTLPEndpoint)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	notify.Init(config.Config.Notify)
+
 	myshoes, err := newShoes()
 	if err != nil {
 		log.Fatalln(err)
 	}
 
-	if err := myshoes.Run(); err != nil {
+	err = myshoes.Run()
+	if err := shutdownTracer(context.Background()); err != nil {
+		logger.Logf(false, "failed to shutdown tracer: %+v", err)
+	}
+	if err != nil {
 		log.Fatalln(err)
 	}
 }
@@ -64,11 +92,18 @@ func newShoes() (*myShoes, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to mysql.New: %w", err)
 	}
+	if err := datastore.LoadRuntimeConfig(context.Background(), ds); err != nil {
+		return nil, fmt.Errorf("failed to load runtime config: %w", err)
+	}
+	if err := starter.LoadScriptTemplateDirectory(); err != nil {
+		return nil, fmt.Errorf("failed to load script template directory: %w", err)
+	}
+	runnerVersion := config.GetRuntime().RunnerVersion
 
 	unlimit := unlimited.Unlimited{}
-	s := starter.New(ds, unlimit, config.Config.RunnerVersion, notifyEnqueueCh)
+	s := starter.New(ds, unlimit, runnerVersion, notifyEnqueueCh)
 
-	manager := runner.New(ds, config.Config.RunnerVersion)
+	manager := runner.New(ds, runnerVersion)
 
 	return &myShoes{
 		ds:    ds,
@@ -100,6 +135,10 @@ func (m *myShoes) Run() error {
 		time.Sleep(time.Second)
 	}
 
+	eg.Go(func() error {
+		m.reloadOnSignal(ctx)
+		return nil
+	})
 	eg.Go(func() error {
 		if err := web.Serve(ctx, m.ds); err != nil {
 			logger.Logf(false, "failed to web.Serve: %+v", err)
@@ -107,6 +146,15 @@ func (m *myShoes) Run() error {
 		}
 		return nil
 	})
+	if len(config.Config.MetricsSnapshotIntervals) != 0 {
+		eg.Go(func() error {
+			if err := metric.StartSnapshot(ctx, m.ds, config.Config.MetricsSnapshotIntervals); err != nil {
+				logger.Logf(false, "failed to start snapshot of metrics: %+v", err)
+				return fmt.Errorf("failed to start snapshot of metrics: %w", err)
+			}
+			return nil
+		})
+	}
 	eg.Go(func() error {
 		if err := m.start.Loop(ctx); err != nil {
 			logger.Logf(false, "failed to starter manager: %+v", err)
@@ -128,3 +176,120 @@ func (m *myShoes) Run() error {
 
 	return nil
 }
+
+// reloadOnSignal reload config file and secrets when receive SIGHUP
+func (m *myShoes) reloadOnSignal(ctx context.Context) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	defer signal.Stop(ch)
+
+	for {
+		select {
+		case <-ch:
+			if err := m.reload(ctx); err != nil {
+				logger.Logf(false, "failed to reload config: %+v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reload apply changes of config file to runtime config
+func (m *myShoes) reload(ctx context.Context) error {
+	patch, restartKeys, err := config.Reload(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to reload config file: %w", err)
+	}
+	if err := starter.LoadScriptTemplateDirectory(); err != nil {
+		logger.Logf(false, "failed to reload script template directory: %+v", err)
+	}
+	for _, key := range restartKeys {
+		logger.Logf(false, "%s is changed in config file, but it is applied after restart", key)
+	}
+	if patch == (config.RuntimePatch{}) {
+		logger.Logf(false, "reload config file, runtime config is not changed")
+		return nil
+	}
+
+	rc, err := config.UpdateRuntime(patch, 0, func(rc config.Runtime) error {
+		return datastore.SaveRuntimeConfig(ctx, m.ds, rc, "sighup")
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update runtime config: %w", err)
+	}
+	logger.Logf(false, "reload config file, runtime config is updated (version: %d)", rc.Version)
+	return nil
+}
+
+// setFlag is flag of "-set KEY=VALUE", it can be specified multiple times
+type setFlag map[string]string
+
+func (s setFlag) String() string {
+	var kv []string
+	for k, v := range s {
+		kv = append(kv, k+"="+v)
+	}
+	return strings.Join(kv, ",")
+}
+
+func (s setFlag) Set(value string) error {
+	k, v, ok := strings.Cut(value, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("must be KEY=VALUE format: %s", value)
+	}
+	s[k] = v
+	return nil
+}
+
+// parseConfigFlags parse flags and load config file.
+// priority of config values is flags > environment > config file > default.
+func parseConfigFlags(fs *flag.FlagSet, args []string) error {
+	configPath := fs.String("config", os.Getenv(config.EnvConfigFile), "path of config file (YAML)")
+	values := setFlag{}
+	fs.Var(values, "set", "set config value as KEY=VALUE (e.g. -set max_connections_to_backend=100), can be specified multiple times")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	if *configPath != "" {
+		if err := config.LoadFile(*configPath); err != nil {
+			return fmt.Errorf("failed to load config file: %w", err)
+		}
+	}
+	if err := config.SetFlagValues(values); err != nil {
+		return fmt.Errorf("failed to set config from flags: %w", err)
+	}
+	if err := config.LoadSecrets(context.Background()); err != nil {
+		return fmt.Errorf("failed to load secrets: %w", err)
+	}
+	return nil
+}
+
+// runConfigCommand run "myshoes config" sub command, return exit code
+func runConfigCommand(args []string) int {
+	if len(args) == 0 || args[0] != "validate" {
+		fmt.Fprintln(os.Stderr, "usage: myshoes config validate [-config path] [-set KEY=VALUE]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("myshoes config validate", flag.ExitOnError)
+	if err := parseConfigFlags(fs, args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+	for _, v := range config.EffectiveValues() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", v.Key, v.Value, v.Source)
+	}
+	tw.Flush()
+
+	if err := config.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "configuration is invalid: %+v\n", err)
+		return 1
+	}
+	fmt.Println("configuration is valid")
+	return 0
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"crypto/x509"
+	"database/sql"
 	"encoding/json"
 	"encoding/pem"
 	"flag"
@@ -70,6 +71,12 @@ type addFlags struct {
 	runnerUser           string
 	runnerBaseDirectory  string
 	githubURL            string
+	runnerGroup          string
+	useJITConfig         bool
+	scriptTemplate       string
+	scriptTemplateDir    string
+	runnerOS             string
+	runnerArch           string
 	jsonOutput           bool
 }
 
@@ -97,6 +104,12 @@ func runAdd(args []string) error {
 	fs.StringVar(&flags.runnerUser, "runner-user", "runner", "Runner user (script generation mode)")
 	fs.StringVar(&flags.runnerBaseDirectory, "runner-base-directory", "/tmp", "Runner base directory (script generation mode)")
 	fs.StringVar(&flags.githubURL, "github-url", "", "GitHub Enterprise Server URL (script generation mode)")
+	fs.StringVar(&flags.runnerGroup, "runner-group", "", "Runner group name, only organization scope (script generation mode)")
+	fs.BoolVar(&flags.useJITConfig, "jit-config", false, "Use just-in-time runner configuration (script generation mode)")
+	fs.StringVar(&flags.scriptTemplate, "script-template", "", "Script template name in script template directory (script generation mode)")
+	fs.StringVar(&flags.scriptTemplateDir, "script-template-directory", os.Getenv("SCRIPT_TEMPLATE_DIRECTORY"), "Script template directory (script generation mode)")
+	fs.StringVar(&flags.runnerOS, "runner-os", "linux", "Runner OS (linux|windows) (script generation mode)")
+	fs.StringVar(&flags.runnerArch, "runner-arch", "x64", "Runner architecture (x64|arm64) (script generation mode)")
 	fs.BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
 
 	fs.Parse(args)
@@ -118,6 +131,12 @@ func runAdd(args []string) error {
 		if flags.githubPrivateKeyPath == "" {
 			return fmt.Errorf("--github-private-key-path is required for script generation mode")
 		}
+		if datastore.UnmarshalRunnerOS(flags.runnerOS) == "" {
+			return fmt.Errorf("invalid runner os: %s", flags.runnerOS)
+		}
+		if datastore.UnmarshalRunnerArch(flags.runnerArch) == "" {
+			return fmt.Errorf("invalid runner arch: %s", flags.runnerArch)
+		}
 	}
 
 	ctx := context.Background()
@@ -289,13 +308,33 @@ func generateSetupScript(ctx context.Context, flags *addFlags) (string, error) {
 
 	config.Config.RunnerUser = flags.runnerUser
 	config.Config.RunnerBaseDirectory = flags.runnerBaseDirectory
+	config.Config.RunnerBaseDirectoryWindows = flags.runnerBaseDirectory
+	config.Config.ScriptTemplateDirectory = flags.scriptTemplateDir
+	if err := starter.LoadScriptTemplateDirectory(); err != nil {
+		return "", fmt.Errorf("failed to load script template directory: %w", err)
+	}
 
 	if err := gh.InitializeCache(appID, keyBytes); err != nil {
 		return "", fmt.Errorf("failed to initialize GitHub client cache: %w", err)
 	}
 
+	target := datastore.Target{
+		Scope: flags.scope,
+		RunnerGroup: sql.NullString{
+			String: flags.runnerGroup,
+			Valid:  flags.runnerGroup != "",
+		},
+		UseJITConfig: flags.useJITConfig,
+		ScriptTemplate: sql.NullString{
+			String: flags.scriptTemplate,
+			Valid:  flags.scriptTemplate != "",
+		},
+		RunnerOS:   datastore.UnmarshalRunnerOS(flags.runnerOS),
+		RunnerArch: datastore.UnmarshalRunnerArch(flags.runnerArch),
+	}
+
 	s := starter.New(nil, nil, flags.runnerVersion, nil)
-	return s.GetSetupScript(ctx, flags.scope, flags.runnerName)
+	return s.GetSetupScript(ctx, target, flags.runnerName, parseLabels(flags.labels))
 }
 
 func parseLabels(labels string) []string {
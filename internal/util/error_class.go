@@ -0,0 +1,51 @@
+package util
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/go-github/v80/github"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// error classes for metrics label, these are bounded values
+const (
+	ErrorClassTimeout         = "timeout"
+	ErrorClassCanceled        = "canceled"
+	ErrorClassGitHubRateLimit = "github_rate_limit"
+	ErrorClassGitHub          = "github"
+	ErrorClassShoesProvider   = "shoes_provider"
+	ErrorClassUnknown         = "unknown"
+)
+
+// ErrorClass return class of error for metrics label
+func ErrorClass(err error) string {
+	var rateLimitErr *github.RateLimitError
+	var abuseRateLimitErr *github.AbuseRateLimitError
+	var githubErr *github.ErrorResponse
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.As(err, &rateLimitErr), errors.As(err, &abuseRateLimitErr):
+		return ErrorClassGitHubRateLimit
+	case errors.As(err, &githubErr):
+		return ErrorClassGitHub
+	}
+
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.DeadlineExceeded:
+			return ErrorClassTimeout
+		case codes.Canceled:
+			return ErrorClassCanceled
+		}
+		return ErrorClassShoesProvider
+	}
+	return ErrorClassUnknown
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-github/v80/github"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		input error
+		want  string
+	}{
+		{input: nil, want: ""},
+		{input: fmt.Errorf("failed to add instance: %w", context.DeadlineExceeded), want: ErrorClassTimeout},
+		{input: fmt.Errorf("failed to add instance: %w", status.Error(codes.Unavailable, "unavailable")), want: ErrorClassShoesProvider},
+		{input: fmt.Errorf("failed to add instance: %w", status.Error(codes.DeadlineExceeded, "deadline")), want: ErrorClassTimeout},
+		{input: fmt.Errorf("failed to get token: %w", &github.RateLimitError{}), want: ErrorClassGitHubRateLimit},
+		{input: fmt.Errorf("failed to get token: %w", &github.ErrorResponse{}), want: ErrorClassGitHub},
+		{input: errors.New("something wrong"), want: ErrorClassUnknown},
+	}
+
+	for _, test := range tests {
+		if got := ErrorClass(test.input); got != test.want {
+			t.Errorf("ErrorClass(%v) must be %q, but got %q", test.input, test.want, got)
+		}
+	}
+}
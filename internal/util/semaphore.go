@@ -0,0 +1,65 @@
+package util
+
+import (
+	"context"
+	"sync"
+)
+
+// Semaphore is counting semaphore that can be resized while in use
+type Semaphore struct {
+	mu      sync.Mutex
+	size    int64
+	cur     int64
+	changed chan struct{} // closed when released or resized
+}
+
+// NewSemaphore create Semaphore with size
+func NewSemaphore(size int64) *Semaphore {
+	return &Semaphore{
+		size:    size,
+		changed: make(chan struct{}),
+	}
+}
+
+// Acquire acquire a semaphore, blocking until resources are available or ctx is done
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	for {
+		s.mu.Lock()
+		if s.cur < s.size {
+			s.cur++
+			s.mu.Unlock()
+			return nil
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Release release a semaphore
+func (s *Semaphore) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cur--
+	s.notify()
+}
+
+// Resize change size of semaphore.
+// if size is smaller than in use, Acquire is blocked until enough semaphores are released.
+func (s *Semaphore) Resize(size int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.size = size
+	s.notify()
+}
+
+// notify wake up waiters, must be called with mu
+func (s *Semaphore) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Role is role of API client
+type Role string
+
+// Role values
+const (
+	// RoleRead can only read resources
+	RoleRead Role = "read"
+	// RoleAdmin can read and write resources
+	RoleAdmin Role = "admin"
+)
+
+// ParseRole parse role from string
+func ParseRole(in string) (Role, error) {
+	switch Role(in) {
+	case RoleRead, RoleAdmin:
+		return Role(in), nil
+	}
+	return "", fmt.Errorf("invalid role %q (must be %s or %s)", in, RoleRead, RoleAdmin)
+}
+
+// Allows return true if role has permission of required role
+func (r Role) Allows(required Role) bool {
+	switch r {
+	case RoleAdmin:
+		return true
+	case RoleRead:
+		return required == RoleRead
+	}
+	return false
+}
+
+// Principal is authenticated API client
+type Principal struct {
+	// Name is name of credential (e.g. name of token, key id, subject of JWT)
+	Name string
+	Role Role
+	// Method is method of authentication (e.g. token, hmac, oidc)
+	Method string
+	// ScopePrefixes restrict targets that principal can access, no restriction if empty
+	ScopePrefixes []string
+}
+
+// IsScoped return true if principal is restricted to some scopes
+func (p *Principal) IsScoped() bool {
+	return len(p.ScopePrefixes) != 0
+}
+
+// CanAccessScope return true if principal can access target of scope
+func (p *Principal) CanAccessScope(scope string) bool {
+	if !p.IsScoped() {
+		return true
+	}
+	for _, prefix := range p.ScopePrefixes {
+		if MatchScope(prefix, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchScope return true if owner of scope (e.g. "octocat" in "octocat/Hello-World") matches prefix.
+// prefix is owner name, or prefix of owner name with "*" suffix (e.g. "team-a-*").
+func MatchScope(prefix, scope string) bool {
+	owner, _, _ := strings.Cut(strings.ToLower(scope), "/")
+	prefix = strings.ToLower(prefix)
+	if p, ok := strings.CutSuffix(prefix, "*"); ok {
+		return p != "" && strings.HasPrefix(owner, p)
+	}
+	return owner == prefix
+}
+
+var (
+	// ErrNoCredentials is error for request that has no credentials for the authenticator
+	ErrNoCredentials = errors.New("no credentials")
+	// ErrInvalidCredentials is error for invalid credentials
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Authenticator authenticate a request
+type Authenticator interface {
+	// Authenticate return ErrNoCredentials if request has no credentials that the authenticator can handle.
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// Chain is list of Authenticator, try in order
+type Chain []Authenticator
+
+// Authenticate try authenticators until one can handle the request
+func (c Chain) Authenticate(r *http.Request) (*Principal, error) {
+	for _, a := range c {
+		p, err := a.Authenticate(r)
+		switch {
+		case errors.Is(err, ErrNoCredentials):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		return p, nil
+	}
+
+	return nil, ErrNoCredentials
+}
+
+type principalKey struct{}
+
+// WithPrincipal set principal to context
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext get principal from context, return nil if not authenticated
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
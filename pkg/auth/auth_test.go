@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRole_Allows(t *testing.T) {
+	tests := []struct {
+		role     Role
+		required Role
+		want     bool
+	}{
+		{role: RoleAdmin, required: RoleAdmin, want: true},
+		{role: RoleAdmin, required: RoleRead, want: true},
+		{role: RoleRead, required: RoleRead, want: true},
+		{role: RoleRead, required: RoleAdmin, want: false},
+		{role: "", required: RoleRead, want: false},
+	}
+
+	for _, test := range tests {
+		if got := test.role.Allows(test.required); got != test.want {
+			t.Errorf("%s allows %s: want %t, but got %t", test.role, test.required, test.want, got)
+		}
+	}
+}
+
+func TestChain_Authenticate(t *testing.T) {
+	chain := Chain{
+		NewTokenAuthenticator([]Credential{
+			{Name: "ci", Secret: "read-token", Role: RoleRead},
+			{Name: "ops", Secret: "admin-token", Role: RoleAdmin},
+		}),
+		NewHMACAuthenticator([]Credential{
+			{Name: "key1", Secret: "secret", Role: RoleAdmin},
+		}),
+	}
+
+	tests := []struct {
+		header map[string]string
+		want   *Principal
+		err    error
+	}{
+		{
+			header: map[string]string{"Authorization": "Bearer read-token"},
+			want:   &Principal{Name: "ci", Role: RoleRead, Method: "token"},
+		},
+		{
+			header: map[string]string{"Authorization": "bearer admin-token"},
+			want:   &Principal{Name: "ops", Role: RoleAdmin, Method: "token"},
+		},
+		{
+			header: map[string]string{"Authorization": "Bearer unknown"},
+			err:    ErrNoCredentials,
+		},
+		{
+			header: map[string]string{"Authorization": "Basic dXNlcjpwYXNz"},
+			err:    ErrNoCredentials,
+		},
+		{
+			header: map[string]string{HeaderKeyID: "key1", HeaderTimestamp: "0"},
+			err:    ErrInvalidCredentials,
+		},
+		{
+			header: map[string]string{},
+			err:    ErrNoCredentials,
+		},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/target", nil)
+		for k, v := range test.header {
+			r.Header.Set(k, v)
+		}
+
+		got, err := chain.Authenticate(r)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("want error %v, but got %+v (header: %v)", test.err, err, test.header)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of HMAC-signed request
+const (
+	HeaderKeyID     = "X-Myshoes-Key-Id"
+	HeaderTimestamp = "X-Myshoes-Timestamp"
+	HeaderSignature = "X-Myshoes-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+// MaxClockSkew is max difference between timestamp in request and server time
+var MaxClockSkew = 5 * time.Minute
+
+// MaxBodySize is max size of request body that is read to verify signature
+var MaxBodySize int64 = 10 << 20
+
+// HMACAuthenticator authenticate a request that signed by shared secret
+type HMACAuthenticator struct {
+	keys map[string]Credential // key: key id
+
+	now func() time.Time
+}
+
+// NewHMACAuthenticator create HMACAuthenticator, Name of Credential is key id
+func NewHMACAuthenticator(keys []Credential) *HMACAuthenticator {
+	m := make(map[string]Credential, len(keys))
+	for _, k := range keys {
+		m[k.Name] = k
+	}
+	return &HMACAuthenticator{keys: m, now: time.Now}
+}
+
+// Authenticate implement Authenticator
+func (a *HMACAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	keyID := r.Header.Get(HeaderKeyID)
+	if keyID == "" {
+		return nil, ErrNoCredentials
+	}
+	key, ok := a.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q: %w", keyID, ErrInvalidCredentials)
+	}
+
+	ts := r.Header.Get(HeaderTimestamp)
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp %q: %w", ts, ErrInvalidCredentials)
+	}
+	if skew := a.now().Sub(time.Unix(unix, 0)); skew > MaxClockSkew || skew < -MaxClockSkew {
+		return nil, fmt.Errorf("timestamp is too old or too new (skew: %s): %w", skew, ErrInvalidCredentials)
+	}
+
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = http.MaxBytesReader(nil, r.Body, MaxBodySize)
+	}
+	body, err := readBody(r)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, fmt.Errorf("body is larger than %d bytes: %w", MaxBodySize, ErrInvalidCredentials)
+		}
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+	want := sign(key.Secret, r.Method, r.URL.RequestURI(), ts, body)
+	got := strings.TrimPrefix(r.Header.Get(HeaderSignature), signaturePrefix)
+	if !hmac.Equal([]byte(want), []byte(got)) {
+		return nil, fmt.Errorf("signature mismatch (key id: %s): %w", keyID, ErrInvalidCredentials)
+	}
+
+	return &Principal{
+		Name:   key.Name,
+		Role:   key.Role,
+		Method: "hmac",
+	}, nil
+}
+
+// SignRequest set headers of HMAC signature to request
+func SignRequest(req *http.Request, keyID, secret string, now time.Time) error {
+	body, err := readBody(req)
+	if err != nil {
+		return fmt.Errorf("failed to read body: %w", err)
+	}
+
+	ts := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set(HeaderKeyID, keyID)
+	req.Header.Set(HeaderTimestamp, ts)
+	req.Header.Set(HeaderSignature, signaturePrefix+sign(secret, req.Method, req.URL.RequestURI(), ts, body))
+	return nil
+}
+
+// sign return HMAC-SHA256 of "<method>\n<request URI>\n<timestamp>\n<SHA-256 of body>"
+func sign(secret, method, uri, ts string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s", method, uri, ts, hex.EncodeToString(bodyHash[:]))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// readBody read body and restore it for next reader
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
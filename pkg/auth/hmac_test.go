@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHMACAuthenticator(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := NewHMACAuthenticator([]Credential{{Name: "key1", Secret: "secret", Role: RoleAdmin}})
+	a.now = func() time.Time { return now }
+
+	newSigned := func(keyID, secret string, signedAt time.Time) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "https://myshoes.example.com/target?x=1", bytes.NewBufferString(`{"scope": "octocat"}`))
+		if err != nil {
+			t.Fatalf("failed to create request: %+v", err)
+		}
+		if err := SignRequest(req, keyID, secret, signedAt); err != nil {
+			t.Fatalf("failed to sign request: %+v", err)
+		}
+
+		// convert to server side request
+		r := httptest.NewRequest(req.Method, req.URL.RequestURI(), req.Body)
+		r.Header = req.Header
+		return r
+	}
+
+	r := newSigned("key1", "secret", now.Add(-time.Minute))
+	p, err := a.Authenticate(r)
+	if err != nil {
+		t.Fatalf("failed to authenticate: %+v", err)
+	}
+	if p.Name != "key1" || p.Role != RoleAdmin {
+		t.Fatalf("invalid principal: %+v", p)
+	}
+	b, _ := io.ReadAll(r.Body)
+	if string(b) != `{"scope": "octocat"}` {
+		t.Fatalf("body must be restored, but got %q", string(b))
+	}
+
+	invalids := []*http.Request{
+		newSigned("key1", "other", now),
+		newSigned("key2", "secret", now),
+		newSigned("key1", "secret", now.Add(-time.Hour)),
+	}
+	tampered := newSigned("key1", "secret", now)
+	tampered.Body = io.NopCloser(bytes.NewBufferString(`{"scope": "other"}`))
+	invalids = append(invalids, tampered)
+
+	for i, r := range invalids {
+		if _, err := a.Authenticate(r); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("want ErrInvalidCredentials (case %d), but got %+v", i, err)
+		}
+	}
+
+	MaxBodySize = 8
+	t.Cleanup(func() { MaxBodySize = 10 << 20 })
+	if _, err := a.Authenticate(newSigned("key1", "secret", now)); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("too large body must be rejected, but got %+v", err)
+	}
+}
@@ -0,0 +1,246 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// DefaultRoleClaim is default name of claim that has role
+const DefaultRoleClaim = "myshoes_role"
+
+// ClockSkew is allowed difference of clock between OIDC provider and myshoes
+const ClockSkew = 1 * time.Minute
+
+// OIDCConfig is config of OIDCAuthenticator
+type OIDCConfig struct {
+	JWKSFile string
+	Issuer   string
+	Audience string
+	// RoleClaim is name of claim that has role, value is string or list of string
+	RoleClaim string
+}
+
+// OIDCAuthenticator authenticate a request by JWT that issued by OIDC provider
+type OIDCAuthenticator struct {
+	config OIDCConfig
+	keys   map[string]crypto.PublicKey // key: kid
+}
+
+// NewOIDCAuthenticator create OIDCAuthenticator with keys in JWKS file
+func NewOIDCAuthenticator(c OIDCConfig) (*OIDCAuthenticator, error) {
+	// tokens for other applications in same identity provider must not be accepted
+	if c.Issuer == "" || c.Audience == "" {
+		return nil, errors.New("issuer and audience are required")
+	}
+
+	b, err := os.ReadFile(c.JWKSFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+	keys, err := ParseJWKS(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS file: %w", err)
+	}
+	if c.RoleClaim == "" {
+		c.RoleClaim = DefaultRoleClaim
+	}
+
+	return &OIDCAuthenticator{config: c, keys: keys}, nil
+}
+
+// Authenticate implement Authenticator
+func (a *OIDCAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token, ok := BearerToken(r)
+	if !ok || strings.Count(token, ".") != 2 {
+		return nil, ErrNoCredentials
+	}
+
+	claims := jwt.MapClaims{}
+	// time based claims are verified by verifyTimeClaims with ClockSkew
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithoutClaimsValidation(),
+	)
+	if _, err := parser.ParseWithClaims(token, claims, a.keyFunc); err != nil {
+		return nil, fmt.Errorf("failed to verify JWT: %v: %w", err, ErrInvalidCredentials)
+	}
+	if err := verifyTimeClaims(claims, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to verify JWT: %v: %w", err, ErrInvalidCredentials)
+	}
+	if !claims.VerifyIssuer(a.config.Issuer, true) {
+		return nil, fmt.Errorf("issuer is not %s: %w", a.config.Issuer, ErrInvalidCredentials)
+	}
+	if !claims.VerifyAudience(a.config.Audience, true) {
+		return nil, fmt.Errorf("audience is not %s: %w", a.config.Audience, ErrInvalidCredentials)
+	}
+
+	role, err := roleFromClaim(claims[a.config.RoleClaim])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role from claim %s: %v: %w", a.config.RoleClaim, err, ErrInvalidCredentials)
+	}
+	sub, _ := claims["sub"].(string)
+
+	return &Principal{
+		Name:   sub,
+		Role:   role,
+		Method: "oidc",
+	}, nil
+}
+
+// verifyTimeClaims verify exp, nbf and iat in claims with ClockSkew, exp is required
+func verifyTimeClaims(claims jwt.MapClaims, now time.Time) error {
+	if _, ok := claims["exp"]; !ok {
+		return errors.New("exp is not set")
+	}
+	if !claims.VerifyExpiresAt(now.Add(-ClockSkew).Unix(), true) {
+		return errors.New("token is expired")
+	}
+	if !claims.VerifyNotBefore(now.Add(ClockSkew).Unix(), false) {
+		return errors.New("token is not valid yet")
+	}
+	if !claims.VerifyIssuedAt(now.Add(ClockSkew).Unix(), false) {
+		return errors.New("token is used before issued")
+	}
+	return nil
+}
+
+func (a *OIDCAuthenticator) keyFunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" && len(a.keys) == 1 {
+		for _, k := range a.keys {
+			return k, nil
+		}
+	}
+	k, ok := a.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("key is not found in JWKS (kid: %s)", kid)
+	}
+	return k, nil
+}
+
+// roleFromClaim return strongest role in claim
+func roleFromClaim(v interface{}) (Role, error) {
+	var values []string
+	switch t := v.(type) {
+	case string:
+		values = []string{t}
+	case []interface{}:
+		for _, e := range t {
+			if s, ok := e.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	var role Role
+	for _, s := range values {
+		r, err := ParseRole(s)
+		if err != nil {
+			continue
+		}
+		if r.Allows(role) || role == "" {
+			role = r
+		}
+	}
+	if role == "" {
+		return "", errors.New("valid role is not found")
+	}
+	return role, nil
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS parse public keys in JSON Web Key Set, key of return value is kid
+func ParseJWKS(in []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(in, &set); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JWKS: %w", err)
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key (kid: %s): %w", k.Kid, err)
+		}
+		keys[k.Kid] = pub
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no signing key in JWKS")
+	}
+
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode n: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode e: %w", err)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode x: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode y: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOIDCAuthenticator(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %+v", err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %+v", err)
+	}
+
+	jwks := fmt.Sprintf(`{"keys": [{"kid": "kid1", "kty": "RSA", "use": "sig", "n": %q, "e": %q}]}`,
+		base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	)
+	p := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(p, []byte(jwks), 0644); err != nil {
+		t.Fatalf("failed to write JWKS: %+v", err)
+	}
+
+	if _, err := NewOIDCAuthenticator(OIDCConfig{JWKSFile: p}); err == nil {
+		t.Fatalf("issuer and audience must be required")
+	}
+	a, err := NewOIDCAuthenticator(OIDCConfig{
+		JWKSFile: p,
+		Issuer:   "https://issuer.example.com",
+		Audience: "myshoes",
+	})
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %+v", err)
+	}
+
+	sign := func(k *rsa.PrivateKey, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "kid1"
+		s, err := token.SignedString(k)
+		if err != nil {
+			t.Fatalf("failed to sign token: %+v", err)
+		}
+		return s
+	}
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":          "https://issuer.example.com",
+			"aud":          "myshoes",
+			"sub":          "octocat",
+			"exp":          time.Now().Add(time.Hour).Unix(),
+			"myshoes_role": []interface{}{"read", "admin"},
+		}
+	}
+
+	tests := []struct {
+		token string
+		want  *Principal
+		err   error
+	}{
+		{
+			token: sign(key, valid()),
+			want:  &Principal{Name: "octocat", Role: RoleAdmin, Method: "oidc"},
+		},
+		{
+			token: sign(otherKey, valid()),
+			err:   ErrInvalidCredentials,
+		},
+		{
+			token: func() string { c := valid(); c["exp"] = time.Now().Add(-time.Hour).Unix(); return sign(key, c) }(),
+			err:   ErrInvalidCredentials,
+		},
+		{
+			// within clock skew
+			token: func() string { c := valid(); c["exp"] = time.Now().Add(-ClockSkew / 2).Unix(); return sign(key, c) }(),
+			want:  &Principal{Name: "octocat", Role: RoleAdmin, Method: "oidc"},
+		},
+		{
+			token: func() string { c := valid(); delete(c, "exp"); return sign(key, c) }(),
+			err:   ErrInvalidCredentials,
+		},
+		{
+			token: func() string { c := valid(); c["nbf"] = time.Now().Add(time.Hour).Unix(); return sign(key, c) }(),
+			err:   ErrInvalidCredentials,
+		},
+		{
+			token: func() string { c := valid(); c["iat"] = time.Now().Add(time.Hour).Unix(); return sign(key, c) }(),
+			err:   ErrInvalidCredentials,
+		},
+		{
+			token: func() string { c := valid(); delete(c, "aud"); return sign(key, c) }(),
+			err:   ErrInvalidCredentials,
+		},
+		{
+			token: func() string { c := valid(); delete(c, "iss"); return sign(key, c) }(),
+			err:   ErrInvalidCredentials,
+		},
+		{
+			token: func() string { c := valid(); c["aud"] = "other"; return sign(key, c) }(),
+			err:   ErrInvalidCredentials,
+		},
+		{
+			token: func() string { c := valid(); c["myshoes_role"] = "owner"; return sign(key, c) }(),
+			err:   ErrInvalidCredentials,
+		},
+		{
+			token: "static-token",
+			err:   ErrNoCredentials,
+		},
+	}
+
+	for i, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/target", nil)
+		r.Header.Set("Authorization", "Bearer "+test.token)
+
+		got, err := a.Authenticate(r)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("want error %v (case %d), but got %+v", test.err, i, err)
+		}
+		if diff := cmp.Diff(test.want, got); test.want != nil && diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ScopedTokenPrefix is prefix of scoped token, for distinguishing from other tokens
+const ScopedTokenPrefix = "myshoes_"
+
+// ErrTokenNotFound is error for token that is not found in store
+var ErrTokenNotFound = errors.New("token is not found")
+
+// ScopedTokenLookup find a principal by hash of token, return ErrTokenNotFound if not found
+type ScopedTokenLookup func(ctx context.Context, tokenHash string) (*Principal, error)
+
+// ScopedTokenAuthenticator authenticate a request by token that stored in datastore
+type ScopedTokenAuthenticator struct {
+	lookup ScopedTokenLookup
+}
+
+// NewScopedTokenAuthenticator create ScopedTokenAuthenticator
+func NewScopedTokenAuthenticator(lookup ScopedTokenLookup) *ScopedTokenAuthenticator {
+	return &ScopedTokenAuthenticator{lookup: lookup}
+}
+
+// Authenticate implement Authenticator
+func (a *ScopedTokenAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token, ok := BearerToken(r)
+	if !ok || !strings.HasPrefix(token, ScopedTokenPrefix) {
+		return nil, ErrNoCredentials
+	}
+
+	p, err := a.lookup(r.Context(), HashToken(token))
+	switch {
+	case errors.Is(err, ErrTokenNotFound):
+		return nil, fmt.Errorf("token is not found or revoked: %w", ErrInvalidCredentials)
+	case err != nil:
+		return nil, fmt.Errorf("failed to lookup token: %w", err)
+	}
+	return p, nil
+}
+
+// GenerateToken generate a new scoped token
+func GenerateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to read random: %w", err)
+	}
+	return ScopedTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken return hex of SHA-256 of token
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
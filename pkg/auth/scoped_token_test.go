@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMatchScope(t *testing.T) {
+	tests := []struct {
+		prefix string
+		scope  string
+		want   bool
+	}{
+		{prefix: "octocat", scope: "octocat", want: true},
+		{prefix: "octocat", scope: "octocat/Hello-World", want: true},
+		{prefix: "OctoCat", scope: "octocat/Hello-World", want: true},
+		{prefix: "octocat", scope: "octocat-other/Hello-World", want: false},
+		{prefix: "team-a-*", scope: "team-a-backend/api", want: true},
+		{prefix: "team-a-*", scope: "team-b-backend", want: false},
+		{prefix: "*", scope: "octocat", want: false},
+	}
+
+	for _, test := range tests {
+		if got := MatchScope(test.prefix, test.scope); got != test.want {
+			t.Errorf("MatchScope(%q, %q): want %t, but got %t", test.prefix, test.scope, test.want, got)
+		}
+	}
+}
+
+func TestScopedTokenAuthenticator(t *testing.T) {
+	token, err := GenerateToken()
+	if err != nil {
+		t.Fatalf("failed to generate token: %+v", err)
+	}
+	if !strings.HasPrefix(token, ScopedTokenPrefix) {
+		t.Fatalf("token must have prefix %s, but got %s", ScopedTokenPrefix, token)
+	}
+
+	a := NewScopedTokenAuthenticator(func(ctx context.Context, tokenHash string) (*Principal, error) {
+		if tokenHash != HashToken(token) {
+			return nil, ErrTokenNotFound
+		}
+		return &Principal{Name: "team-a", Role: RoleAdmin, Method: "scoped_token", ScopePrefixes: []string{"team-a-*"}}, nil
+	})
+
+	tests := []struct {
+		token string
+		err   error
+	}{
+		{token: token},
+		{token: ScopedTokenPrefix + "revoked", err: ErrInvalidCredentials},
+		{token: "static-token", err: ErrNoCredentials},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/target", nil)
+		r.Header.Set("Authorization", "Bearer "+test.token)
+
+		p, err := a.Authenticate(r)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("want error %v, but got %+v", test.err, err)
+		}
+		if err != nil {
+			continue
+		}
+		if !p.CanAccessScope("team-a-web/site") || p.CanAccessScope("team-b/site") {
+			t.Errorf("principal must access only team-a-*, but got %+v", p)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Credential is a named secret with role
+type Credential struct {
+	Name   string
+	Secret string
+	Role   Role
+}
+
+// TokenAuthenticator authenticate a request by static bearer token
+type TokenAuthenticator struct {
+	tokens []Credential
+}
+
+// NewTokenAuthenticator create TokenAuthenticator
+func NewTokenAuthenticator(tokens []Credential) *TokenAuthenticator {
+	return &TokenAuthenticator{tokens: tokens}
+}
+
+// Authenticate implement Authenticator.
+// return ErrNoCredentials if bearer token is not a static token, it may be JWT.
+func (a *TokenAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	token, ok := BearerToken(r)
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+
+	// compare digest to avoid leaking length of token
+	got := sha256.Sum256([]byte(token))
+	var found *Credential
+	for i, c := range a.tokens {
+		want := sha256.Sum256([]byte(c.Secret))
+		if subtle.ConstantTimeCompare(got[:], want[:]) == 1 {
+			found = &a.tokens[i]
+		}
+	}
+	if found == nil {
+		return nil, ErrNoCredentials
+	}
+
+	return &Principal{
+		Name:   found.Name,
+		Role:   found.Role,
+		Method: "token",
+	}, nil
+}
+
+// BearerToken get token from Authorization header
+func BearerToken(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+	scheme, token, ok := strings.Cut(h, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"crypto/rsa"
 	"strings"
+	"time"
 )
 
 // Config is config value
@@ -19,10 +20,31 @@ type Conf struct {
 	RunnerUser            string
 	RunnerBaseDirectory   string
 
+	RunnerBaseDirectoryWindows string
+	ScriptTemplateDirectory    string
+
+	RunnerMirrorDirectory string
+	RunnerMirrorURL       string
+	RunnerMirrorOffline   bool // not download runner binaries from github.com if true
+
+	// Debug, Strict, MaxConnectionsToBackend, MaxConcurrencyDeleting and RunnerVersion are initial value.
+	// Please use GetRuntime() for current value.
 	Debug           bool
 	Strict          bool // check to registered runner before delete job
 	ModeWebhookType ModeWebhookType
 
+	LogFormat string // plain, text or json
+
+	// OTLPEndpoint is URL of OTLP (HTTP) collector for tracing, tracing is disabled if empty
+	OTLPEndpoint string
+
+	// PriceTable is price of runner-minutes for estimating cost
+	PriceTable PriceTable
+
+	// MetricsSnapshotIntervals is refresh interval of scrapers in background, key is name of scraper or *.
+	// scrapers that are not in this are scraped on each request of /metrics.
+	MetricsSnapshotIntervals map[string]time.Duration
+
 	MaxConnectionsToBackend int64
 	MaxConcurrencyDeleting  int64
 
@@ -31,6 +53,33 @@ type Conf struct {
 
 	DockerHubCredential     DockerHubCredential
 	ProvideDockerHubMetrics bool
+
+	APIAuth APIAuth
+
+	Notify Notify
+}
+
+// APIAuth is type of config value for authentication of REST API
+type APIAuth struct {
+	Tokens   []APICredential // static bearer tokens
+	HMACKeys []APICredential // Name is key id
+
+	OIDCJWKSFile  string
+	OIDCIssuer    string
+	OIDCAudience  string
+	OIDCRoleClaim string
+}
+
+// APICredential is type of config value
+type APICredential struct {
+	Name   string
+	Role   string // admin or read
+	Secret string
+}
+
+// Enabled return true if any authentication is configured
+func (a APIAuth) Enabled() bool {
+	return len(a.Tokens) != 0 || len(a.HMACKeys) != 0 || a.OIDCJWKSFile != ""
 }
 
 // DockerHubCredential is type of config value
@@ -49,30 +98,58 @@ type GitHubApp struct {
 
 // Config Environment keys
 const (
-	EnvGitHubAppID               = "GITHUB_APP_ID"
-	EnvGitHubAppSecret           = "GITHUB_APP_SECRET"
-	EnvGitHubAppPrivateKeyBase64 = "GITHUB_PRIVATE_KEY_BASE64"
-	EnvMySQLHost                 = "MYSQL_HOST"
-	EnvMySQLPort                 = "MYSQL_PORT"
-	EnvMySQLUser                 = "MYSQL_USER"
-	EnvMySQLPassword             = "MYSQL_PASSWORD"
-	EnvMySQLDatabase             = "MYSQL_DATABASE"
-	EnvMySQLURL                  = "MYSQL_URL"
-	EnvPort                      = "PORT"
-	EnvShoesPluginPath           = "PLUGIN"
-	EnvShoesPluginOutputPath     = "PLUGIN_OUTPUT"
-	EnvRunnerUser                = "RUNNER_USER"
-	EnvRunnerBaseDirectory       = "RUNNER_BASE_DIRECTORY"
-	EnvDebug                     = "DEBUG"
-	EnvStrict                    = "STRICT"
-	EnvModeWebhookType           = "MODE_WEBHOOK_TYPE"
-	EnvMaxConnectionsToBackend   = "MAX_CONNECTIONS_TO_BACKEND"
-	EnvMaxConcurrencyDeleting    = "MAX_CONCURRENCY_DELETING"
-	EnvGitHubURL                 = "GITHUB_URL"
-	EnvRunnerVersion             = "RUNNER_VERSION"
-	EnvDockerHubUsername         = "DOCKER_HUB_USERNAME"
-	EnvDockerHubPassword         = "DOCKER_HUB_PASSWORD"
-	EnvProvideDockerHubMetrics   = "PROVIDE_DOCKER_HUB_METRICS"
+	EnvGitHubAppID                = "GITHUB_APP_ID"
+	EnvGitHubAppSecret            = "GITHUB_APP_SECRET"
+	EnvGitHubAppPrivateKeyBase64  = "GITHUB_PRIVATE_KEY_BASE64"
+	EnvMySQLHost                  = "MYSQL_HOST"
+	EnvMySQLPort                  = "MYSQL_PORT"
+	EnvMySQLUser                  = "MYSQL_USER"
+	EnvMySQLPassword              = "MYSQL_PASSWORD"
+	EnvMySQLDatabase              = "MYSQL_DATABASE"
+	EnvMySQLURL                   = "MYSQL_URL"
+	EnvPort                       = "PORT"
+	EnvShoesPluginPath            = "PLUGIN"
+	EnvShoesPluginOutputPath      = "PLUGIN_OUTPUT"
+	EnvRunnerUser                 = "RUNNER_USER"
+	EnvRunnerBaseDirectory        = "RUNNER_BASE_DIRECTORY"
+	EnvRunnerBaseDirectoryWindows = "RUNNER_BASE_DIRECTORY_WINDOWS"
+	EnvScriptTemplateDirectory    = "SCRIPT_TEMPLATE_DIRECTORY"
+	EnvRunnerMirrorDirectory      = "RUNNER_MIRROR_DIRECTORY"
+	EnvRunnerMirrorURL            = "RUNNER_MIRROR_URL"
+	EnvRunnerMirrorOffline        = "RUNNER_MIRROR_OFFLINE"
+	EnvDebug                      = "DEBUG"
+	EnvStrict                     = "STRICT"
+	EnvModeWebhookType            = "MODE_WEBHOOK_TYPE"
+	EnvMaxConnectionsToBackend    = "MAX_CONNECTIONS_TO_BACKEND"
+	EnvMaxConcurrencyDeleting     = "MAX_CONCURRENCY_DELETING"
+	EnvGitHubURL                  = "GITHUB_URL"
+	EnvRunnerVersion              = "RUNNER_VERSION"
+	EnvDockerHubUsername          = "DOCKER_HUB_USERNAME"
+	EnvDockerHubPassword          = "DOCKER_HUB_PASSWORD"
+	EnvProvideDockerHubMetrics    = "PROVIDE_DOCKER_HUB_METRICS"
+	EnvAPITokens                  = "API_TOKENS"
+	EnvAPIHMACKeys                = "API_HMAC_KEYS"
+	EnvAPIOIDCJWKSFile            = "API_OIDC_JWKS_FILE"
+	EnvAPIOIDCIssuer              = "API_OIDC_ISSUER"
+	EnvAPIOIDCAudience            = "API_OIDC_AUDIENCE"
+	EnvAPIOIDCRoleClaim           = "API_OIDC_ROLE_CLAIM"
+	EnvGitHubAppPrivateKeyFile    = "GITHUB_PRIVATE_KEY_FILE"
+	EnvWebhookSecretGracePeriod   = "WEBHOOK_SECRET_GRACE_PERIOD"
+	EnvSecretProvider             = "SECRET_PROVIDER"
+	EnvSecretFileDirectory        = "SECRET_FILE_DIRECTORY"
+	EnvVaultAddress               = "VAULT_ADDR"
+	EnvVaultToken                 = "VAULT_TOKEN"
+	EnvVaultKVMount               = "VAULT_KV_MOUNT"
+	EnvVaultKVPath                = "VAULT_KV_PATH"
+	EnvLogFormat                  = "LOG_FORMAT"
+	EnvOTLPEndpoint               = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	EnvCostPriceTable             = "COST_PRICE_TABLE"
+	EnvMetricsSnapshotIntervals   = "METRICS_SNAPSHOT_INTERVALS"
+	EnvNotifyWebhookURLs          = "NOTIFY_WEBHOOK_URLS"
+	EnvNotifySlackWebhookURLs     = "NOTIFY_SLACK_WEBHOOK_URLS"
+	EnvNotifyDedupWindow          = "NOTIFY_DEDUP_WINDOW"
+	EnvNotifyRateLimit            = "NOTIFY_RATE_LIMIT"
+	EnvNotifyJobRetryThreshold    = "NOTIFY_JOB_RETRY_THRESHOLD"
 )
 
 // ModeWebhookType is type value for GitHub webhook
@@ -0,0 +1,469 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvConfigFile is environment key of path to config file
+const EnvConfigFile = "MYSHOES_CONFIG"
+
+// Source is where a config value comes from
+type Source string
+
+// Source values, a former has priority
+const (
+	SourceFlag    Source = "flag"
+	SourceEnv     Source = "env"
+	SourceFile    Source = "file"
+	SourceSecret  Source = "secret_provider"
+	SourceDefault Source = "default"
+)
+
+type settingKind int
+
+const (
+	kindString settingKind = iota
+	kindInt
+	kindBool
+	kindDuration
+)
+
+// setting is schema of a config value
+type setting struct {
+	key        string
+	kind       settingKind
+	secret     bool // redacted in output
+	reloadable bool // applied by Reload without restart
+}
+
+// settings is schema of config file, key is same as environment
+var settings = []setting{
+	{key: EnvGitHubAppID, kind: kindInt},
+	{key: EnvGitHubAppSecret, secret: true},
+	{key: EnvGitHubAppPrivateKeyBase64, secret: true},
+	{key: EnvGitHubURL},
+	{key: EnvMySQLURL, secret: true},
+	{key: EnvMySQLHost},
+	{key: EnvMySQLPort, kind: kindInt},
+	{key: EnvMySQLUser},
+	{key: EnvMySQLPassword, secret: true},
+	{key: EnvMySQLDatabase},
+	{key: EnvPort, kind: kindInt},
+	{key: EnvShoesPluginPath},
+	{key: EnvShoesPluginOutputPath},
+	{key: EnvRunnerUser},
+	{key: EnvRunnerBaseDirectory},
+	{key: EnvRunnerBaseDirectoryWindows},
+	{key: EnvScriptTemplateDirectory},
+	{key: EnvRunnerMirrorDirectory},
+	{key: EnvRunnerMirrorURL},
+	{key: EnvRunnerMirrorOffline, kind: kindBool},
+	{key: EnvDebug, kind: kindBool, reloadable: true},
+	{key: EnvStrict, kind: kindBool, reloadable: true},
+	{key: EnvModeWebhookType},
+	{key: EnvMaxConnectionsToBackend, kind: kindInt, reloadable: true},
+	{key: EnvMaxConcurrencyDeleting, kind: kindInt, reloadable: true},
+	{key: EnvRunnerVersion, reloadable: true},
+	{key: EnvDockerHubUsername},
+	{key: EnvDockerHubPassword, secret: true},
+	{key: EnvProvideDockerHubMetrics, kind: kindBool},
+	{key: EnvAPITokens, secret: true},
+	{key: EnvAPIHMACKeys, secret: true},
+	{key: EnvAPIOIDCJWKSFile},
+	{key: EnvAPIOIDCIssuer},
+	{key: EnvAPIOIDCAudience},
+	{key: EnvAPIOIDCRoleClaim},
+	{key: EnvGitHubAppPrivateKeyFile},
+	{key: EnvWebhookSecretGracePeriod, kind: kindDuration},
+	{key: EnvSecretProvider},
+	{key: EnvSecretFileDirectory},
+	{key: EnvVaultAddress},
+	{key: EnvVaultToken, secret: true},
+	{key: EnvVaultKVMount},
+	{key: EnvVaultKVPath},
+	{key: EnvLogFormat},
+	{key: EnvOTLPEndpoint},
+	{key: EnvCostPriceTable},
+	{key: EnvMetricsSnapshotIntervals},
+	{key: EnvNotifyWebhookURLs, secret: true},
+	{key: EnvNotifySlackWebhookURLs, secret: true},
+	{key: EnvNotifyDedupWindow, kind: kindDuration},
+	{key: EnvNotifyRateLimit, kind: kindInt},
+	{key: EnvNotifyJobRetryThreshold, kind: kindInt},
+}
+
+var (
+	sourceMu   sync.RWMutex
+	flagValues = map[string]string{}
+	fileValues = map[string]string{}
+	filePath   string
+	// providerValues is secrets from secret provider
+	providerValues = map[string]string{}
+)
+
+// SetFlagValues set values from command line flags, key is name of environment (e.g. PORT)
+func SetFlagValues(values map[string]string) error {
+	normalized := map[string]string{}
+	for k, v := range values {
+		key := strings.ToUpper(k)
+		if _, ok := findSetting(key); !ok {
+			return fmt.Errorf("unknown config key %q", k)
+		}
+		normalized[key] = v
+	}
+
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	flagValues = normalized
+	return nil
+}
+
+// LoadFile load config file in YAML.
+// keys are name of environment in lower case (e.g. max_connections_to_backend), nested keys are joined with "_".
+func LoadFile(path string) error {
+	values, err := readFile(path)
+	if err != nil {
+		return err
+	}
+
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	fileValues = values
+	filePath = path
+	return nil
+}
+
+func readFile(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file (path: %s): %w", path, err)
+	}
+
+	flatten := map[string]interface{}{}
+	flattenMap("", raw, flatten)
+
+	values := map[string]string{}
+	var errs []error
+	for k, v := range flatten {
+		key := strings.ToUpper(k)
+		s, ok := findSetting(key)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: unknown key", k))
+			continue
+		}
+		value, err := s.format(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", k, err))
+			continue
+		}
+		values[key] = value
+	}
+	if len(errs) != 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+		return nil, fmt.Errorf("invalid config file (path: %s): %w", path, errors.Join(errs...))
+	}
+
+	return values, nil
+}
+
+func flattenMap(prefix string, in map[string]interface{}, out map[string]interface{}) {
+	for k, v := range in {
+		key := k
+		if prefix != "" {
+			key = prefix + "_" + k
+		}
+		if child, ok := v.(map[string]interface{}); ok {
+			flattenMap(key, child, out)
+			continue
+		}
+		out[key] = v
+	}
+}
+
+// format convert a value in config file to string that is same format as environment
+func (s setting) format(v interface{}) (string, error) {
+	switch s.kind {
+	case kindInt:
+		switch n := v.(type) {
+		case int:
+			return strconv.Itoa(n), nil
+		case string:
+			if _, err := strconv.ParseInt(n, 10, 64); err == nil {
+				return n, nil
+			}
+		}
+		return "", fmt.Errorf("must be integer, but got %v", v)
+	case kindBool:
+		if b, ok := v.(bool); ok {
+			return strconv.FormatBool(b), nil
+		}
+		return "", fmt.Errorf("must be boolean, but got %v", v)
+	case kindDuration:
+		if d, ok := v.(string); ok {
+			if _, err := time.ParseDuration(d); err == nil {
+				return d, nil
+			}
+		}
+		return "", fmt.Errorf("must be duration (e.g. 1h), but got %v", v)
+	default:
+		switch v.(type) {
+		case string, int, float64, bool:
+			return fmt.Sprint(v), nil
+		}
+		return "", fmt.Errorf("must be string, but got %v", v)
+	}
+}
+
+// findSetting return schema of key, KEY_FILE of secret is also valid as path of file that has secret
+func findSetting(key string) (setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+		if s.secret && s.key+fileSuffix == key {
+			return setting{key: key}, true
+		}
+	}
+	return setting{}, false
+}
+
+// fileSuffix is suffix of key that has path of file for secret (e.g. GITHUB_APP_SECRET_FILE)
+const fileSuffix = "_FILE"
+
+// resolved is a config value before reading file of KEY_FILE
+type resolved struct {
+	value   string
+	fileRef string // path of file that has value
+	source  Source
+	ok      bool
+}
+
+// resolve return value of key by priority of flag > env > config file > secret provider.
+// in each source, KEY has priority over KEY_FILE.
+func resolve(key string) resolved {
+	s, _ := findSetting(key)
+
+	sourceMu.RLock()
+	defer sourceMu.RUnlock()
+
+	for _, src := range []struct {
+		source Source
+		get    func(string) (string, bool)
+	}{
+		{source: SourceFlag, get: func(k string) (string, bool) { v, ok := flagValues[k]; return v, ok }},
+		{source: SourceEnv, get: os.LookupEnv},
+		{source: SourceFile, get: func(k string) (string, bool) { v, ok := fileValues[k]; return v, ok }},
+	} {
+		if v, ok := src.get(key); ok {
+			return resolved{value: v, source: src.source, ok: true}
+		}
+		if !s.secret {
+			continue
+		}
+		if p, ok := src.get(key + fileSuffix); ok {
+			return resolved{fileRef: p, source: src.source, ok: true}
+		}
+	}
+	if v, ok := providerValues[key]; ok {
+		return resolved{value: v, source: SourceSecret, ok: true}
+	}
+	return resolved{source: SourceDefault}
+}
+
+// lookup return value of key, panic if failed to read file of KEY_FILE
+func lookup(key string) (string, Source, bool) {
+	r := resolve(key)
+	if r.fileRef == "" {
+		return r.value, r.source, r.ok
+	}
+
+	b, err := os.ReadFile(r.fileRef)
+	if err != nil {
+		log.Panicf("failed to read %s%s: %+v", key, fileSuffix, err)
+	}
+	return strings.TrimRight(string(b), "\r\n"), r.source, true
+}
+
+// getenv is same as os.Getenv, but also read flags and config file
+func getenv(key string) string {
+	v, _, _ := lookup(key)
+	return v
+}
+
+// lookupEnv is same as os.LookupEnv, but also read flags and config file
+func lookupEnv(key string) (string, bool) {
+	v, _, ok := lookup(key)
+	return v, ok
+}
+
+// EffectiveValue is a config value and where it comes from
+type EffectiveValue struct {
+	Key    string
+	Value  string
+	Source Source
+}
+
+// EffectiveValues return config values that are set, secret values are redacted
+func EffectiveValues() []EffectiveValue {
+	var values []EffectiveValue
+	for _, s := range settings {
+		r := resolve(s.key)
+		switch {
+		case !r.ok:
+			continue
+		case r.fileRef != "":
+			values = append(values, EffectiveValue{Key: s.key + fileSuffix, Value: r.fileRef, Source: r.source})
+			continue
+		case s.secret && r.value != "":
+			r.value = "<redacted>"
+		}
+		values = append(values, EffectiveValue{Key: s.key, Value: r.value, Source: r.source})
+	}
+	return values
+}
+
+// Validate check config values from flags, environment and config file.
+// plugin binary is not fetched.
+func Validate() (err error) {
+	defer func() {
+		// Load functions panic if a value is invalid
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	c := LoadWithDefault()
+	LoadGitHubApps()
+	LoadMySQLURL()
+	if getenv(EnvShoesPluginPath) == "" {
+		return fmt.Errorf("%s must be set", EnvShoesPluginPath)
+	}
+	return c.InitialRuntime().Validate()
+}
+
+// Reload re-read config file and secrets, and return patch of Runtime for reloadable settings that are changed.
+// changes of other settings need to restart, these are returned as restartKeys.
+// a changed GITHUB_APP_SECRET is rotated, previous secret is valid during WEBHOOK_SECRET_GRACE_PERIOD.
+func Reload(ctx context.Context) (patch RuntimePatch, restartKeys []string, err error) {
+	defer func() {
+		// lookup panic if failed to read file of KEY_FILE
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	sourceMu.RLock()
+	path := filePath
+	sourceMu.RUnlock()
+
+	before := map[string]string{}
+	for _, s := range settings {
+		before[s.key] = getenv(s.key)
+	}
+	if path != "" {
+		if err := LoadFile(path); err != nil {
+			return RuntimePatch{}, nil, err
+		}
+	}
+	if err := LoadSecrets(ctx); err != nil {
+		return RuntimePatch{}, nil, err
+	}
+
+	for _, s := range settings {
+		after := getenv(s.key)
+		if s.key == EnvGitHubAppSecret {
+			// compare with current secret, content of GITHUB_APP_SECRET_FILE may be already changed
+			if current := WebhookSecrets(time.Now())[0]; after != "" && after != string(current) {
+				RotateWebhookSecret([]byte(after), time.Now(), webhookSecretGracePeriod())
+			}
+			continue
+		}
+		if after == before[s.key] {
+			continue
+		}
+		if !s.reloadable {
+			restartKeys = append(restartKeys, s.key)
+			continue
+		}
+		if err := setPatch(&patch, s.key, after); err != nil {
+			return RuntimePatch{}, nil, fmt.Errorf("invalid value of %s: %w", s.key, err)
+		}
+	}
+	return patch, restartKeys, nil
+}
+
+// ExplicitRuntimePatch return patch of Runtime for reloadable settings that are set by flags, environment or config file.
+// it is applied over runtime config in datastore, so explicit values have priority.
+func ExplicitRuntimePatch() (patch RuntimePatch, keys []string, err error) {
+	for _, s := range settings {
+		if !s.reloadable {
+			continue
+		}
+		r := resolve(s.key)
+		if !r.ok || r.source == SourceSecret {
+			continue
+		}
+		if err := setPatch(&patch, s.key, getenv(s.key)); err != nil {
+			return RuntimePatch{}, nil, fmt.Errorf("invalid value of %s: %w", s.key, err)
+		}
+		keys = append(keys, s.key)
+	}
+	return patch, keys, nil
+}
+
+func setPatch(patch *RuntimePatch, key, value string) error {
+	d := DefaultRuntime()
+	switch key {
+	case EnvDebug:
+		logLevel := LogLevelInfo
+		if value == "true" {
+			logLevel = LogLevelDebug
+		}
+		patch.LogLevel = &logLevel
+	case EnvStrict:
+		strict := value != "false"
+		patch.Strict = &strict
+	case EnvMaxConnectionsToBackend:
+		n, err := parseInt64OrDefault(value, d.MaxConnectionsToBackend)
+		if err != nil {
+			return err
+		}
+		patch.MaxConnectionsToBackend = &n
+	case EnvMaxConcurrencyDeleting:
+		n, err := parseInt64OrDefault(value, d.MaxConcurrencyDeleting)
+		if err != nil {
+			return err
+		}
+		patch.MaxConcurrencyDeleting = &n
+	case EnvRunnerVersion:
+		runnerVersion := value
+		if runnerVersion == "" {
+			runnerVersion = d.RunnerVersion
+		}
+		patch.RunnerVersion = &runnerVersion
+	}
+	return nil
+}
+
+func parseInt64OrDefault(value string, defaultValue int64) (int64, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "myshoes.yaml")
+	writeFile := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write config file: %+v", err)
+		}
+	}
+	defer func() {
+		fileValues, flagValues, filePath = map[string]string{}, map[string]string{}, ""
+	}()
+
+	writeFile(`
+github:
+  url: https://github.example.com
+port: 9090
+runner_user: ubuntu
+max_connections_to_backend: 10
+debug: true
+`)
+	if err := LoadFile(path); err != nil {
+		t.Fatalf("failed to load config file: %+v", err)
+	}
+	t.Setenv(EnvRunnerUser, "runner-from-env")
+	if err := SetFlagValues(map[string]string{"port": "8080"}); err != nil {
+		t.Fatalf("failed to set flag values: %+v", err)
+	}
+
+	tests := []struct {
+		key        string
+		wantValue  string
+		wantSource Source
+	}{
+		{key: EnvGitHubURL, wantValue: "https://github.example.com", wantSource: SourceFile},
+		{key: EnvPort, wantValue: "8080", wantSource: SourceFlag},
+		{key: EnvRunnerUser, wantValue: "runner-from-env", wantSource: SourceEnv},
+		{key: EnvDebug, wantValue: "true", wantSource: SourceFile},
+		{key: EnvStrict, wantValue: "", wantSource: SourceDefault},
+	}
+	for _, test := range tests {
+		got, source, _ := lookup(test.key)
+		if got != test.wantValue || source != test.wantSource {
+			t.Errorf("%s must be %q from %s, but got %q from %s", test.key, test.wantValue, test.wantSource, got, source)
+		}
+	}
+
+	writeFile(`
+github:
+  url: https://github.example.com
+port: 9090
+runner_user: ubuntu
+max_connections_to_backend: 20
+runner_base_directory: /var/lib/myshoes
+`)
+	patch, restartKeys, err := Reload(context.Background())
+	if err != nil {
+		t.Fatalf("failed to reload: %+v", err)
+	}
+	if patch.MaxConnectionsToBackend == nil || *patch.MaxConnectionsToBackend != 20 {
+		t.Errorf("max_connections_to_backend must be reloaded, but got %v", patch.MaxConnectionsToBackend)
+	}
+	if patch.LogLevel == nil || *patch.LogLevel != LogLevelInfo {
+		t.Errorf("log_level must be info after debug is removed, but got %v", patch.LogLevel)
+	}
+	if patch.Strict != nil || patch.RunnerVersion != nil {
+		t.Errorf("not changed values must not be in patch: %+v", patch)
+	}
+	if len(restartKeys) != 1 || restartKeys[0] != EnvRunnerBaseDirectory {
+		t.Errorf("runner_base_directory needs restart, but got %v", restartKeys)
+	}
+
+	writeFile("port: eighty\nunknown: 1\n")
+	if err := LoadFile(path); err == nil {
+		t.Errorf("must be error for invalid config file")
+	}
+}
@@ -23,11 +23,16 @@ func Load() {
 
 	ga := LoadGitHubApps()
 	c.GitHub = *ga
+	SetWebhookSecret(ga.AppSecret)
 
 	pluginPath := LoadPluginPath()
 	c.ShoesPluginPath = pluginPath
 
 	Config = c
+
+	if err := SetRuntime(c.InitialRuntime()); err != nil {
+		log.Panicf("failed to set runtime config: %+v", err)
+	}
 }
 
 // LoadWithDefault load only value that has default value
@@ -35,8 +40,8 @@ func LoadWithDefault() Conf {
 	var c Conf
 
 	p := "8080"
-	if os.Getenv(EnvPort) != "" {
-		p = os.Getenv(EnvPort)
+	if getenv(EnvPort) != "" {
+		p = getenv(EnvPort)
 	}
 	pp, err := strconv.Atoi(p)
 	if err != nil {
@@ -45,33 +50,90 @@ func LoadWithDefault() Conf {
 	c.Port = pp
 
 	runnerUser := "runner"
-	if os.Getenv(EnvRunnerUser) != "" {
-		runnerUser = os.Getenv(EnvRunnerUser)
+	if getenv(EnvRunnerUser) != "" {
+		runnerUser = getenv(EnvRunnerUser)
 	}
 	c.RunnerUser = runnerUser
 
 	c.RunnerBaseDirectory = "/tmp"
-	if os.Getenv(EnvRunnerBaseDirectory) != "" {
-		c.RunnerBaseDirectory = os.Getenv(EnvRunnerBaseDirectory)
+	if getenv(EnvRunnerBaseDirectory) != "" {
+		c.RunnerBaseDirectory = getenv(EnvRunnerBaseDirectory)
 		log.Printf("use runner base directory is %s\n", c.RunnerBaseDirectory)
 	}
 
+	c.RunnerBaseDirectoryWindows = `C:\myshoes`
+	if getenv(EnvRunnerBaseDirectoryWindows) != "" {
+		c.RunnerBaseDirectoryWindows = getenv(EnvRunnerBaseDirectoryWindows)
+		log.Printf("use runner base directory for windows is %s\n", c.RunnerBaseDirectoryWindows)
+	}
+
+	if getenv(EnvScriptTemplateDirectory) != "" {
+		c.ScriptTemplateDirectory = getenv(EnvScriptTemplateDirectory)
+		log.Printf("use script template directory is %s\n", c.ScriptTemplateDirectory)
+	}
+
+	if getenv(EnvRunnerMirrorDirectory) != "" {
+		c.RunnerMirrorDirectory = getenv(EnvRunnerMirrorDirectory)
+		if getenv(EnvRunnerMirrorURL) == "" {
+			log.Panicf("%s must be set if %s is set", EnvRunnerMirrorURL, EnvRunnerMirrorDirectory)
+		}
+		u, err := url.Parse(getenv(EnvRunnerMirrorURL))
+		if err != nil {
+			log.Panicf("failed to parse URL %s: %+v", getenv(EnvRunnerMirrorURL), err)
+		}
+		if strings.EqualFold(u.Scheme, "") || strings.EqualFold(u.Host, "") {
+			log.Panicf("%s must has scheme and host (value: %s)", EnvRunnerMirrorURL, getenv(EnvRunnerMirrorURL))
+		}
+		c.RunnerMirrorURL = strings.TrimSuffix(getenv(EnvRunnerMirrorURL), "/")
+		c.RunnerMirrorOffline = getenv(EnvRunnerMirrorOffline) == "true"
+		log.Printf("use runner mirror directory is %s (offline: %t)\n", c.RunnerMirrorDirectory, c.RunnerMirrorOffline)
+	}
+
 	c.Debug = false
-	if os.Getenv(EnvDebug) == "true" {
+	if getenv(EnvDebug) == "true" {
 		c.Debug = true
 	}
 
+	c.LogFormat = "plain"
+	switch getenv(EnvLogFormat) {
+	case "", "plain":
+	case "text", "json":
+		c.LogFormat = getenv(EnvLogFormat)
+	default:
+		log.Panicf("%s must be plain, text or json (value: %s)", EnvLogFormat, getenv(EnvLogFormat))
+	}
+
+	if getenv(EnvOTLPEndpoint) != "" {
+		u, err := url.Parse(getenv(EnvOTLPEndpoint))
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			log.Panicf("%s must has scheme and host (value: %s)", EnvOTLPEndpoint, getenv(EnvOTLPEndpoint))
+		}
+		c.OTLPEndpoint = getenv(EnvOTLPEndpoint)
+	}
+
+	priceTable, err := parsePriceTable(getenv(EnvCostPriceTable))
+	if err != nil {
+		log.Panicf("failed to parse %s: %+v", EnvCostPriceTable, err)
+	}
+	c.PriceTable = priceTable
+
+	snapshotIntervals, err := parseSnapshotIntervals(getenv(EnvMetricsSnapshotIntervals))
+	if err != nil {
+		log.Panicf("failed to parse %s: %+v", EnvMetricsSnapshotIntervals, err)
+	}
+	c.MetricsSnapshotIntervals = snapshotIntervals
+
 	c.Strict = true
-	if os.Getenv(EnvStrict) == "false" {
+	if getenv(EnvStrict) == "false" {
 		c.Strict = false
 	}
 
 	c.ModeWebhookType = ModeWebhookTypeWorkflowJob
-	if os.Getenv(EnvModeWebhookType) != "" {
-		mwt := marshalModeWebhookType(os.Getenv(EnvModeWebhookType))
+	if getenv(EnvModeWebhookType) != "" {
+		mwt := marshalModeWebhookType(getenv(EnvModeWebhookType))
 
 		if mwt == ModeWebhookTypeUnknown {
-			log.Panicf("%s is invalid webhook type", os.Getenv(EnvModeWebhookType))
+			log.Panicf("%s is invalid webhook type", getenv(EnvModeWebhookType))
 		}
 
 		if mwt == ModeWebhookTypeCheckRun {
@@ -82,15 +144,15 @@ func LoadWithDefault() Conf {
 	}
 
 	c.ProvideDockerHubMetrics = false
-	if os.Getenv(EnvProvideDockerHubMetrics) == "true" {
+	if getenv(EnvProvideDockerHubMetrics) == "true" {
 		c.ProvideDockerHubMetrics = true
 	}
 
 	c.DockerHubCredential = DockerHubCredential{}
 	if c.ProvideDockerHubMetrics {
-		if os.Getenv(EnvDockerHubUsername) != "" && os.Getenv(EnvDockerHubPassword) != "" {
-			c.DockerHubCredential.Username = os.Getenv(EnvDockerHubUsername)
-			c.DockerHubCredential.Password = os.Getenv(EnvDockerHubPassword)
+		if getenv(EnvDockerHubUsername) != "" && getenv(EnvDockerHubPassword) != "" {
+			c.DockerHubCredential.Username = getenv(EnvDockerHubUsername)
+			c.DockerHubCredential.Password = getenv(EnvDockerHubPassword)
 		} else {
 			log.Println("WARNING: Providing Docker Hub metrics is enabled, but DOCKER_HUB_USERNAME and DOCKER_HUB_PASSWORD are not set. Providing Docker Hub metrics with anonymous user mode")
 		}
@@ -99,16 +161,16 @@ func LoadWithDefault() Conf {
 	}
 
 	c.MaxConnectionsToBackend = 50
-	if os.Getenv(EnvMaxConnectionsToBackend) != "" {
-		numberPB, err := strconv.ParseInt(os.Getenv(EnvMaxConnectionsToBackend), 10, 64)
+	if getenv(EnvMaxConnectionsToBackend) != "" {
+		numberPB, err := strconv.ParseInt(getenv(EnvMaxConnectionsToBackend), 10, 64)
 		if err != nil {
 			log.Panicf("failed to convert int64 %s: %+v", EnvMaxConnectionsToBackend, err)
 		}
 		c.MaxConnectionsToBackend = numberPB
 	}
 	c.MaxConcurrencyDeleting = 1
-	if os.Getenv(EnvMaxConcurrencyDeleting) != "" {
-		numberCD, err := strconv.ParseInt(os.Getenv(EnvMaxConcurrencyDeleting), 10, 64)
+	if getenv(EnvMaxConcurrencyDeleting) != "" {
+		numberCD, err := strconv.ParseInt(getenv(EnvMaxConcurrencyDeleting), 10, 64)
 		if err != nil {
 			log.Panicf("failed to convert int64 %s: %+v", EnvMaxConcurrencyDeleting, err)
 		}
@@ -116,64 +178,102 @@ func LoadWithDefault() Conf {
 	}
 
 	c.GitHubURL = "https://github.com"
-	if os.Getenv(EnvGitHubURL) != "" {
-		u, err := url.Parse(os.Getenv(EnvGitHubURL))
+	if getenv(EnvGitHubURL) != "" {
+		u, err := url.Parse(getenv(EnvGitHubURL))
 		if err != nil {
-			log.Panicf("failed to parse URL %s: %+v", os.Getenv(EnvGitHubURL), err)
+			log.Panicf("failed to parse URL %s: %+v", getenv(EnvGitHubURL), err)
 		}
 
 		if strings.EqualFold(u.Scheme, "") {
-			log.Panicf("%s must has scheme (value: %s)", EnvGitHubURL, os.Getenv(EnvGitHubURL))
+			log.Panicf("%s must has scheme (value: %s)", EnvGitHubURL, getenv(EnvGitHubURL))
 		}
 		if strings.EqualFold(u.Host, "") {
-			log.Panicf("%s must has host (value: %s)", EnvGitHubURL, os.Getenv(EnvGitHubURL))
+			log.Panicf("%s must has host (value: %s)", EnvGitHubURL, getenv(EnvGitHubURL))
 		}
 
-		c.GitHubURL = os.Getenv(EnvGitHubURL)
+		c.GitHubURL = getenv(EnvGitHubURL)
 	}
 
-	if os.Getenv(EnvRunnerVersion) == "" {
+	if getenv(EnvRunnerVersion) == "" {
 		c.RunnerVersion = "latest"
 	} else {
 		// valid value: "latest" or "vX.XXX.X"
-		switch os.Getenv(EnvRunnerVersion) {
+		switch getenv(EnvRunnerVersion) {
 		case "latest":
 			c.RunnerVersion = "latest"
 		default:
-			_, err := version.NewVersion(os.Getenv(EnvRunnerVersion))
+			_, err := version.NewVersion(getenv(EnvRunnerVersion))
 			if err != nil {
 				log.Panicf("failed to parse input runner version: %+v", err)
 			}
 
-			c.RunnerVersion = os.Getenv(EnvRunnerVersion)
+			c.RunnerVersion = getenv(EnvRunnerVersion)
 		}
 	}
 
 	c.ShoesPluginOutputPath = "."
-	if os.Getenv(EnvShoesPluginOutputPath) != "" {
-		c.ShoesPluginOutputPath = os.Getenv(EnvShoesPluginOutputPath)
+	if getenv(EnvShoesPluginOutputPath) != "" {
+		c.ShoesPluginOutputPath = getenv(EnvShoesPluginOutputPath)
 	}
 
+	c.APIAuth = APIAuth{
+		Tokens:        parseAPICredentials(EnvAPITokens),
+		HMACKeys:      parseAPICredentials(EnvAPIHMACKeys),
+		OIDCJWKSFile:  getenv(EnvAPIOIDCJWKSFile),
+		OIDCIssuer:    getenv(EnvAPIOIDCIssuer),
+		OIDCAudience:  getenv(EnvAPIOIDCAudience),
+		OIDCRoleClaim: getenv(EnvAPIOIDCRoleClaim),
+	}
+	if !c.APIAuth.Enabled() {
+		log.Println("WARNING: authentication of REST API is disabled. Please set API_TOKENS, API_HMAC_KEYS or API_OIDC_JWKS_FILE")
+	}
+
+	notify, err := loadNotify()
+	if err != nil {
+		log.Panicf("failed to load config of notifications: %+v", err)
+	}
+	c.Notify = notify
+
 	Config = c
 	return c
 }
 
+// parseAPICredentials parse credentials in environment, format is "<name>:<role>:<secret>,..."
+func parseAPICredentials(env string) []APICredential {
+	if getenv(env) == "" {
+		return nil
+	}
+
+	var credentials []APICredential
+	for _, s := range strings.Split(getenv(env), ",") {
+		parts := strings.SplitN(strings.TrimSpace(s), ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			log.Panicf("%s is invalid format, must be <name>:<role>:<secret>", env)
+		}
+		if parts[1] != "admin" && parts[1] != "read" {
+			log.Panicf("%s has invalid role %q (name: %s), must be admin or read", env, parts[1], parts[0])
+		}
+		credentials = append(credentials, APICredential{
+			Name:   parts[0],
+			Role:   parts[1],
+			Secret: parts[2],
+		})
+	}
+	return credentials
+}
+
 // LoadGitHubApps load config for GitHub Apps
 func LoadGitHubApps() *GitHubApp {
 	var ga GitHubApp
-	appID, err := strconv.ParseInt(os.Getenv(EnvGitHubAppID), 10, 64)
+	appID, err := strconv.ParseInt(getenv(EnvGitHubAppID), 10, 64)
 	if err != nil {
 		log.Panicf("failed to parse %s: %+v", EnvGitHubAppID, err)
 	}
 	ga.AppID = appID
 
-	pemBase64ed := os.Getenv(EnvGitHubAppPrivateKeyBase64)
-	if pemBase64ed == "" {
-		log.Panicf("%s must be set", EnvGitHubAppPrivateKeyBase64)
-	}
-	pemByte, err := base64.StdEncoding.DecodeString(pemBase64ed)
+	pemByte, err := loadPrivateKey()
 	if err != nil {
-		log.Panicf("failed to decode base64 %s: %+v", EnvGitHubAppPrivateKeyBase64, err)
+		log.Panicf("%+v", err)
 	}
 	ga.PEMByte = pemByte
 
@@ -187,7 +287,7 @@ func LoadGitHubApps() *GitHubApp {
 	}
 	ga.PEM = key
 
-	appSecret := os.Getenv(EnvGitHubAppSecret)
+	appSecret := getenv(EnvGitHubAppSecret)
 	if appSecret == "" {
 		log.Panicf("%s must be set", EnvGitHubAppSecret)
 	}
@@ -196,19 +296,40 @@ func LoadGitHubApps() *GitHubApp {
 	return &ga
 }
 
+// loadPrivateKey load private key of GitHub Apps, GITHUB_PRIVATE_KEY_BASE64 has priority over GITHUB_PRIVATE_KEY_FILE
+func loadPrivateKey() ([]byte, error) {
+	if pemBase64ed := getenv(EnvGitHubAppPrivateKeyBase64); pemBase64ed != "" {
+		pemByte, err := base64.StdEncoding.DecodeString(pemBase64ed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64 %s: %w", EnvGitHubAppPrivateKeyBase64, err)
+		}
+		return pemByte, nil
+	}
+
+	p := getenv(EnvGitHubAppPrivateKeyFile)
+	if p == "" {
+		return nil, fmt.Errorf("%s or %s must be set", EnvGitHubAppPrivateKeyBase64, EnvGitHubAppPrivateKeyFile)
+	}
+	pemByte, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", EnvGitHubAppPrivateKeyFile, err)
+	}
+	return pemByte, nil
+}
+
 // LoadMySQLURL load MySQL URL from environment
 func LoadMySQLURL() string {
-	mysqlHost, ok_Host := os.LookupEnv(EnvMySQLHost)
-	mysqlPort, ok_Port := os.LookupEnv(EnvMySQLPort)
-	mysqlUser, ok_User := os.LookupEnv(EnvMySQLUser)
-	mysqlPassword, ok_Password := os.LookupEnv(EnvMySQLPassword)
-	mysqlDatabase, ok_Database := os.LookupEnv(EnvMySQLDatabase)
+	mysqlHost, ok_Host := lookupEnv(EnvMySQLHost)
+	mysqlPort, ok_Port := lookupEnv(EnvMySQLPort)
+	mysqlUser, ok_User := lookupEnv(EnvMySQLUser)
+	mysqlPassword, ok_Password := lookupEnv(EnvMySQLPassword)
+	mysqlDatabase, ok_Database := lookupEnv(EnvMySQLDatabase)
 	if ok_Host && ok_Port && ok_User && ok_Password && ok_Database {
 		mysqlURL := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", mysqlUser, mysqlPassword, mysqlHost, mysqlPort, mysqlDatabase)
 		log.Println("load MySQL URL from environment variables MYSQL_USER, MYSQL_PASSWORD, MYSQL_HOST, MYSQL_PORT, MYSQL_DATABASE, not MYSQL_URL")
 		return mysqlURL
 	}
-	mysqlURL := os.Getenv(EnvMySQLURL)
+	mysqlURL := getenv(EnvMySQLURL)
 	if mysqlURL == "" {
 		log.Panicf("%s must be set", EnvMySQLURL)
 	}
@@ -217,7 +338,7 @@ func LoadMySQLURL() string {
 
 // LoadPluginPath load plugin path from environment
 func LoadPluginPath() string {
-	pluginPath := os.Getenv(EnvShoesPluginPath)
+	pluginPath := getenv(EnvShoesPluginPath)
 	if pluginPath == "" {
 		log.Panicf("%s must be set", EnvShoesPluginPath)
 	}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SnapshotIntervalWildcard match any scraper in MetricsSnapshotIntervals
+const SnapshotIntervalWildcard = "*"
+
+// parseSnapshotIntervals parse intervals of metrics snapshot, format is "<scraper>:<interval>,..."
+func parseSnapshotIntervals(s string) (map[string]time.Duration, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	intervals := map[string]time.Duration{}
+	for _, entry := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid format %q, must be <scraper>:<interval>", entry)
+		}
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval %q: %w", value, err)
+		}
+		if interval < time.Second {
+			return nil, fmt.Errorf("interval of %s must be 1s or more (value: %s)", name, value)
+		}
+		intervals[name] = interval
+	}
+	return intervals, nil
+}
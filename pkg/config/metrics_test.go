@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSnapshotIntervals(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    map[string]time.Duration
+		wantErr bool
+	}{
+		{input: "", want: nil},
+		{input: "datastore:30s, github:5m", want: map[string]time.Duration{"datastore": 30 * time.Second, "github": 5 * time.Minute}},
+		{input: "*:1m", want: map[string]time.Duration{"*": time.Minute}},
+		{input: "datastore", wantErr: true},
+		{input: ":30s", wantErr: true},
+		{input: "datastore:30", wantErr: true},
+		{input: "datastore:100ms", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := parseSnapshotIntervals(test.input)
+		if (err != nil) != test.wantErr {
+			t.Fatalf("parseSnapshotIntervals(%q) error = %v, wantErr %t", test.input, err, test.wantErr)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// default values of notification
+const (
+	defaultNotifyDedupWindow       = time.Hour
+	defaultNotifyRateLimit         = 10
+	defaultNotifyJobRetryThreshold = 10
+)
+
+// Notify is type of config value for notifications
+type Notify struct {
+	WebhookURLs      []string // generic HTTP webhook, event is posted as JSON
+	SlackWebhookURLs []string // Slack incoming webhook
+
+	DedupWindow       time.Duration // same event is sent once in this window
+	RateLimit         int           // max number of notifications per minute
+	JobRetryThreshold int           // notify if retry count of a job reaches this
+}
+
+// Enabled return true if any sink is configured
+func (n Notify) Enabled() bool {
+	return len(n.WebhookURLs) != 0 || len(n.SlackWebhookURLs) != 0
+}
+
+// loadNotify load config of notifications
+func loadNotify() (Notify, error) {
+	n := Notify{
+		DedupWindow:       defaultNotifyDedupWindow,
+		RateLimit:         defaultNotifyRateLimit,
+		JobRetryThreshold: defaultNotifyJobRetryThreshold,
+	}
+
+	var err error
+	if n.WebhookURLs, err = parseURLs(getenv(EnvNotifyWebhookURLs)); err != nil {
+		return Notify{}, fmt.Errorf("failed to parse %s: %w", EnvNotifyWebhookURLs, err)
+	}
+	if n.SlackWebhookURLs, err = parseURLs(getenv(EnvNotifySlackWebhookURLs)); err != nil {
+		return Notify{}, fmt.Errorf("failed to parse %s: %w", EnvNotifySlackWebhookURLs, err)
+	}
+
+	if v := getenv(EnvNotifyDedupWindow); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return Notify{}, fmt.Errorf("failed to parse %s: %w", EnvNotifyDedupWindow, err)
+		}
+		n.DedupWindow = d
+	}
+	if v := getenv(EnvNotifyRateLimit); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return Notify{}, fmt.Errorf("%s must be positive integer (value: %s)", EnvNotifyRateLimit, v)
+		}
+		n.RateLimit = limit
+	}
+	if v := getenv(EnvNotifyJobRetryThreshold); v != "" {
+		threshold, err := strconv.Atoi(v)
+		if err != nil || threshold <= 0 {
+			return Notify{}, fmt.Errorf("%s must be positive integer (value: %s)", EnvNotifyJobRetryThreshold, v)
+		}
+		n.JobRetryThreshold = threshold
+	}
+	return n, nil
+}
+
+// parseURLs parse URLs separated by comma, URL must has scheme and host
+func parseURLs(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var urls []string
+	for _, raw := range strings.Split(s, ",") {
+		raw = strings.TrimSpace(raw)
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			// not output URL, it may have token
+			return nil, fmt.Errorf("URL must has scheme and host")
+		}
+		urls = append(urls, raw)
+	}
+	return urls, nil
+}
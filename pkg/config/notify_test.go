@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadNotify(t *testing.T) {
+	tests := []struct {
+		input   map[string]string
+		want    Notify
+		wantErr bool
+	}{
+		{
+			input: map[string]string{},
+			want:  Notify{DedupWindow: time.Hour, RateLimit: 10, JobRetryThreshold: 10},
+		},
+		{
+			input: map[string]string{
+				EnvNotifyWebhookURLs:       "https://example.com/hook1, https://example.com/hook2",
+				EnvNotifySlackWebhookURLs:  "https://hooks.slack.com/services/T000/B000/XXX",
+				EnvNotifyDedupWindow:       "30m",
+				EnvNotifyRateLimit:         "5",
+				EnvNotifyJobRetryThreshold: "3",
+			},
+			want: Notify{
+				WebhookURLs:       []string{"https://example.com/hook1", "https://example.com/hook2"},
+				SlackWebhookURLs:  []string{"https://hooks.slack.com/services/T000/B000/XXX"},
+				DedupWindow:       30 * time.Minute,
+				RateLimit:         5,
+				JobRetryThreshold: 3,
+			},
+		},
+		{
+			input:   map[string]string{EnvNotifyWebhookURLs: "example.com/hook"},
+			wantErr: true,
+		},
+		{
+			input:   map[string]string{EnvNotifyRateLimit: "0"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		for _, key := range []string{EnvNotifyWebhookURLs, EnvNotifySlackWebhookURLs, EnvNotifyDedupWindow, EnvNotifyRateLimit, EnvNotifyJobRetryThreshold} {
+			t.Setenv(key, test.input[key])
+		}
+
+		got, err := loadNotify()
+		if (err != nil) != test.wantErr {
+			t.Fatalf("loadNotify() error = %v, wantErr %t", err, test.wantErr)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
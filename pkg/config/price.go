@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PriceWildcard match any provider or resource type in PriceTable
+const PriceWildcard = "*"
+
+// Price is price of a runner-minute
+type Price struct {
+	Provider     string // shoes type of runner (e.g. aws), or *
+	ResourceType string // e.g. nano, or *
+	PerMinute    float64
+}
+
+// PriceTable is list of Price for estimating cost
+type PriceTable []Price
+
+// PerMinute return price of a runner-minute, more specific price has priority.
+// return 0 if no price is matched.
+func (t PriceTable) PerMinute(provider, resourceType string) float64 {
+	for _, match := range [][2]string{
+		{provider, resourceType},
+		{provider, PriceWildcard},
+		{PriceWildcard, resourceType},
+		{PriceWildcard, PriceWildcard},
+	} {
+		for _, p := range t {
+			if p.Provider == match[0] && p.ResourceType == match[1] {
+				return p.PerMinute
+			}
+		}
+	}
+	return 0
+}
+
+// parsePriceTable parse price table, format is "<provider>:<resource_type>:<price per minute>,..."
+func parsePriceTable(s string) (PriceTable, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var table PriceTable
+	for _, entry := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid format %q, must be <provider>:<resource_type>:<price per minute>", entry)
+		}
+		perMinute, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil || perMinute < 0 {
+			return nil, fmt.Errorf("invalid price %q, must be non-negative number", parts[2])
+		}
+		table = append(table, Price{
+			Provider:     parts[0],
+			ResourceType: parts[1],
+			PerMinute:    perMinute,
+		})
+	}
+	return table, nil
+}
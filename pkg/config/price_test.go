@@ -0,0 +1,35 @@
+package config
+
+import "testing"
+
+func TestPriceTable_PerMinute(t *testing.T) {
+	table, err := parsePriceTable("aws:large:0.008, aws:*:0.002,*:nano:0.0005,*:*:0.001")
+	if err != nil {
+		t.Fatalf("failed to parse price table: %+v", err)
+	}
+
+	tests := []struct {
+		provider     string
+		resourceType string
+		want         float64
+	}{
+		{provider: "aws", resourceType: "large", want: 0.008},
+		{provider: "aws", resourceType: "nano", want: 0.002},
+		{provider: "lxd", resourceType: "nano", want: 0.0005},
+		{provider: "lxd", resourceType: "large", want: 0.001},
+	}
+	for _, test := range tests {
+		if got := table.PerMinute(test.provider, test.resourceType); got != test.want {
+			t.Errorf("price of %s:%s must be %v, but got %v", test.provider, test.resourceType, test.want, got)
+		}
+	}
+	if got := PriceTable(nil).PerMinute("aws", "large"); got != 0 {
+		t.Errorf("price must be 0 without price table, but got %v", got)
+	}
+
+	for _, invalid := range []string{"aws:large", "aws:large:free", ":large:1", "aws:large:-1"} {
+		if _, err := parsePriceTable(invalid); err == nil {
+			t.Errorf("%q must be invalid", invalid)
+		}
+	}
+}
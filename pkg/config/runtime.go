@@ -0,0 +1,245 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-version"
+)
+
+// LogLevel is level of logging
+type LogLevel string
+
+// LogLevel values
+const (
+	LogLevelInfo  LogLevel = "info"
+	LogLevelDebug LogLevel = "debug"
+	LogLevelWarn  LogLevel = "warn"
+	LogLevelError LogLevel = "error"
+)
+
+// Duration is time.Duration that is marshaled to string (e.g. "5m0s") in JSON
+type Duration time.Duration
+
+// MarshalJSON is implementation of json.Marshaler
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON is implementation of json.Unmarshaler
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be string (e.g. \"5m\"): %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("failed to parse duration: %w", err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Runtime is config values that can be changed without restart
+type Runtime struct {
+	// Version is incremented by every update, start from 1
+	Version int64 `json:"version"`
+
+	MaxConnectionsToBackend int64  `json:"max_connections_to_backend"`
+	MaxConcurrencyDeleting  int64  `json:"max_concurrency_deleting"`
+	RunnerVersion           string `json:"runner_version"`
+
+	MustRunningTime Duration `json:"must_running_time"`
+	MustGoalTime    Duration `json:"must_goal_time"`
+
+	// RescuePendingThreshold is time of pending workflow run that will be rescued
+	RescuePendingThreshold Duration `json:"rescue_pending_threshold"`
+	// RescueRecentWindow is time of repositories that have runners recently, rescue is checked in these repositories
+	RescueRecentWindow Duration `json:"rescue_recent_window"`
+
+	LogLevel LogLevel `json:"log_level"`
+	Strict   bool     `json:"strict"`
+}
+
+// IsDebug return true if log level is debug
+func (r Runtime) IsDebug() bool {
+	return r.LogLevel == LogLevelDebug
+}
+
+// Validate check values of Runtime
+func (r Runtime) Validate() error {
+	switch {
+	case r.MaxConnectionsToBackend < 1:
+		return errors.New("max_connections_to_backend must be greater than 0")
+	case r.MaxConcurrencyDeleting < 1:
+		return errors.New("max_concurrency_deleting must be greater than 0")
+	case r.MustRunningTime <= 0, r.MustGoalTime <= 0:
+		return errors.New("must_running_time and must_goal_time must be positive")
+	case r.MustRunningTime > r.MustGoalTime:
+		return errors.New("must_running_time must be less than must_goal_time")
+	case r.RescuePendingThreshold <= 0, r.RescueRecentWindow <= 0:
+		return errors.New("rescue_pending_threshold and rescue_recent_window must be positive")
+	case r.LogLevel != LogLevelDebug && r.LogLevel != LogLevelInfo && r.LogLevel != LogLevelWarn && r.LogLevel != LogLevelError:
+		return fmt.Errorf("log_level must be %s, %s, %s or %s", LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError)
+	}
+
+	if !strings.EqualFold(r.RunnerVersion, "latest") {
+		if _, err := version.NewVersion(r.RunnerVersion); err != nil {
+			return fmt.Errorf("runner_version must be latest or version (e.g. v2.300.0): %w", err)
+		}
+	}
+	return nil
+}
+
+// RuntimePatch is partial update of Runtime, nil field is not changed
+type RuntimePatch struct {
+	MaxConnectionsToBackend *int64    `json:"max_connections_to_backend,omitempty"`
+	MaxConcurrencyDeleting  *int64    `json:"max_concurrency_deleting,omitempty"`
+	RunnerVersion           *string   `json:"runner_version,omitempty"`
+	MustRunningTime         *Duration `json:"must_running_time,omitempty"`
+	MustGoalTime            *Duration `json:"must_goal_time,omitempty"`
+	RescuePendingThreshold  *Duration `json:"rescue_pending_threshold,omitempty"`
+	RescueRecentWindow      *Duration `json:"rescue_recent_window,omitempty"`
+	LogLevel                *LogLevel `json:"log_level,omitempty"`
+	Strict                  *bool     `json:"strict,omitempty"`
+}
+
+// Apply return Runtime that applied patch
+func (p RuntimePatch) Apply(r Runtime) Runtime {
+	if p.MaxConnectionsToBackend != nil {
+		r.MaxConnectionsToBackend = *p.MaxConnectionsToBackend
+	}
+	if p.MaxConcurrencyDeleting != nil {
+		r.MaxConcurrencyDeleting = *p.MaxConcurrencyDeleting
+	}
+	if p.RunnerVersion != nil {
+		r.RunnerVersion = *p.RunnerVersion
+	}
+	if p.MustRunningTime != nil {
+		r.MustRunningTime = *p.MustRunningTime
+	}
+	if p.MustGoalTime != nil {
+		r.MustGoalTime = *p.MustGoalTime
+	}
+	if p.RescuePendingThreshold != nil {
+		r.RescuePendingThreshold = *p.RescuePendingThreshold
+	}
+	if p.RescueRecentWindow != nil {
+		r.RescueRecentWindow = *p.RescueRecentWindow
+	}
+	if p.LogLevel != nil {
+		r.LogLevel = *p.LogLevel
+	}
+	if p.Strict != nil {
+		r.Strict = *p.Strict
+	}
+	return r
+}
+
+// Errors of updating Runtime
+var (
+	ErrRuntimeVersionConflict = errors.New("runtime config is already updated")
+	ErrInvalidRuntime         = errors.New("invalid runtime config")
+)
+
+var (
+	runtimeValue atomic.Pointer[Runtime]
+	// runtimeMu serialize updating runtimeValue and calling hooks
+	runtimeMu    sync.Mutex
+	runtimeHooks []func(Runtime)
+)
+
+// DefaultRuntime return default value of Runtime
+func DefaultRuntime() Runtime {
+	return Runtime{
+		Version:                 1,
+		MaxConnectionsToBackend: 50,
+		MaxConcurrencyDeleting:  1,
+		RunnerVersion:           "latest",
+		MustRunningTime:         Duration(5 * time.Minute),
+		MustGoalTime:            Duration(6 * time.Hour),
+		RescuePendingThreshold:  Duration(10 * time.Minute),
+		RescueRecentWindow:      Duration(1 * time.Hour),
+		LogLevel:                LogLevelInfo,
+		Strict:                  true,
+	}
+}
+
+// InitialRuntime return Runtime from values in environment, these can be changed after start
+func (c Conf) InitialRuntime() Runtime {
+	r := DefaultRuntime()
+	r.MaxConnectionsToBackend = c.MaxConnectionsToBackend
+	r.MaxConcurrencyDeleting = c.MaxConcurrencyDeleting
+	r.RunnerVersion = c.RunnerVersion
+	r.Strict = c.Strict
+	if c.Debug {
+		r.LogLevel = LogLevelDebug
+	}
+	return r
+}
+
+// GetRuntime return current Runtime
+func GetRuntime() Runtime {
+	r := runtimeValue.Load()
+	if r == nil {
+		return DefaultRuntime()
+	}
+	return *r
+}
+
+// OnRuntimeChange register hook that is called after Runtime is changed
+func OnRuntimeChange(hook func(Runtime)) {
+	runtimeMu.Lock()
+	defer runtimeMu.Unlock()
+	runtimeHooks = append(runtimeHooks, hook)
+}
+
+// SetRuntime replace Runtime, and call hooks
+func SetRuntime(r Runtime) error {
+	if err := r.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRuntime, err)
+	}
+
+	runtimeMu.Lock()
+	defer runtimeMu.Unlock()
+	setRuntime(r)
+	return nil
+}
+
+// UpdateRuntime apply patch to Runtime and increment version.
+// return ErrRuntimeVersionConflict if expectVersion is not zero and is not current version.
+// persist is called before applying, Runtime is not changed if persist returns error.
+func UpdateRuntime(patch RuntimePatch, expectVersion int64, persist func(Runtime) error) (Runtime, error) {
+	runtimeMu.Lock()
+	defer runtimeMu.Unlock()
+
+	current := GetRuntime()
+	if expectVersion != 0 && expectVersion != current.Version {
+		return current, ErrRuntimeVersionConflict
+	}
+
+	updated := patch.Apply(current)
+	if err := updated.Validate(); err != nil {
+		return current, fmt.Errorf("%w: %w", ErrInvalidRuntime, err)
+	}
+	updated.Version = current.Version + 1
+
+	if err := persist(updated); err != nil {
+		return current, fmt.Errorf("failed to persist runtime config: %w", err)
+	}
+	setRuntime(updated)
+	return updated, nil
+}
+
+// setRuntime store Runtime and call hooks, must be called with runtimeMu
+func setRuntime(r Runtime) {
+	runtimeValue.Store(&r)
+	for _, hook := range runtimeHooks {
+		hook(r)
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUpdateRuntime(t *testing.T) {
+	original := GetRuntime()
+	defer SetRuntime(original)
+	if err := SetRuntime(DefaultRuntime()); err != nil {
+		t.Fatalf("failed to set runtime: %+v", err)
+	}
+
+	var hooked Runtime
+	OnRuntimeChange(func(r Runtime) { hooked = r })
+	defer func() { runtimeHooks = nil }()
+
+	persist := func(Runtime) error { return nil }
+	max := int64(10)
+	got, err := UpdateRuntime(RuntimePatch{MaxConnectionsToBackend: &max}, 0, persist)
+	if err != nil {
+		t.Fatalf("failed to update runtime: %+v", err)
+	}
+	if got.Version != 2 || got.MaxConnectionsToBackend != 10 || hooked != got || GetRuntime() != got {
+		t.Errorf("runtime is not updated (got: %+v, hooked: %+v)", got, hooked)
+	}
+
+	goal := Duration(time.Minute)
+	if _, err := UpdateRuntime(RuntimePatch{MustGoalTime: &goal}, 0, persist); !errors.Is(err, ErrInvalidRuntime) {
+		t.Errorf("must_goal_time less than must_running_time must be invalid, but got %+v", err)
+	}
+	if _, err := UpdateRuntime(RuntimePatch{MaxConnectionsToBackend: &max}, 5, persist); !errors.Is(err, ErrRuntimeVersionConflict) {
+		t.Errorf("must be conflict, but got %+v", err)
+	}
+	max = 20
+	if _, err := UpdateRuntime(RuntimePatch{MaxConnectionsToBackend: &max}, 2, func(Runtime) error { return errors.New("datastore is down") }); err == nil {
+		t.Errorf("must be error if failed to persist")
+	}
+	if GetRuntime() != got {
+		t.Errorf("runtime must not be changed by failed update, but got %+v", GetRuntime())
+	}
+}
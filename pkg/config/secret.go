@@ -0,0 +1,139 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/whywaita/myshoes/pkg/secret"
+)
+
+// Values of SECRET_PROVIDER
+const (
+	SecretProviderEnv   = "env"
+	SecretProviderFile  = "file"
+	SecretProviderVault = "vault"
+)
+
+// defaultWebhookSecretGracePeriod is time that old secret is valid after rotation
+const defaultWebhookSecretGracePeriod = 1 * time.Hour
+
+// newSecretProvider create secret.Provider from config, return nil if SECRET_PROVIDER is not set
+func newSecretProvider() (secret.Provider, error) {
+	switch strings.ToLower(getenv(EnvSecretProvider)) {
+	case "":
+		return nil, nil
+	case SecretProviderEnv:
+		return secret.Env{}, nil
+	case SecretProviderFile:
+		dir := getenv(EnvSecretFileDirectory)
+		if dir == "" {
+			return nil, fmt.Errorf("%s must be set if %s is %s", EnvSecretFileDirectory, EnvSecretProvider, SecretProviderFile)
+		}
+		return secret.File{Directory: dir}, nil
+	case SecretProviderVault:
+		if getenv(EnvVaultAddress) == "" || getenv(EnvVaultKVPath) == "" {
+			return nil, fmt.Errorf("%s and %s must be set if %s is %s", EnvVaultAddress, EnvVaultKVPath, EnvSecretProvider, SecretProviderVault)
+		}
+		mount := getenv(EnvVaultKVMount)
+		if mount == "" {
+			mount = "secret"
+		}
+		return &secret.Vault{
+			Address:   getenv(EnvVaultAddress),
+			Token:     getenv(EnvVaultToken),
+			MountPath: mount,
+			Path:      getenv(EnvVaultKVPath),
+		}, nil
+	}
+	return nil, fmt.Errorf("%s is invalid value (%s), must be %s, %s or %s", EnvSecretProvider, getenv(EnvSecretProvider), SecretProviderEnv, SecretProviderFile, SecretProviderVault)
+}
+
+// LoadSecrets fetch secrets from secret provider.
+// name of secret is key in lower case (e.g. github_app_secret), a value in flags, environment or config file has priority.
+func LoadSecrets(ctx context.Context) (err error) {
+	defer func() {
+		// lookup panic if failed to read file of KEY_FILE
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	provider, err := newSecretProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create secret provider: %w", err)
+	}
+
+	values := map[string]string{}
+	if provider != nil {
+		for _, s := range settings {
+			if !s.secret || s.key == EnvVaultToken {
+				continue
+			}
+			v, err := provider.Get(ctx, strings.ToLower(s.key))
+			switch {
+			case errors.Is(err, secret.ErrNotFound):
+				continue
+			case err != nil:
+				return fmt.Errorf("failed to get %s from secret provider: %w", strings.ToLower(s.key), err)
+			}
+			values[s.key] = v
+		}
+	}
+
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	providerValues = values
+	return nil
+}
+
+var webhookSecret struct {
+	mu               sync.RWMutex
+	current          []byte
+	previous         []byte
+	previousExpireAt time.Time
+}
+
+// SetWebhookSecret set secret of GitHub webhook, previous secret is discarded
+func SetWebhookSecret(s []byte) {
+	webhookSecret.mu.Lock()
+	defer webhookSecret.mu.Unlock()
+	webhookSecret.current = s
+	webhookSecret.previous = nil
+}
+
+// RotateWebhookSecret change secret of GitHub webhook, old secret is still valid until now + gracePeriod
+func RotateWebhookSecret(s []byte, now time.Time, gracePeriod time.Duration) {
+	webhookSecret.mu.Lock()
+	defer webhookSecret.mu.Unlock()
+	if bytes.Equal(webhookSecret.current, s) {
+		return
+	}
+	webhookSecret.previous = webhookSecret.current
+	webhookSecret.previousExpireAt = now.Add(gracePeriod)
+	webhookSecret.current = s
+}
+
+// WebhookSecrets return secrets of GitHub webhook that are valid at now, current secret is first
+func WebhookSecrets(now time.Time) [][]byte {
+	webhookSecret.mu.RLock()
+	defer webhookSecret.mu.RUnlock()
+
+	secrets := [][]byte{webhookSecret.current}
+	if webhookSecret.previous != nil && now.Before(webhookSecret.previousExpireAt) {
+		secrets = append(secrets, webhookSecret.previous)
+	}
+	return secrets
+}
+
+func webhookSecretGracePeriod() time.Duration {
+	d, err := time.ParseDuration(getenv(EnvWebhookSecretGracePeriod))
+	if err != nil {
+		return defaultWebhookSecretGracePeriod
+	}
+	return d
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadSecrets(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write file: %+v", err)
+		}
+		return p
+	}
+	defer func() {
+		providerValues = map[string]string{}
+	}()
+
+	t.Setenv(EnvGitHubAppSecret+fileSuffix, writeFile("app_secret", "secret-from-file\n"))
+	if got, source, _ := lookup(EnvGitHubAppSecret); got != "secret-from-file" || source != SourceEnv {
+		t.Errorf("%s must be read from %s%s, but got %q from %s", EnvGitHubAppSecret, EnvGitHubAppSecret, fileSuffix, got, source)
+	}
+
+	secretDir := filepath.Join(dir, "secrets")
+	if err := os.Mkdir(secretDir, 0700); err != nil {
+		t.Fatalf("failed to create directory: %+v", err)
+	}
+	if err := os.WriteFile(filepath.Join(secretDir, "mysql_url"), []byte("user:pass@tcp(localhost:3306)/myshoes\n"), 0600); err != nil {
+		t.Fatalf("failed to write file: %+v", err)
+	}
+	t.Setenv(EnvSecretProvider, SecretProviderFile)
+	t.Setenv(EnvSecretFileDirectory, secretDir)
+	if err := LoadSecrets(context.Background()); err != nil {
+		t.Fatalf("failed to load secrets: %+v", err)
+	}
+	if got, source, _ := lookup(EnvMySQLURL); got != "user:pass@tcp(localhost:3306)/myshoes" || source != SourceSecret {
+		t.Errorf("%s must be read from secret provider, but got %q from %s", EnvMySQLURL, got, source)
+	}
+	if got, source, _ := lookup(EnvGitHubAppSecret); got != "secret-from-file" || source != SourceEnv {
+		t.Errorf("environment must have priority over secret provider, but got %q from %s", got, source)
+	}
+
+	t.Setenv(EnvSecretProvider, "unknown")
+	if err := LoadSecrets(context.Background()); err == nil {
+		t.Errorf("unknown provider must return error")
+	}
+}
+
+func TestRotateWebhookSecret(t *testing.T) {
+	defer SetWebhookSecret(nil)
+
+	now := time.Now()
+	SetWebhookSecret([]byte("old"))
+	RotateWebhookSecret([]byte("new"), now, time.Hour)
+
+	got := WebhookSecrets(now.Add(30 * time.Minute))
+	if len(got) != 2 || string(got[0]) != "new" || string(got[1]) != "old" {
+		t.Errorf("new and old secrets must be valid in grace period, but got %q", got)
+	}
+	got = WebhookSecrets(now.Add(2 * time.Hour))
+	if len(got) != 1 || string(got[0]) != "new" {
+		t.Errorf("only new secret must be valid after grace period, but got %q", got)
+	}
+
+	RotateWebhookSecret([]byte("new"), now, time.Hour)
+	if got := WebhookSecrets(now); len(got) != 2 || string(got[1]) != "old" {
+		t.Errorf("rotation to same secret must not discard previous secret, but got %q", got)
+	}
+}
@@ -0,0 +1,46 @@
+package datastore
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// AuditChange is a changed field in AuditLog
+type AuditChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// AuditChanges is list of changed fields
+type AuditChanges []AuditChange
+
+// Value implements the database/sql/driver Valuer interface
+func (c AuditChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return driver.Value("[]"), nil
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal AuditChanges: %w", err)
+	}
+	return driver.Value(string(b)), nil
+}
+
+// Scan implements the database/sql Scanner interface
+func (c *AuditChanges) Scan(src interface{}) error {
+	b, err := scanJSONBytes(src)
+	if err != nil {
+		return fmt.Errorf("incompatible type for AuditChanges: %w", err)
+	}
+	changes := AuditChanges{}
+	if len(b) != 0 {
+		if err := json.Unmarshal(b, &changes); err != nil {
+			return fmt.Errorf("failed to unmarshal AuditChanges: %w", err)
+		}
+	}
+
+	*c = changes
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/whywaita/myshoes/pkg/logger"
 
 	"github.com/google/go-github/v80/github"
+	"github.com/whywaita/myshoes/pkg/config"
 	"github.com/whywaita/myshoes/pkg/gh"
 )
 
@@ -141,13 +142,13 @@ func getPendingRunByRepo(ctx context.Context, client *github.Client, owner, repo
 	var pendingRuns []*github.WorkflowRun
 	for _, r := range runs {
 		if r.GetStatus() == "queued" || r.GetStatus() == "pending" {
-			oldMinutes := 10
-			sinceMinutes := time.Since(r.CreatedAt.Time).Minutes()
-			if sinceMinutes >= float64(oldMinutes) {
-				logger.Logf(false, "workflow run %d is pending over %d minutes, So will enqueue (repo: %s/%s)", r.GetID(), oldMinutes, owner, repo)
+			threshold := time.Duration(config.GetRuntime().RescuePendingThreshold)
+			since := time.Since(r.CreatedAt.Time)
+			if since >= threshold {
+				logger.Logf(false, "workflow run %d is pending over %s, So will enqueue (repo: %s/%s)", r.GetID(), threshold, owner, repo)
 				pendingRuns = append(pendingRuns, r)
 			} else {
-				logger.Logf(true, "workflow run %d is pending, but not over %d minutes. So ignore (since: %f minutes, repo: %s/%s)", r.GetID(), oldMinutes, sinceMinutes, owner, repo)
+				logger.Logf(true, "workflow run %d is pending, but not over %s. So ignore (since: %s, repo: %s/%s)", r.GetID(), threshold, since, owner, repo)
 			}
 		}
 	}
@@ -156,7 +157,7 @@ func getPendingRunByRepo(ctx context.Context, client *github.Client, owner, repo
 }
 
 func getRecentRepositories(ctx context.Context, ds Datastore) ([]string, error) {
-	recent := time.Now().Add(-1 * time.Hour)
+	recent := time.Now().Add(-time.Duration(config.GetRuntime().RescueRecentWindow))
 	recentRunners, err := ds.ListRunnersLogBySince(ctx, recent)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get targets from datastore: %w", err)
@@ -13,6 +13,7 @@ import (
 
 	"github.com/whywaita/myshoes/pkg/gh"
 	"github.com/whywaita/myshoes/pkg/logger"
+	"github.com/whywaita/myshoes/pkg/notify"
 )
 
 // Error values
@@ -38,10 +39,14 @@ type Datastore interface {
 	UpdateTargetStatus(ctx context.Context, targetID uuid.UUID, newStatus TargetStatus, description string) error
 	UpdateToken(ctx context.Context, targetID uuid.UUID, newToken string, newExpiredAt time.Time) error
 
-	UpdateTargetParam(ctx context.Context, targetID uuid.UUID, newResourceType ResourceType, newProviderURL sql.NullString) error
+	UpdateTargetParam(ctx context.Context, targetID uuid.UUID, newParam TargetParam) error
+	// ListTargetStatusHistory get changes of target status, sorted by newest first
+	ListTargetStatusHistory(ctx context.Context, targetID uuid.UUID, limit, offset int) ([]TargetStatusHistory, error)
 
 	EnqueueJob(ctx context.Context, job Job) error
 	ListJobs(ctx context.Context) ([]Job, error)
+	ListJobsWithFilter(ctx context.Context, filter JobFilter) ([]Job, error)
+	GetJob(ctx context.Context, id uuid.UUID) (*Job, error)
 	DeleteJob(ctx context.Context, id uuid.UUID) error
 
 	CreateRunner(ctx context.Context, runner Runner) error
@@ -49,7 +54,35 @@ type Datastore interface {
 	ListRunnersByTargetID(ctx context.Context, targetID uuid.UUID) ([]Runner, error)
 	ListRunnersLogBySince(ctx context.Context, since time.Time) ([]Runner, error)
 	GetRunner(ctx context.Context, id uuid.UUID) (*Runner, error)
+	ListRunnersWithFilter(ctx context.Context, filter RunnerFilter) ([]Runner, error)
+	// ListRunnersByPeriod get runners that were running in [from, to), it contains deleted runners
+	ListRunnersByPeriod(ctx context.Context, from, to time.Time) ([]Runner, error)
 	DeleteRunner(ctx context.Context, id uuid.UUID, deletedAt time.Time, reason RunnerStatus) error
+	SetRunnerExecutedJob(ctx context.Context, id uuid.UUID, job ExecutedJob) error
+	SetRunnerOnline(ctx context.Context, id uuid.UUID, onlineAt time.Time) error
+
+	CreateScriptTemplate(ctx context.Context, st ScriptTemplate) error
+	GetScriptTemplate(ctx context.Context, name string) (*ScriptTemplate, error)
+	ListScriptTemplates(ctx context.Context) ([]ScriptTemplate, error)
+	UpdateScriptTemplate(ctx context.Context, st ScriptTemplate) error
+	DeleteScriptTemplate(ctx context.Context, name string) error
+
+	CreateAPIToken(ctx context.Context, token APIToken) error
+	GetAPITokenByHash(ctx context.Context, tokenHash string) (*APIToken, error)
+	ListAPITokens(ctx context.Context) ([]APIToken, error)
+	RevokeAPIToken(ctx context.Context, id uuid.UUID, revokedAt time.Time) error
+
+	CreateAuditLog(ctx context.Context, log AuditLog) error
+	ListAuditLogs(ctx context.Context, filter AuditLogFilter) ([]AuditLog, error)
+
+	CreateMaintenanceWindow(ctx context.Context, window MaintenanceWindow) error
+	// ListMaintenanceWindows get windows that are not ended at now, sorted by start_at
+	ListMaintenanceWindows(ctx context.Context, now time.Time) ([]MaintenanceWindow, error)
+	DeleteMaintenanceWindow(ctx context.Context, id uuid.UUID) error
+
+	// GetRuntimeConfig return ErrNotFound if runtime config is not saved yet
+	GetRuntimeConfig(ctx context.Context) (*RuntimeConfig, error)
+	PutRuntimeConfig(ctx context.Context, rc RuntimeConfig) error
 
 	// Lock
 	GetLock(ctx context.Context) error
@@ -67,17 +100,49 @@ type Target struct {
 
 	ResourceType      ResourceType   `db:"resource_type" json:"resource_type"`
 	ProviderURL       sql.NullString `db:"provider_url" json:"provider_url"`
+	RunnerGroup       sql.NullString `db:"runner_group" json:"runner_group"` // only organization scope
+	UseJITConfig      bool           `db:"use_jit_config" json:"use_jit_config"`
+	ScriptTemplate    sql.NullString `db:"script_template" json:"script_template"`
+	RunnerOS          RunnerOS       `db:"runner_os" json:"runner_os"`
+	RunnerArch        RunnerArch     `db:"runner_arch" json:"runner_arch"`
 	Status            TargetStatus   `db:"status" json:"status"`
 	StatusDescription sql.NullString `db:"status_description" json:"status_description"`
 	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
 	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"`
 }
 
+// TargetParam is parameters of target that can be updated
+type TargetParam struct {
+	ResourceType   ResourceType
+	ProviderURL    sql.NullString
+	RunnerGroup    sql.NullString
+	UseJITConfig   bool
+	ScriptTemplate sql.NullString
+	RunnerOS       RunnerOS
+	RunnerArch     RunnerArch
+}
+
 // OwnerRepo return :owner and :repo
 func (t *Target) OwnerRepo() (string, string) {
 	return gh.DivideScope(t.Scope)
 }
 
+// GetRunnerOS return operating system of runner, default is linux
+func (t *Target) GetRunnerOS() RunnerOS {
+	if t.RunnerOS == "" {
+		return RunnerOSLinux
+	}
+	return t.RunnerOS
+}
+
+// GetRunnerArch return architecture of runner, default is x64
+func (t *Target) GetRunnerArch() RunnerArch {
+	if t.RunnerArch == "" {
+		return RunnerArchX64
+	}
+	return t.RunnerArch
+}
+
 // CanReceiveJob check status in target
 func (t *Target) CanReceiveJob() bool {
 	switch t.Status {
@@ -88,6 +153,29 @@ func (t *Target) CanReceiveJob() bool {
 	return true
 }
 
+// CanEnqueueJob check status in target for webhook.
+// jobs for suspended (paused) target are queued, and processed after resume.
+func (t *Target) CanEnqueueJob() bool {
+	return t.Status != TargetStatusDeleted
+}
+
+// IsProvisioningPaused return true if maintenance window is active or target is paused
+func IsProvisioningPaused(ctx context.Context, ds Datastore, targetID uuid.UUID, now time.Time) (bool, error) {
+	inMaintenance, err := IsInMaintenance(ctx, ds, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to check maintenance: %w", err)
+	}
+	if inMaintenance {
+		return true, nil
+	}
+
+	target, err := ds.GetTarget(ctx, targetID)
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve relational target (target ID: %s): %w", targetID, err)
+	}
+	return target.Status == TargetStatusSuspend, nil
+}
+
 // ListTargets get list of target that can receive job
 func ListTargets(ctx context.Context, ds Datastore) ([]Target, error) {
 	targets, err := ds.ListTargets(ctx)
@@ -123,6 +211,19 @@ func UpdateTargetStatus(ctx context.Context, ds Datastore, targetID uuid.UUID, n
 		return err
 	}
 
+	if newStatus == TargetStatusErr && target.Status != TargetStatusErr {
+		notify.Notify(ctx, notify.Event{
+			Kind:    notify.KindTargetError,
+			Key:     targetID.String(),
+			Title:   fmt.Sprintf("target %s is error", target.Scope),
+			Message: description,
+			Fields: map[string]string{
+				"target_id": targetID.String(),
+				"scope":     target.Scope,
+			},
+		})
+	}
+
 	return nil
 }
 
@@ -136,7 +237,7 @@ func SearchRepo(ctx context.Context, ds Datastore, repo string) (*Target, error)
 
 	// use repo scope if set repo
 	repoTarget, err := ds.GetTargetByScope(ctx, repo)
-	if err == nil && repoTarget.CanReceiveJob() {
+	if err == nil && repoTarget.CanEnqueueJob() {
 		return repoTarget, nil
 	} else if err != nil && !errors.Is(err, ErrNotFound) {
 		return nil, fmt.Errorf("failed to get target from repo: %w", err)
@@ -149,7 +250,7 @@ func SearchRepo(ctx context.Context, ds Datastore, repo string) (*Target, error)
 		return nil, fmt.Errorf("failed to get target from organization: %w", err)
 	}
 
-	if !orgTarget.CanReceiveJob() {
+	if !orgTarget.CanEnqueueJob() {
 		return nil, fmt.Errorf("target is not active")
 	}
 
@@ -168,6 +269,50 @@ const (
 	TargetStatusErr                  = "error"
 )
 
+// RunnerOS is operating system of runner
+type RunnerOS string
+
+// RunnerOS variables
+const (
+	RunnerOSLinux   RunnerOS = "linux"
+	RunnerOSWindows RunnerOS = "windows"
+)
+
+// UnmarshalRunnerOS cast type from string to RunnerOS.
+// return empty string if input is unknown
+func UnmarshalRunnerOS(in string) RunnerOS {
+	switch strings.ToLower(in) {
+	case string(RunnerOSLinux):
+		return RunnerOSLinux
+	case string(RunnerOSWindows):
+		return RunnerOSWindows
+	}
+
+	return ""
+}
+
+// RunnerArch is CPU architecture of runner
+type RunnerArch string
+
+// RunnerArch variables
+const (
+	RunnerArchX64   RunnerArch = "x64"
+	RunnerArchARM64 RunnerArch = "arm64"
+)
+
+// UnmarshalRunnerArch cast type from string to RunnerArch.
+// return empty string if input is unknown
+func UnmarshalRunnerArch(in string) RunnerArch {
+	switch strings.ToLower(in) {
+	case string(RunnerArchX64):
+		return RunnerArchX64
+	case string(RunnerArchARM64):
+		return RunnerArchARM64
+	}
+
+	return ""
+}
+
 // Job is a runner job
 type Job struct {
 	UUID           uuid.UUID      `db:"uuid"`
@@ -175,6 +320,8 @@ type Job struct {
 	Repository     string         `db:"repository"` // repo (:owner/:repo)
 	CheckEventJSON string         `db:"check_event"`
 	TargetID       uuid.UUID      `db:"target_id"`
+	TraceParent    string         `db:"trace_parent"`                   // W3C traceparent of span that enqueued this job
+	ReceivedAt     sql.NullTime   `db:"received_at" json:"received_at"` // time that myshoes received webhook
 	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
 	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
 }
@@ -206,6 +353,7 @@ type Runner struct {
 	Deleted        bool           `db:"deleted"`
 	Status         RunnerStatus   `db:"status"`
 	ResourceType   ResourceType   `db:"resource_type"`
+	RunnerArch     RunnerArch     `db:"runner_arch"`
 	RunnerUser     sql.NullString `db:"runner_user" json:"runner_user"`
 	ProviderURL    sql.NullString `db:"provider_url" json:"provider_url"`
 	RepositoryURL  string         `db:"repository_url"`
@@ -213,6 +361,232 @@ type Runner struct {
 	CreatedAt      time.Time      `db:"created_at"`
 	UpdatedAt      time.Time      `db:"updated_at"`
 	DeletedAt      sql.NullTime   `db:"deleted_at"`
+
+	// a job that runner actually executed, set by workflow_job in_progress event
+	ExecutedRepository sql.NullString `db:"executed_repository"`
+	ExecutedRunID      sql.NullInt64  `db:"executed_run_id"`
+	ExecutedJobID      sql.NullInt64  `db:"executed_job_id"`
+	ExecutedAt         sql.NullTime   `db:"executed_at"`
+
+	// InstanceCreatedAt is time that shoes-provider created an instance
+	InstanceCreatedAt sql.NullTime `db:"instance_created_at"`
+	// OnlineAt is time that runner was found as online in GitHub
+	OnlineAt sql.NullTime `db:"online_at"`
+}
+
+// ExecutedJob is a GitHub job that runner actually executed
+type ExecutedJob struct {
+	Repository string // :owner/:repo
+	RunID      int64
+	JobID      int64
+	ExecutedAt time.Time
+}
+
+// RequestedJobID return ID of GitHub job that requested runner.
+// return 0 if it is unknown (e.g. check_run mode)
+func (r *Runner) RequestedJobID() int64 {
+	jobID, err := gh.ExtractWorkflowJobID([]byte(r.RequestWebhook))
+	if err != nil {
+		return 0
+	}
+	return jobID
+}
+
+// IsJobMismatched return true if runner executed other job than requested job
+func (r *Runner) IsJobMismatched() bool {
+	requested := r.RequestedJobID()
+	if !r.ExecutedJobID.Valid || requested == 0 {
+		return false
+	}
+	return r.ExecutedJobID.Int64 != requested
+}
+
+// ScriptTemplate is a named template of setup script
+type ScriptTemplate struct {
+	Name        string            `db:"name" json:"name"`
+	Script      string            `db:"script" json:"script"` // replace default setup script if set
+	PreInstall  string            `db:"pre_install" json:"pre_install"`
+	PostInstall string            `db:"post_install" json:"post_install"`
+	Environment ScriptEnvironment `db:"environment" json:"environment"`
+	Labels      ScriptLabels      `db:"labels" json:"labels"`
+	CreatedAt   time.Time         `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time         `db:"updated_at" json:"updated_at"`
+}
+
+// APIToken is a token of REST API that restricted to targets in scope prefix
+type APIToken struct {
+	UUID        uuid.UUID    `db:"uuid"`
+	Name        string       `db:"name"`
+	TokenHash   string       `db:"token_hash"` // hex of SHA-256, raw token is not stored
+	Role        string       `db:"role"`
+	ScopePrefix string       `db:"scope_prefix"` // owner of scope, "*" suffix matches prefix of owner
+	CreatedBy   string       `db:"created_by"`
+	CreatedAt   time.Time    `db:"created_at"`
+	RevokedAt   sql.NullTime `db:"revoked_at"`
+}
+
+// AuditLog is a record of operation that changes configuration or resources
+type AuditLog struct {
+	UUID         uuid.UUID    `db:"uuid" json:"id"`
+	Actor        string       `db:"actor" json:"actor"`   // principal of request (e.g. "token:ops"), "anonymous" if not authenticated
+	Action       string       `db:"action" json:"action"` // e.g. "target.update"
+	ResourceType string       `db:"resource_type" json:"resource_type"`
+	ResourceID   string       `db:"resource_id" json:"resource_id"`
+	Changes      AuditChanges `db:"changes" json:"changes"`
+	CreatedAt    time.Time    `db:"created_at" json:"created_at"`
+}
+
+// TargetStatusHistory is a record of change of target status
+type TargetStatusHistory struct {
+	ID          int64        `db:"id"`
+	TargetID    uuid.UUID    `db:"target_id"`
+	Status      TargetStatus `db:"status"`
+	Description string       `db:"description"`
+	CreatedAt   time.Time    `db:"created_at"`
+}
+
+// MaintenanceWindow is a period that starter does not provision new runners
+type MaintenanceWindow struct {
+	UUID      uuid.UUID    `db:"uuid"`
+	Reason    string       `db:"reason"`
+	StartAt   time.Time    `db:"start_at"`
+	EndAt     sql.NullTime `db:"end_at"` // continue until deleted if not valid
+	CreatedBy string       `db:"created_by"`
+	CreatedAt time.Time    `db:"created_at"`
+}
+
+// IsActive return true if now is in window
+func (w MaintenanceWindow) IsActive(now time.Time) bool {
+	if now.Before(w.StartAt) {
+		return false
+	}
+	return !w.EndAt.Valid || now.Before(w.EndAt.Time)
+}
+
+// IsInMaintenance return true if any maintenance window is active
+func IsInMaintenance(ctx context.Context, ds Datastore, now time.Time) (bool, error) {
+	windows, err := ds.ListMaintenanceWindows(ctx, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
+	for _, w := range windows {
+		if w.IsActive(now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RuntimeConfig is saved runtime config that is changed by REST API
+type RuntimeConfig struct {
+	Config    string    `db:"config"` // JSON of config.Runtime
+	UpdatedBy string    `db:"updated_by"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// AuditLogFilter is filter for ListAuditLogs.
+// a zero value of field is not used for filtering.
+// logs are sorted by created_at in descending order.
+type AuditLogFilter struct {
+	Actor        string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Since        time.Time // created_at >= Since
+	Until        time.Time // created_at < Until
+	Limit        int
+	Offset       int
+}
+
+// Match check audit log matches filter without Limit and Offset
+func (f AuditLogFilter) Match(l AuditLog) bool {
+	switch {
+	case f.Actor != "" && f.Actor != l.Actor:
+		return false
+	case f.Action != "" && f.Action != l.Action:
+		return false
+	case f.ResourceType != "" && f.ResourceType != l.ResourceType:
+		return false
+	case f.ResourceID != "" && f.ResourceID != l.ResourceID:
+		return false
+	case !f.Since.IsZero() && l.CreatedAt.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !l.CreatedAt.Before(f.Until):
+		return false
+	}
+
+	return true
+}
+
+// RunnerFilterStatus is status of runner in RunnerFilter
+type RunnerFilterStatus string
+
+// RunnerFilterStatus variables
+const (
+	RunnerFilterStatusAll     RunnerFilterStatus = ""
+	RunnerFilterStatusRunning RunnerFilterStatus = "running"
+	RunnerFilterStatusDeleted RunnerFilterStatus = "deleted"
+)
+
+// RunnerFilter is filter for ListRunnersWithFilter.
+// a zero value of field is not used for filtering.
+// runners are sorted by created_at in descending order.
+type RunnerFilter struct {
+	Status     RunnerFilterStatus
+	TargetID   uuid.UUID
+	Repository string    // :owner/:repo
+	Since      time.Time // created_at >= Since
+	Until      time.Time // created_at < Until
+	Limit      int
+	Offset     int
+}
+
+// Match check runner matches filter without Limit and Offset
+func (f RunnerFilter) Match(r Runner) bool {
+	switch {
+	case f.Status == RunnerFilterStatusRunning && r.Deleted:
+		return false
+	case f.Status == RunnerFilterStatusDeleted && !r.Deleted:
+		return false
+	case !uuid.Equal(f.TargetID, uuid.Nil) && !uuid.Equal(f.TargetID, r.TargetID):
+		return false
+	case f.Repository != "" && !strings.HasSuffix(r.RepositoryURL, "/"+f.Repository):
+		return false
+	case !f.Since.IsZero() && r.CreatedAt.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !r.CreatedAt.Before(f.Until):
+		return false
+	}
+
+	return true
+}
+
+// JobFilter is filter for ListJobsWithFilter.
+// a zero value of field is not used for filtering.
+// jobs are sorted by created_at in descending order.
+type JobFilter struct {
+	TargetID   uuid.UUID
+	Repository string    // :owner/:repo
+	Since      time.Time // created_at >= Since
+	Until      time.Time // created_at < Until
+	Limit      int
+	Offset     int
+}
+
+// Match check job matches filter without Limit and Offset
+func (f JobFilter) Match(j Job) bool {
+	switch {
+	case !uuid.Equal(f.TargetID, uuid.Nil) && !uuid.Equal(f.TargetID, j.TargetID):
+		return false
+	case f.Repository != "" && f.Repository != j.Repository:
+		return false
+	case !f.Since.IsZero() && j.CreatedAt.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !j.CreatedAt.Before(f.Until):
+		return false
+	}
+
+	return true
 }
 
 // RunnerStatus is status for runner
@@ -223,4 +597,5 @@ const (
 	RunnerStatusCreated        RunnerStatus = "created" //lint:ignore SA9004 this is status
 	RunnerStatusCompleted                   = "completed"
 	RunnerStatusReachHardLimit              = "reach_hard_limit"
+	RunnerStatusManual                      = "manual"
 )
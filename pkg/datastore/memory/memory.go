@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -18,6 +19,14 @@ type Memory struct {
 	targets map[uuid.UUID]datastore.Target
 	jobs    map[uuid.UUID]datastore.Job
 	runners map[uuid.UUID]datastore.Runner
+
+	scriptTemplates map[string]datastore.ScriptTemplate
+	apiTokens       map[uuid.UUID]datastore.APIToken
+	auditLogs       []datastore.AuditLog
+
+	maintenanceWindows map[uuid.UUID]datastore.MaintenanceWindow
+	statusHistories    []datastore.TargetStatusHistory
+	runtimeConfig      *datastore.RuntimeConfig
 }
 
 // New create map
@@ -26,12 +35,17 @@ func New() (*Memory, error) {
 	t := map[uuid.UUID]datastore.Target{}
 	j := map[uuid.UUID]datastore.Job{}
 	r := map[uuid.UUID]datastore.Runner{}
+	st := map[string]datastore.ScriptTemplate{}
 
 	return &Memory{
-		mu:      m,
-		targets: t,
-		jobs:    j,
-		runners: r,
+		mu:              m,
+		targets:         t,
+		jobs:            j,
+		runners:         r,
+		scriptTemplates: st,
+		apiTokens:       map[uuid.UUID]datastore.APIToken{},
+
+		maintenanceWindows: map[uuid.UUID]datastore.MaintenanceWindow{},
 	}, nil
 }
 
@@ -41,6 +55,11 @@ func (m *Memory) CreateTarget(ctx context.Context, target datastore.Target) erro
 	defer m.mu.Unlock()
 
 	m.targets[target.UUID] = target
+	status := target.Status
+	if status == "" {
+		status = datastore.TargetStatusActive
+	}
+	m.addStatusHistory(target.UUID, status, target.StatusDescription.String)
 	return nil
 }
 
@@ -92,6 +111,7 @@ func (m *Memory) DeleteTarget(ctx context.Context, id uuid.UUID) error {
 	defer m.mu.Unlock()
 
 	delete(m.targets, id)
+	m.addStatusHistory(id, datastore.TargetStatusDeleted, "")
 	return nil
 }
 
@@ -105,6 +125,9 @@ func (m *Memory) UpdateTargetStatus(ctx context.Context, targetID uuid.UUID, new
 		return fmt.Errorf("not found")
 	}
 
+	if t.Status != newStatus || t.StatusDescription.String != description {
+		m.addStatusHistory(targetID, newStatus, description)
+	}
 	t.Status = newStatus
 	if description != "" {
 		t.StatusDescription.Valid = true
@@ -135,7 +158,7 @@ func (m *Memory) UpdateToken(ctx context.Context, targetID uuid.UUID, newToken s
 }
 
 // UpdateTargetParam update parameter of target
-func (m *Memory) UpdateTargetParam(ctx context.Context, targetID uuid.UUID, newResourceType datastore.ResourceType, newProviderURL string) error {
+func (m *Memory) UpdateTargetParam(ctx context.Context, targetID uuid.UUID, newParam datastore.TargetParam) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -143,11 +166,13 @@ func (m *Memory) UpdateTargetParam(ctx context.Context, targetID uuid.UUID, newR
 	if !ok {
 		return fmt.Errorf("not found")
 	}
-	t.ResourceType = newResourceType
-	t.ProviderURL = sql.NullString{
-		String: newProviderURL,
-		Valid:  true,
-	}
+	t.ResourceType = newParam.ResourceType
+	t.ProviderURL = newParam.ProviderURL
+	t.RunnerGroup = newParam.RunnerGroup
+	t.UseJITConfig = newParam.UseJITConfig
+	t.ScriptTemplate = newParam.ScriptTemplate
+	t.RunnerOS = newParam.RunnerOS
+	t.RunnerArch = newParam.RunnerArch
 
 	m.targets[targetID] = t
 	return nil
@@ -158,6 +183,10 @@ func (m *Memory) EnqueueJob(ctx context.Context, job datastore.Job) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = time.Now().UTC()
+		job.UpdatedAt = job.CreatedAt
+	}
 	m.jobs[job.UUID] = job
 	return nil
 }
@@ -175,6 +204,40 @@ func (m *Memory) ListJobs(ctx context.Context) ([]datastore.Job, error) {
 	return jobs, nil
 }
 
+// ListJobsWithFilter get jobs that match filter
+func (m *Memory) ListJobsWithFilter(ctx context.Context, filter datastore.JobFilter) ([]datastore.Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var jobs []datastore.Job
+	for _, j := range m.jobs {
+		if filter.Match(j) {
+			jobs = append(jobs, j)
+		}
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].UUID.String() < jobs[j].UUID.String()
+		}
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+
+	return paginate(jobs, filter.Limit, filter.Offset), nil
+}
+
+// GetJob get a job
+func (m *Memory) GetJob(ctx context.Context, id uuid.UUID) (*datastore.Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	j, ok := m.jobs[id]
+	if !ok {
+		return nil, datastore.ErrNotFound
+	}
+
+	return &j, nil
+}
+
 // DeleteJob delete a job
 func (m *Memory) DeleteJob(ctx context.Context, id uuid.UUID) error {
 	m.mu.Lock()
@@ -189,6 +252,10 @@ func (m *Memory) CreateRunner(ctx context.Context, runner datastore.Runner) erro
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if runner.CreatedAt.IsZero() {
+		runner.CreatedAt = time.Now().UTC()
+		runner.UpdatedAt = runner.CreatedAt
+	}
 	m.runners[runner.UUID] = runner
 
 	return nil
@@ -201,6 +268,9 @@ func (m *Memory) ListRunners(ctx context.Context) ([]datastore.Runner, error) {
 
 	var runners []datastore.Runner
 	for _, r := range m.runners {
+		if r.Deleted {
+			continue
+		}
 		runners = append(runners, r)
 	}
 
@@ -214,7 +284,7 @@ func (m *Memory) ListRunnersByTargetID(ctx context.Context, targetID uuid.UUID)
 
 	var runners []datastore.Runner
 	for _, r := range m.runners {
-		if uuid.Equal(r.TargetID, targetID) {
+		if uuid.Equal(r.TargetID, targetID) && !r.Deleted {
 			runners = append(runners, r)
 		}
 	}
@@ -237,6 +307,25 @@ func (m *Memory) ListRunnersLogBySince(ctx context.Context, since time.Time) ([]
 	return runners, nil
 }
 
+// ListRunnersByPeriod get runners that were running in [from, to), it contains deleted runners
+func (m *Memory) ListRunnersByPeriod(ctx context.Context, from, to time.Time) ([]datastore.Runner, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var runners []datastore.Runner
+	for _, r := range m.runners {
+		if !r.CreatedAt.Before(to) {
+			continue
+		}
+		if r.DeletedAt.Valid && !r.DeletedAt.Time.After(from) {
+			continue
+		}
+		runners = append(runners, r)
+	}
+
+	return runners, nil
+}
+
 // GetRunner get a runner
 func (m *Memory) GetRunner(ctx context.Context, id uuid.UUID) (*datastore.Runner, error) {
 	m.mu.Lock()
@@ -250,12 +339,135 @@ func (m *Memory) GetRunner(ctx context.Context, id uuid.UUID) (*datastore.Runner
 	return &r, nil
 }
 
-// DeleteRunner delete a runner
+// ListRunnersWithFilter get runners that match filter, it contains deleted runners
+func (m *Memory) ListRunnersWithFilter(ctx context.Context, filter datastore.RunnerFilter) ([]datastore.Runner, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var runners []datastore.Runner
+	for _, r := range m.runners {
+		if filter.Match(r) {
+			runners = append(runners, r)
+		}
+	}
+	sort.SliceStable(runners, func(i, j int) bool {
+		if runners[i].CreatedAt.Equal(runners[j].CreatedAt) {
+			return runners[i].UUID.String() < runners[j].UUID.String()
+		}
+		return runners[i].CreatedAt.After(runners[j].CreatedAt)
+	})
+
+	return paginate(runners, filter.Limit, filter.Offset), nil
+}
+
+// SetRunnerOnline set time that runner became online in GitHub
+func (m *Memory) SetRunnerOnline(ctx context.Context, id uuid.UUID, onlineAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.runners[id]
+	if !ok {
+		return datastore.ErrNotFound
+	}
+	r.OnlineAt = sql.NullTime{Time: onlineAt, Valid: true}
+
+	m.runners[id] = r
+	return nil
+}
+
+// SetRunnerExecutedJob set a job that runner actually executed
+func (m *Memory) SetRunnerExecutedJob(ctx context.Context, id uuid.UUID, job datastore.ExecutedJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.runners[id]
+	if !ok {
+		return datastore.ErrNotFound
+	}
+	r.ExecutedRepository = sql.NullString{String: job.Repository, Valid: true}
+	r.ExecutedRunID = sql.NullInt64{Int64: job.RunID, Valid: true}
+	r.ExecutedJobID = sql.NullInt64{Int64: job.JobID, Valid: true}
+	r.ExecutedAt = sql.NullTime{Time: job.ExecutedAt, Valid: true}
+
+	m.runners[id] = r
+	return nil
+}
+
+// DeleteRunner delete a runner.
+// a runner is kept and marked as deleted same as MySQL (runner_detail and runners_deleted),
+// so GetRunner and history queries return it but ListRunners and ListRunnersByTargetID do not.
 func (m *Memory) DeleteRunner(ctx context.Context, id uuid.UUID, deletedAt time.Time, reason datastore.RunnerStatus) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	delete(m.runners, id)
+	// keep deleted runner for history
+	r, ok := m.runners[id]
+	if !ok {
+		return nil
+	}
+	r.Deleted = true
+	r.Status = reason
+	r.DeletedAt = sql.NullTime{Time: deletedAt, Valid: true}
+
+	m.runners[id] = r
+	return nil
+}
+
+// CreateScriptTemplate create a script template
+func (m *Memory) CreateScriptTemplate(ctx context.Context, st datastore.ScriptTemplate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.scriptTemplates[st.Name]; ok {
+		return fmt.Errorf("already exists")
+	}
+	m.scriptTemplates[st.Name] = st
+	return nil
+}
+
+// GetScriptTemplate get a script template
+func (m *Memory) GetScriptTemplate(ctx context.Context, name string) (*datastore.ScriptTemplate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	st, ok := m.scriptTemplates[name]
+	if !ok {
+		return nil, datastore.ErrNotFound
+	}
+	return &st, nil
+}
+
+// ListScriptTemplates get all script templates
+func (m *Memory) ListScriptTemplates(ctx context.Context) ([]datastore.ScriptTemplate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var sts []datastore.ScriptTemplate
+	for _, st := range m.scriptTemplates {
+		sts = append(sts, st)
+	}
+
+	return sts, nil
+}
+
+// UpdateScriptTemplate update a script template
+func (m *Memory) UpdateScriptTemplate(ctx context.Context, st datastore.ScriptTemplate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.scriptTemplates[st.Name]; !ok {
+		return fmt.Errorf("not found")
+	}
+	m.scriptTemplates[st.Name] = st
+	return nil
+}
+
+// DeleteScriptTemplate delete a script template
+func (m *Memory) DeleteScriptTemplate(ctx context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.scriptTemplates, name)
 	return nil
 }
 
@@ -268,3 +480,202 @@ func (m *Memory) GetLock(ctx context.Context) error {
 func (m *Memory) IsLocked(ctx context.Context) (string, error) {
 	return datastore.IsNotLocked, nil
 }
+
+// paginate return a page of items, limit <= 0 means all items
+func paginate[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// CreateAPIToken create an API token
+func (m *Memory) CreateAPIToken(ctx context.Context, token datastore.APIToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, t := range m.apiTokens {
+		if t.TokenHash == token.TokenHash {
+			return fmt.Errorf("token is already exist")
+		}
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now().UTC()
+	}
+	m.apiTokens[token.UUID] = token
+	return nil
+}
+
+// GetAPITokenByHash get an API token that is not revoked
+func (m *Memory) GetAPITokenByHash(ctx context.Context, tokenHash string) (*datastore.APIToken, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, t := range m.apiTokens {
+		if t.TokenHash == tokenHash && !t.RevokedAt.Valid {
+			return &t, nil
+		}
+	}
+	return nil, datastore.ErrNotFound
+}
+
+// ListAPITokens get all API tokens, it contains revoked tokens
+func (m *Memory) ListAPITokens(ctx context.Context) ([]datastore.APIToken, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var ts []datastore.APIToken
+	for _, t := range m.apiTokens {
+		ts = append(ts, t)
+	}
+	sort.SliceStable(ts, func(i, j int) bool {
+		if ts[i].CreatedAt.Equal(ts[j].CreatedAt) {
+			return ts[i].UUID.String() < ts[j].UUID.String()
+		}
+		return ts[i].CreatedAt.Before(ts[j].CreatedAt)
+	})
+	return ts, nil
+}
+
+// RevokeAPIToken revoke an API token
+func (m *Memory) RevokeAPIToken(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.apiTokens[id]
+	if !ok || t.RevokedAt.Valid {
+		return datastore.ErrNotFound
+	}
+	t.RevokedAt = sql.NullTime{Time: revokedAt, Valid: true}
+	m.apiTokens[id] = t
+	return nil
+}
+
+// CreateAuditLog create an audit log
+func (m *Memory) CreateAuditLog(ctx context.Context, log datastore.AuditLog) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now().UTC()
+	}
+	m.auditLogs = append(m.auditLogs, log)
+	return nil
+}
+
+// ListAuditLogs get audit logs that match filter
+func (m *Memory) ListAuditLogs(ctx context.Context, filter datastore.AuditLogFilter) ([]datastore.AuditLog, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var logs []datastore.AuditLog
+	for _, l := range m.auditLogs {
+		if filter.Match(l) {
+			logs = append(logs, l)
+		}
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].CreatedAt.Equal(logs[j].CreatedAt) {
+			return logs[i].UUID.String() < logs[j].UUID.String()
+		}
+		return logs[i].CreatedAt.After(logs[j].CreatedAt)
+	})
+
+	return paginate(logs, filter.Limit, filter.Offset), nil
+}
+
+// CreateMaintenanceWindow create a maintenance window
+func (m *Memory) CreateMaintenanceWindow(ctx context.Context, window datastore.MaintenanceWindow) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if window.CreatedAt.IsZero() {
+		window.CreatedAt = time.Now().UTC()
+	}
+	m.maintenanceWindows[window.UUID] = window
+	return nil
+}
+
+// ListMaintenanceWindows get maintenance windows that are not ended at now
+func (m *Memory) ListMaintenanceWindows(ctx context.Context, now time.Time) ([]datastore.MaintenanceWindow, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var windows []datastore.MaintenanceWindow
+	for _, w := range m.maintenanceWindows {
+		if !w.EndAt.Valid || w.EndAt.Time.After(now) {
+			windows = append(windows, w)
+		}
+	}
+	sort.SliceStable(windows, func(i, j int) bool {
+		if windows[i].StartAt.Equal(windows[j].StartAt) {
+			return windows[i].UUID.String() < windows[j].UUID.String()
+		}
+		return windows[i].StartAt.Before(windows[j].StartAt)
+	})
+	return windows, nil
+}
+
+// DeleteMaintenanceWindow delete a maintenance window
+func (m *Memory) DeleteMaintenanceWindow(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.maintenanceWindows[id]; !ok {
+		return datastore.ErrNotFound
+	}
+	delete(m.maintenanceWindows, id)
+	return nil
+}
+
+// GetRuntimeConfig get saved runtime config
+func (m *Memory) GetRuntimeConfig(ctx context.Context) (*datastore.RuntimeConfig, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.runtimeConfig == nil {
+		return nil, datastore.ErrNotFound
+	}
+	rc := *m.runtimeConfig
+	return &rc, nil
+}
+
+// PutRuntimeConfig save runtime config
+func (m *Memory) PutRuntimeConfig(ctx context.Context, rc datastore.RuntimeConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rc.UpdatedAt = time.Now().UTC()
+	m.runtimeConfig = &rc
+	return nil
+}
+
+// addStatusHistory record a change of target status, must be called with lock
+func (m *Memory) addStatusHistory(targetID uuid.UUID, status datastore.TargetStatus, description string) {
+	m.statusHistories = append(m.statusHistories, datastore.TargetStatusHistory{
+		ID:          int64(len(m.statusHistories) + 1),
+		TargetID:    targetID,
+		Status:      status,
+		Description: description,
+		CreatedAt:   time.Now().UTC(),
+	})
+}
+
+// ListTargetStatusHistory get changes of target status
+func (m *Memory) ListTargetStatusHistory(ctx context.Context, targetID uuid.UUID, limit, offset int) ([]datastore.TargetStatusHistory, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var histories []datastore.TargetStatusHistory
+	for i := len(m.statusHistories) - 1; i >= 0; i-- {
+		if uuid.Equal(m.statusHistories[i].TargetID, targetID) {
+			histories = append(histories, m.statusHistories[i])
+		}
+	}
+
+	return paginate(histories, limit, offset), nil
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+func TestMemory_DeleteRunner(t *testing.T) {
+	ctx := context.Background()
+	m, err := New()
+	if err != nil {
+		t.Fatalf("failed to create datastore: %+v", err)
+	}
+
+	targetID := uuid.NewV4()
+	runnerID := uuid.NewV4()
+	if err := m.CreateRunner(ctx, datastore.Runner{UUID: runnerID, TargetID: targetID}); err != nil {
+		t.Fatalf("failed to create runner: %+v", err)
+	}
+	if err := m.DeleteRunner(ctx, runnerID, time.Now(), datastore.RunnerStatusCompleted); err != nil {
+		t.Fatalf("failed to delete runner: %+v", err)
+	}
+
+	got, err := m.GetRunner(ctx, runnerID)
+	if err != nil {
+		t.Fatalf("deleted runner must be found: %+v", err)
+	}
+	if !got.Deleted || got.Status != datastore.RunnerStatusCompleted || !got.DeletedAt.Valid {
+		t.Errorf("runner must be marked as deleted, but got %+v", got)
+	}
+
+	running, err := m.ListRunners(ctx)
+	if err != nil {
+		t.Fatalf("failed to list runners: %+v", err)
+	}
+	byTarget, err := m.ListRunnersByTargetID(ctx, targetID)
+	if err != nil {
+		t.Fatalf("failed to list runners by target: %+v", err)
+	}
+	if len(running) != 0 || len(byTarget) != 0 {
+		t.Errorf("deleted runner must not be listed, but got %+v, %+v", running, byTarget)
+	}
+
+	logs, err := m.ListRunnersLogBySince(ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("failed to list runner logs: %+v", err)
+	}
+	if len(logs) != 1 {
+		t.Errorf("deleted runner must be in logs, but got %+v", logs)
+	}
+}
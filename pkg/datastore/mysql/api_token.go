@@ -0,0 +1,66 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+// CreateAPIToken create an API token
+func (m *MySQL) CreateAPIToken(ctx context.Context, token datastore.APIToken) error {
+	query := `INSERT INTO api_tokens(uuid, name, token_hash, role, scope_prefix, created_by) VALUES (?, ?, ?, ?, ?, ?)`
+	if _, err := m.Conn.ExecContext(ctx, query, token.UUID.String(), token.Name, token.TokenHash, token.Role, token.ScopePrefix, token.CreatedBy); err != nil {
+		return fmt.Errorf("failed to execute INSERT query: %w", err)
+	}
+
+	return nil
+}
+
+// GetAPITokenByHash get an API token that is not revoked
+func (m *MySQL) GetAPITokenByHash(ctx context.Context, tokenHash string) (*datastore.APIToken, error) {
+	var t datastore.APIToken
+	query := `SELECT uuid, name, token_hash, role, scope_prefix, created_by, created_at, revoked_at FROM api_tokens WHERE token_hash = ? AND revoked_at IS NULL`
+	if err := m.Conn.GetContext(ctx, &t, query, tokenHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, datastore.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
+	return &t, nil
+}
+
+// ListAPITokens get all API tokens, it contains revoked tokens
+func (m *MySQL) ListAPITokens(ctx context.Context) ([]datastore.APIToken, error) {
+	var ts []datastore.APIToken
+	query := `SELECT uuid, name, token_hash, role, scope_prefix, created_by, created_at, revoked_at FROM api_tokens ORDER BY created_at, uuid`
+	if err := m.Conn.SelectContext(ctx, &ts, query); err != nil {
+		return nil, fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
+	return ts, nil
+}
+
+// RevokeAPIToken revoke an API token
+func (m *MySQL) RevokeAPIToken(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	query := `UPDATE api_tokens SET revoked_at = ? WHERE uuid = ? AND revoked_at IS NULL`
+	result, err := m.Conn.ExecContext(ctx, query, revokedAt, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to execute UPDATE query: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return datastore.ErrNotFound
+	}
+
+	return nil
+}
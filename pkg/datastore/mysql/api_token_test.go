@@ -0,0 +1,60 @@
+package mysql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/internal/testutils"
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+var testAPIToken = datastore.APIToken{
+	UUID:        uuid.FromStringOrNil("5e0f7d2c-6b4a-4c1e-9a4f-2d1b3c8e7f60"),
+	Name:        "team-a",
+	TokenHash:   "0123456789abcdef",
+	Role:        "admin",
+	ScopePrefix: "team-a-*",
+	CreatedBy:   "token:ops",
+}
+
+func TestMySQL_APIToken(t *testing.T) {
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	ctx := context.Background()
+	if err := testDatastore.CreateAPIToken(ctx, testAPIToken); err != nil {
+		t.Fatalf("failed to create API token: %+v", err)
+	}
+
+	got, err := testDatastore.GetAPITokenByHash(ctx, testAPIToken.TokenHash)
+	if err != nil {
+		t.Fatalf("failed to get API token: %+v", err)
+	}
+	got.CreatedAt = time.Time{}
+	if diff := cmp.Diff(&testAPIToken, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := testDatastore.RevokeAPIToken(ctx, testAPIToken.UUID, time.Now()); err != nil {
+		t.Fatalf("failed to revoke API token: %+v", err)
+	}
+	if _, err := testDatastore.GetAPITokenByHash(ctx, testAPIToken.TokenHash); !errors.Is(err, datastore.ErrNotFound) {
+		t.Fatalf("revoked token must be not found, but got %+v", err)
+	}
+	if err := testDatastore.RevokeAPIToken(ctx, testAPIToken.UUID, time.Now()); !errors.Is(err, datastore.ErrNotFound) {
+		t.Fatalf("already revoked token must be not found, but got %+v", err)
+	}
+
+	ts, err := testDatastore.ListAPITokens(ctx)
+	if err != nil {
+		t.Fatalf("failed to list API tokens: %+v", err)
+	}
+	if len(ts) != 1 || !ts[0].RevokedAt.Valid {
+		t.Fatalf("list must contain revoked token, but got %+v", ts)
+	}
+}
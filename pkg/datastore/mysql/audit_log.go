@@ -0,0 +1,63 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+// CreateAuditLog create an audit log
+func (m *MySQL) CreateAuditLog(ctx context.Context, log datastore.AuditLog) error {
+	query := `INSERT INTO audit_logs(uuid, actor, action, resource_type, resource_id, changes, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	if _, err := m.Conn.ExecContext(ctx, query, log.UUID.String(), log.Actor, log.Action, log.ResourceType, log.ResourceID, log.Changes, log.CreatedAt); err != nil {
+		return fmt.Errorf("failed to execute INSERT query: %w", err)
+	}
+
+	return nil
+}
+
+// ListAuditLogs get audit logs that match filter
+func (m *MySQL) ListAuditLogs(ctx context.Context, filter datastore.AuditLogFilter) ([]datastore.AuditLog, error) {
+	query := `SELECT uuid, actor, action, resource_type, resource_id, changes, created_at FROM audit_logs`
+
+	var conditions []string
+	var args []interface{}
+	if filter.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.ResourceType != "" {
+		conditions = append(conditions, "resource_type = ?")
+		args = append(args, filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		conditions = append(conditions, "resource_id = ?")
+		args = append(args, filter.ResourceID)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.Until)
+	}
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, uuid"
+	query, args = appendLimitOffset(query, args, filter.Limit, filter.Offset)
+
+	var logs []datastore.AuditLog
+	if err := m.Conn.SelectContext(ctx, &logs, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
+	return logs, nil
+}
@@ -0,0 +1,85 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/internal/testutils"
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+func TestMySQL_AuditLog(t *testing.T) {
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	ctx := context.Background()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	logs := []datastore.AuditLog{
+		{
+			UUID:         uuid.NewV4(),
+			Actor:        "token:ops",
+			Action:       "target.update",
+			ResourceType: "target",
+			ResourceID:   testTargetID.String(),
+			Changes:      datastore.AuditChanges{{Field: "resource_type", Old: "nano", New: "micro"}},
+			CreatedAt:    base,
+		},
+		{
+			UUID:         uuid.NewV4(),
+			Actor:        "anonymous",
+			Action:       "config.debug",
+			ResourceType: "config",
+			ResourceID:   "debug",
+			Changes:      datastore.AuditChanges{{Field: "debug", Old: false, New: true}},
+			CreatedAt:    base.Add(time.Millisecond),
+		},
+	}
+	for _, l := range logs {
+		if err := testDatastore.CreateAuditLog(ctx, l); err != nil {
+			t.Fatalf("failed to create audit log: %+v", err)
+		}
+	}
+
+	tests := []struct {
+		input datastore.AuditLogFilter
+		want  []datastore.AuditLog
+	}{
+		{
+			input: datastore.AuditLogFilter{},
+			want:  []datastore.AuditLog{logs[1], logs[0]},
+		},
+		{
+			input: datastore.AuditLogFilter{ResourceType: "target", ResourceID: testTargetID.String()},
+			want:  []datastore.AuditLog{logs[0]},
+		},
+		{
+			input: datastore.AuditLogFilter{Actor: "anonymous", Action: "config.debug"},
+			want:  []datastore.AuditLog{logs[1]},
+		},
+		{
+			input: datastore.AuditLogFilter{Since: base.Add(time.Millisecond)},
+			want:  []datastore.AuditLog{logs[1]},
+		},
+		{
+			input: datastore.AuditLogFilter{Limit: 1, Offset: 1},
+			want:  []datastore.AuditLog{logs[0]},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := testDatastore.ListAuditLogs(ctx, test.input)
+		if err != nil {
+			t.Fatalf("failed to list audit logs: %+v", err)
+		}
+		for i := range got {
+			got[i].CreatedAt = got[i].CreatedAt.UTC()
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
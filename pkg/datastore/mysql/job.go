@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
 	uuid "github.com/satori/go.uuid"
 	"github.com/whywaita/myshoes/pkg/datastore"
@@ -12,8 +13,8 @@ import (
 
 // EnqueueJob add a job
 func (m *MySQL) EnqueueJob(ctx context.Context, job datastore.Job) error {
-	query := `INSERT INTO jobs(uuid, ghe_domain, repository, check_event, target_id) VALUES (?, ?, ?, ?, ?)`
-	if _, err := m.Conn.ExecContext(ctx, query, job.UUID, job.GHEDomain, job.Repository, job.CheckEventJSON, job.TargetID.String()); err != nil {
+	query := `INSERT INTO jobs(uuid, ghe_domain, repository, check_event, target_id, trace_parent, received_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	if _, err := m.Conn.ExecContext(ctx, query, job.UUID, job.GHEDomain, job.Repository, job.CheckEventJSON, job.TargetID.String(), job.TraceParent, job.ReceivedAt); err != nil {
 		return fmt.Errorf("failed to execute INSERT query: %w", err)
 	}
 
@@ -30,7 +31,7 @@ func (m *MySQL) EnqueueJob(ctx context.Context, job datastore.Job) error {
 // ListJobs get all jobs
 func (m *MySQL) ListJobs(ctx context.Context) ([]datastore.Job, error) {
 	var jobs []datastore.Job
-	query := `SELECT uuid, ghe_domain, repository, check_event, target_id, created_at, updated_at FROM jobs`
+	query := `SELECT uuid, ghe_domain, repository, check_event, target_id, trace_parent, received_at, created_at, updated_at FROM jobs`
 	if err := m.Conn.SelectContext(ctx, &jobs, query); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, datastore.ErrNotFound
@@ -42,6 +43,57 @@ func (m *MySQL) ListJobs(ctx context.Context) ([]datastore.Job, error) {
 	return jobs, nil
 }
 
+// ListJobsWithFilter get jobs that match filter
+func (m *MySQL) ListJobsWithFilter(ctx context.Context, filter datastore.JobFilter) ([]datastore.Job, error) {
+	query := `SELECT uuid, ghe_domain, repository, check_event, target_id, trace_parent, received_at, created_at, updated_at FROM jobs`
+
+	var conditions []string
+	var args []interface{}
+	if !uuid.Equal(filter.TargetID, uuid.Nil) {
+		conditions = append(conditions, "target_id = ?")
+		args = append(args, filter.TargetID.String())
+	}
+	if filter.Repository != "" {
+		conditions = append(conditions, "repository = ?")
+		args = append(args, filter.Repository)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.Until)
+	}
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, uuid"
+	query, args = appendLimitOffset(query, args, filter.Limit, filter.Offset)
+
+	var jobs []datastore.Job
+	if err := m.Conn.SelectContext(ctx, &jobs, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// GetJob get a job
+func (m *MySQL) GetJob(ctx context.Context, id uuid.UUID) (*datastore.Job, error) {
+	var j datastore.Job
+	query := `SELECT uuid, ghe_domain, repository, check_event, target_id, trace_parent, received_at, created_at, updated_at FROM jobs WHERE uuid = ?`
+	if err := m.Conn.GetContext(ctx, &j, query, id.String()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, datastore.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
+	return &j, nil
+}
+
 // DeleteJob delete a job
 func (m *MySQL) DeleteJob(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM jobs WHERE uuid = ?`
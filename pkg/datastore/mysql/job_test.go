@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 	"testing"
 	"time"
 
@@ -146,6 +147,144 @@ func TestMySQL_ListJobs(t *testing.T) {
 	}
 }
 
+func TestMySQL_ListJobsWithFilter(t *testing.T) {
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	if err := testDatastore.CreateTarget(context.Background(), datastore.Target{
+		UUID:           testTargetID,
+		Scope:          testScopeRepo,
+		GitHubToken:    testGitHubToken,
+		TokenExpiredAt: testTime,
+		ResourceType:   datastore.ResourceTypeNano,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+
+	otherJobID := uuid.FromStringOrNil("2b4e5b7a-e3c1-4829-9cfd-eac4183f2c95")
+	for _, j := range []datastore.Job{
+		{
+			UUID:           testJobID,
+			Repository:     testScopeRepo,
+			CheckEventJSON: `{"example": "json"}`,
+			TargetID:       testTargetID,
+		},
+		{
+			UUID:           otherJobID,
+			Repository:     testScopeRepo + "-other",
+			CheckEventJSON: `{"example": "json"}`,
+			TargetID:       testTargetID,
+		},
+	} {
+		if err := testDatastore.EnqueueJob(context.Background(), j); err != nil {
+			t.Fatalf("failed to enqueue job: %+v", err)
+		}
+	}
+
+	tests := []struct {
+		input datastore.JobFilter
+		want  []uuid.UUID
+	}{
+		{
+			input: datastore.JobFilter{},
+			want:  []uuid.UUID{testJobID, otherJobID},
+		},
+		{
+			input: datastore.JobFilter{Repository: testScopeRepo},
+			want:  []uuid.UUID{testJobID},
+		},
+		{
+			input: datastore.JobFilter{TargetID: testTargetID, Repository: testScopeRepo, Limit: 1},
+			want:  []uuid.UUID{testJobID},
+		},
+		{
+			input: datastore.JobFilter{Repository: testScopeRepo, Offset: 1},
+			want:  nil,
+		},
+		{
+			input: datastore.JobFilter{Since: time.Now().Add(time.Hour)},
+			want:  nil,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := testDatastore.ListJobsWithFilter(context.Background(), test.input)
+		if err != nil {
+			t.Fatalf("failed to get jobs: %+v", err)
+		}
+		var gotIDs []uuid.UUID
+		for _, j := range got {
+			gotIDs = append(gotIDs, j.UUID)
+		}
+		// order of jobs created in same second is not stable
+		sort.Slice(gotIDs, func(i, j int) bool { return gotIDs[i].String() < gotIDs[j].String() })
+
+		if diff := cmp.Diff(test.want, gotIDs); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestMySQL_GetJob(t *testing.T) {
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	if err := testDatastore.CreateTarget(context.Background(), datastore.Target{
+		UUID:           testTargetID,
+		Scope:          testScopeRepo,
+		GitHubToken:    testGitHubToken,
+		TokenExpiredAt: testTime,
+		ResourceType:   datastore.ResourceTypeNano,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+
+	if err := testDatastore.EnqueueJob(context.Background(), datastore.Job{
+		UUID:           testJobID,
+		Repository:     testScopeRepo,
+		CheckEventJSON: `{"example": "json"}`,
+		TargetID:       testTargetID,
+	}); err != nil {
+		t.Fatalf("failed to enqueue job: %+v", err)
+	}
+
+	tests := []struct {
+		input uuid.UUID
+		want  *datastore.Job
+		err   error
+	}{
+		{
+			input: testJobID,
+			want: &datastore.Job{
+				UUID:           testJobID,
+				Repository:     testScopeRepo,
+				CheckEventJSON: `{"example": "json"}`,
+				TargetID:       testTargetID,
+			},
+		},
+		{
+			input: uuid.NewV4(),
+			want:  nil,
+			err:   datastore.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := testDatastore.GetJob(context.Background(), test.input)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("want error %v, but got %+v", test.err, err)
+		}
+		if got != nil {
+			got.CreatedAt = time.Time{}
+			got.UpdatedAt = time.Time{}
+		}
+
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
 func TestMySQL_DeleteJob(t *testing.T) {
 	testDatastore, teardown := testutils.GetTestDatastore()
 	defer teardown()
@@ -0,0 +1,49 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+// CreateMaintenanceWindow create a maintenance window
+func (m *MySQL) CreateMaintenanceWindow(ctx context.Context, window datastore.MaintenanceWindow) error {
+	query := `INSERT INTO maintenance_windows(uuid, reason, start_at, end_at, created_by) VALUES (?, ?, ?, ?, ?)`
+	if _, err := m.Conn.ExecContext(ctx, query, window.UUID.String(), window.Reason, window.StartAt, window.EndAt, window.CreatedBy); err != nil {
+		return fmt.Errorf("failed to execute INSERT query: %w", err)
+	}
+
+	return nil
+}
+
+// ListMaintenanceWindows get maintenance windows that are not ended at now
+func (m *MySQL) ListMaintenanceWindows(ctx context.Context, now time.Time) ([]datastore.MaintenanceWindow, error) {
+	var windows []datastore.MaintenanceWindow
+	query := `SELECT uuid, reason, start_at, end_at, created_by, created_at FROM maintenance_windows WHERE end_at IS NULL OR end_at > ? ORDER BY start_at, uuid`
+	if err := m.Conn.SelectContext(ctx, &windows, query, now); err != nil {
+		return nil, fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
+	return windows, nil
+}
+
+// DeleteMaintenanceWindow delete a maintenance window
+func (m *MySQL) DeleteMaintenanceWindow(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM maintenance_windows WHERE uuid = ?`
+	result, err := m.Conn.ExecContext(ctx, query, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to execute DELETE query: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return datastore.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/internal/testutils"
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+func TestMySQL_MaintenanceWindow(t *testing.T) {
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	windows := []datastore.MaintenanceWindow{
+		{ // ended
+			UUID:      uuid.NewV4(),
+			Reason:    "upgrade",
+			StartAt:   now.Add(-2 * time.Hour),
+			EndAt:     sql.NullTime{Time: now.Add(-time.Hour), Valid: true},
+			CreatedBy: "token:ops",
+		},
+		{ // active without end
+			UUID:      uuid.NewV4(),
+			Reason:    "incident",
+			StartAt:   now.Add(-time.Minute),
+			CreatedBy: "token:ops",
+		},
+		{ // scheduled
+			UUID:      uuid.NewV4(),
+			Reason:    "weekly",
+			StartAt:   now.Add(time.Hour),
+			EndAt:     sql.NullTime{Time: now.Add(2 * time.Hour), Valid: true},
+			CreatedBy: "anonymous",
+		},
+	}
+	for _, w := range windows {
+		if err := testDatastore.CreateMaintenanceWindow(ctx, w); err != nil {
+			t.Fatalf("failed to create maintenance window: %+v", err)
+		}
+	}
+
+	got, err := testDatastore.ListMaintenanceWindows(ctx, now)
+	if err != nil {
+		t.Fatalf("failed to list maintenance windows: %+v", err)
+	}
+	for i := range got {
+		got[i].CreatedAt = time.Time{}
+		got[i].StartAt = got[i].StartAt.UTC()
+		got[i].EndAt.Time = got[i].EndAt.Time.UTC()
+	}
+	if diff := cmp.Diff(windows[1:], got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := testDatastore.DeleteMaintenanceWindow(ctx, windows[1].UUID); err != nil {
+		t.Fatalf("failed to delete maintenance window: %+v", err)
+	}
+	if err := testDatastore.DeleteMaintenanceWindow(ctx, windows[1].UUID); !errors.Is(err, datastore.ErrNotFound) {
+		t.Fatalf("must be not found, but got %+v", err)
+	}
+	inMaintenance, err := datastore.IsInMaintenance(ctx, testDatastore, now)
+	if err != nil {
+		t.Fatalf("failed to check maintenance: %+v", err)
+	}
+	if inMaintenance {
+		t.Errorf("must not be in maintenance after delete active window")
+	}
+}
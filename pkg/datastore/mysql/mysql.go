@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
@@ -51,3 +52,23 @@ func getMySQLURL(dsn string) (string, error) {
 
 	return c.FormatDSN(), nil
 }
+
+// appendLimitOffset append LIMIT and OFFSET clause to query if limit or offset is set
+func appendLimitOffset(query string, args []interface{}, limit, offset int) (string, []interface{}) {
+	switch {
+	case limit > 0:
+		query += " LIMIT ? OFFSET ?"
+		return query, append(args, limit, offset)
+	case offset > 0:
+		// MySQL can not use OFFSET without LIMIT, use max value of LIMIT for no limit
+		query += " LIMIT 18446744073709551615 OFFSET ?"
+		return query, append(args, offset)
+	}
+	return query, args
+}
+
+// escapeLike escape special characters in LIKE pattern
+func escapeLike(in string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(in)
+}
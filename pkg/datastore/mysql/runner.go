@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	uuid "github.com/satori/go.uuid"
@@ -21,8 +22,8 @@ func (m *MySQL) CreateRunner(ctx context.Context, runner datastore.Runner) error
 		return fmt.Errorf("failed to execute INSERT query runners: %w", err)
 	}
 
-	queryDetail := `INSERT INTO runner_detail(runner_id, shoes_type, ip_address, target_id, cloud_id, resource_type, runner_user, repository_url, request_webhook, provider_url) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	if _, err := tx.ExecContext(ctx, queryDetail, runner.UUID.String(), runner.ShoesType, runner.IPAddress, runner.TargetID.String(), runner.CloudID, runner.ResourceType, runner.RunnerUser, runner.RepositoryURL, runner.RequestWebhook, runner.ProviderURL); err != nil {
+	queryDetail := `INSERT INTO runner_detail(runner_id, shoes_type, ip_address, target_id, cloud_id, resource_type, runner_arch, runner_user, repository_url, request_webhook, provider_url, instance_created_at, online_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, queryDetail, runner.UUID.String(), runner.ShoesType, runner.IPAddress, runner.TargetID.String(), runner.CloudID, runner.ResourceType, runner.RunnerArch, runner.RunnerUser, runner.RepositoryURL, runner.RequestWebhook, runner.ProviderURL, runner.InstanceCreatedAt, runner.OnlineAt); err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to execute INSERT query runner_detail: %w", err)
 	}
@@ -43,7 +44,7 @@ func (m *MySQL) CreateRunner(ctx context.Context, runner datastore.Runner) error
 // ListRunners get a not deleted runners
 func (m *MySQL) ListRunners(ctx context.Context) ([]datastore.Runner, error) {
 	var runners []datastore.Runner
-	query := `SELECT runner.runner_id, detail.shoes_type, detail.ip_address, detail.target_id, detail.cloud_id, detail.created_at, detail.updated_at, detail.resource_type, detail.repository_url, detail.request_webhook, detail.runner_user, detail.provider_url
+	query := `SELECT runner.runner_id, detail.shoes_type, detail.ip_address, detail.target_id, detail.cloud_id, detail.created_at, detail.updated_at, detail.resource_type, detail.runner_arch, detail.repository_url, detail.request_webhook, detail.runner_user, detail.provider_url, detail.executed_repository, detail.executed_run_id, detail.executed_job_id, detail.executed_at, detail.instance_created_at, detail.online_at
  FROM runners_running AS runner JOIN runner_detail AS detail ON runner.runner_id = detail.runner_id`
 	err := m.Conn.SelectContext(ctx, &runners, query)
 	if err != nil {
@@ -60,7 +61,7 @@ func (m *MySQL) ListRunners(ctx context.Context) ([]datastore.Runner, error) {
 // ListRunnersByTargetID get a not deleted runners that has target_id
 func (m *MySQL) ListRunnersByTargetID(ctx context.Context, targetID uuid.UUID) ([]datastore.Runner, error) {
 	var runners []datastore.Runner
-	query := `SELECT runner.runner_id, detail.shoes_type, detail.ip_address, detail.target_id, detail.cloud_id, detail.created_at, detail.updated_at, detail.resource_type, detail.repository_url, detail.request_webhook, detail.runner_user, detail.provider_url
+	query := `SELECT runner.runner_id, detail.shoes_type, detail.ip_address, detail.target_id, detail.cloud_id, detail.created_at, detail.updated_at, detail.resource_type, detail.runner_arch, detail.repository_url, detail.request_webhook, detail.runner_user, detail.provider_url, detail.executed_repository, detail.executed_run_id, detail.executed_job_id, detail.executed_at, detail.instance_created_at, detail.online_at
  FROM runners_running AS runner JOIN runner_detail AS detail ON runner.runner_id = detail.runner_id WHERE detail.target_id = ?`
 	err := m.Conn.SelectContext(ctx, &runners, query, targetID)
 	if err != nil {
@@ -78,7 +79,7 @@ func (m *MySQL) ListRunnersByTargetID(ctx context.Context, targetID uuid.UUID) (
 func (m *MySQL) ListRunnersLogBySince(ctx context.Context, since time.Time) ([]datastore.Runner, error) {
 	var runners []datastore.Runner
 
-	query := `SELECT runner_id, shoes_type, ip_address, target_id, cloud_id, created_at, updated_at, resource_type, repository_url, request_webhook, runner_user, provider_url FROM runner_detail WHERE created_at > ?`
+	query := `SELECT runner_id, shoes_type, ip_address, target_id, cloud_id, created_at, updated_at, resource_type, runner_arch, repository_url, request_webhook, runner_user, provider_url, executed_repository, executed_run_id, executed_job_id, executed_at, instance_created_at, online_at FROM runner_detail WHERE created_at > ?`
 	err := m.Conn.SelectContext(ctx, &runners, query, since)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -95,7 +96,9 @@ func (m *MySQL) ListRunnersLogBySince(ctx context.Context, since time.Time) ([]d
 func (m *MySQL) GetRunner(ctx context.Context, id uuid.UUID) (*datastore.Runner, error) {
 	var r datastore.Runner
 
-	query := `SELECT runner_id, shoes_type, ip_address, target_id, cloud_id, created_at, updated_at, resource_type, repository_url, request_webhook, runner_user, provider_url FROM runner_detail WHERE runner_id = ?`
+	query := `SELECT detail.runner_id, detail.shoes_type, detail.ip_address, detail.target_id, detail.cloud_id, detail.created_at, detail.updated_at, detail.resource_type, detail.runner_arch, detail.repository_url, detail.request_webhook, detail.runner_user, detail.provider_url, detail.executed_repository, detail.executed_run_id, detail.executed_job_id, detail.executed_at, detail.instance_created_at, detail.online_at,
+ (deleted.runner_id IS NOT NULL) AS deleted, COALESCE(deleted.reason, '') AS status, deleted.created_at AS deleted_at
+ FROM runner_detail AS detail LEFT JOIN runners_deleted AS deleted ON detail.runner_id = deleted.runner_id WHERE detail.runner_id = ?`
 	if err := m.Conn.GetContext(ctx, &r, query, id.String()); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, datastore.ErrNotFound
@@ -107,6 +110,64 @@ func (m *MySQL) GetRunner(ctx context.Context, id uuid.UUID) (*datastore.Runner,
 	return &r, nil
 }
 
+// ListRunnersByPeriod get runners that were running in [from, to), it contains deleted runners
+func (m *MySQL) ListRunnersByPeriod(ctx context.Context, from, to time.Time) ([]datastore.Runner, error) {
+	var runners []datastore.Runner
+	query := `SELECT detail.runner_id, detail.shoes_type, detail.ip_address, detail.target_id, detail.cloud_id, detail.created_at, detail.updated_at, detail.resource_type, detail.runner_arch, detail.repository_url, detail.request_webhook, detail.runner_user, detail.provider_url, detail.executed_repository, detail.executed_run_id, detail.executed_job_id, detail.executed_at, detail.instance_created_at, detail.online_at,
+ (deleted.runner_id IS NOT NULL) AS deleted, COALESCE(deleted.reason, '') AS status, deleted.created_at AS deleted_at
+ FROM runner_detail AS detail LEFT JOIN runners_deleted AS deleted ON detail.runner_id = deleted.runner_id
+ WHERE detail.created_at < ? AND (deleted.runner_id IS NULL OR deleted.created_at > ?)`
+	if err := m.Conn.SelectContext(ctx, &runners, query, to, from); err != nil {
+		return nil, fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
+	return runners, nil
+}
+
+// ListRunnersWithFilter get runners that match filter, it contains deleted runners
+func (m *MySQL) ListRunnersWithFilter(ctx context.Context, filter datastore.RunnerFilter) ([]datastore.Runner, error) {
+	query := `SELECT detail.runner_id, detail.shoes_type, detail.ip_address, detail.target_id, detail.cloud_id, detail.created_at, detail.updated_at, detail.resource_type, detail.runner_arch, detail.repository_url, detail.request_webhook, detail.runner_user, detail.provider_url, detail.executed_repository, detail.executed_run_id, detail.executed_job_id, detail.executed_at, detail.instance_created_at, detail.online_at,
+ (deleted.runner_id IS NOT NULL) AS deleted, COALESCE(deleted.reason, '') AS status, deleted.created_at AS deleted_at
+ FROM runner_detail AS detail LEFT JOIN runners_deleted AS deleted ON detail.runner_id = deleted.runner_id`
+
+	var conditions []string
+	var args []interface{}
+	switch filter.Status {
+	case datastore.RunnerFilterStatusRunning:
+		conditions = append(conditions, "deleted.runner_id IS NULL")
+	case datastore.RunnerFilterStatusDeleted:
+		conditions = append(conditions, "deleted.runner_id IS NOT NULL")
+	}
+	if !uuid.Equal(filter.TargetID, uuid.Nil) {
+		conditions = append(conditions, "detail.target_id = ?")
+		args = append(args, filter.TargetID.String())
+	}
+	if filter.Repository != "" {
+		conditions = append(conditions, "detail.repository_url LIKE ?")
+		args = append(args, "%/"+escapeLike(filter.Repository))
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "detail.created_at >= ?")
+		args = append(args, filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "detail.created_at < ?")
+		args = append(args, filter.Until)
+	}
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY detail.created_at DESC, detail.runner_id"
+	query, args = appendLimitOffset(query, args, filter.Limit, filter.Offset)
+
+	var runners []datastore.Runner
+	if err := m.Conn.SelectContext(ctx, &runners, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
+	return runners, nil
+}
+
 // DeleteRunner delete a runner
 func (m *MySQL) DeleteRunner(ctx context.Context, id uuid.UUID, deletedAt time.Time, reason datastore.RunnerStatus) error {
 	tx := m.Conn.MustBegin()
@@ -130,3 +191,53 @@ func (m *MySQL) DeleteRunner(ctx context.Context, id uuid.UUID, deletedAt time.T
 
 	return nil
 }
+
+// SetRunnerOnline set time that runner became online in GitHub
+func (m *MySQL) SetRunnerOnline(ctx context.Context, id uuid.UUID, onlineAt time.Time) error {
+	query := `UPDATE runner_detail SET online_at = ? WHERE runner_id = ?`
+	result, err := m.Conn.ExecContext(ctx, query, onlineAt, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to execute UPDATE query: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if n == 0 {
+		// affected rows is 0 if values are not changed, so check existence
+		var count int
+		if err := m.Conn.GetContext(ctx, &count, `SELECT COUNT(*) FROM runner_detail WHERE runner_id = ?`, id.String()); err != nil {
+			return fmt.Errorf("failed to execute SELECT query: %w", err)
+		}
+		if count == 0 {
+			return datastore.ErrNotFound
+		}
+	}
+
+	return nil
+}
+
+// SetRunnerExecutedJob set a job that runner actually executed
+func (m *MySQL) SetRunnerExecutedJob(ctx context.Context, id uuid.UUID, job datastore.ExecutedJob) error {
+	query := `UPDATE runner_detail SET executed_repository = ?, executed_run_id = ?, executed_job_id = ?, executed_at = ? WHERE runner_id = ?`
+	result, err := m.Conn.ExecContext(ctx, query, job.Repository, job.RunID, job.JobID, job.ExecutedAt, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to execute UPDATE query: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if n == 0 {
+		// affected rows is 0 if values are not changed, so check existence
+		var count int
+		if err := m.Conn.GetContext(ctx, &count, `SELECT COUNT(*) FROM runner_detail WHERE runner_id = ?`, id.String()); err != nil {
+			return fmt.Errorf("failed to execute SELECT query: %w", err)
+		}
+		if count == 0 {
+			return datastore.ErrNotFound
+		}
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 	"testing"
 	"time"
 
@@ -385,6 +386,232 @@ func TestMySQL_GetRunner(t *testing.T) {
 	}
 }
 
+func TestMySQL_SetRunnerExecutedJob(t *testing.T) {
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	if err := testDatastore.CreateTarget(context.Background(), datastore.Target{
+		UUID:           testTargetID,
+		Scope:          testScopeRepo,
+		GitHubToken:    testGitHubToken,
+		TokenExpiredAt: testTime,
+		ResourceType:   datastore.ResourceTypeNano,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+
+	if err := testDatastore.CreateRunner(context.Background(), datastore.Runner{
+		UUID:           testRunnerID,
+		ShoesType:      "shoes-test",
+		TargetID:       testTargetID,
+		CloudID:        "mycloud-uuid",
+		ResourceType:   datastore.ResourceTypeNano,
+		RepositoryURL:  "https://github.com/octocat/Hello-World",
+		RequestWebhook: "{}",
+	}); err != nil {
+		t.Fatalf("failed to create runner: %+v", err)
+	}
+
+	executedJob := datastore.ExecutedJob{
+		Repository: "octocat/Hello-World",
+		RunID:      100,
+		JobID:      200,
+		ExecutedAt: testTime,
+	}
+	// same event may be received twice
+	for i := 0; i < 2; i++ {
+		if err := testDatastore.SetRunnerExecutedJob(context.Background(), testRunnerID, executedJob); err != nil {
+			t.Fatalf("failed to set executed job: %+v", err)
+		}
+	}
+	if err := testDatastore.SetRunnerExecutedJob(context.Background(), uuid.NewV4(), executedJob); !errors.Is(err, datastore.ErrNotFound) {
+		t.Fatalf("must be ErrNotFound, but got %+v", err)
+	}
+
+	got, err := testDatastore.GetRunner(context.Background(), testRunnerID)
+	if err != nil {
+		t.Fatalf("failed to get runner: %+v", err)
+	}
+	want := datastore.Runner{
+		UUID:               testRunnerID,
+		ShoesType:          "shoes-test",
+		TargetID:           testTargetID,
+		CloudID:            "mycloud-uuid",
+		ResourceType:       datastore.ResourceTypeNano,
+		RepositoryURL:      "https://github.com/octocat/Hello-World",
+		RequestWebhook:     "{}",
+		ExecutedRepository: sql.NullString{String: "octocat/Hello-World", Valid: true},
+		ExecutedRunID:      sql.NullInt64{Int64: 100, Valid: true},
+		ExecutedJobID:      sql.NullInt64{Int64: 200, Valid: true},
+		ExecutedAt:         sql.NullTime{Time: testTime, Valid: true},
+	}
+	got.CreatedAt = time.Time{}
+	got.UpdatedAt = time.Time{}
+	if diff := cmp.Diff(want, *got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMySQL_ListRunnersWithFilter(t *testing.T) {
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	if err := testDatastore.CreateTarget(context.Background(), datastore.Target{
+		UUID:           testTargetID,
+		Scope:          testScopeRepo,
+		GitHubToken:    testGitHubToken,
+		TokenExpiredAt: testTime,
+		ResourceType:   datastore.ResourceTypeNano,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+
+	deletedRunnerID := uuid.FromStringOrNil("8943e412-c0ae-4068-ab24-3e71a13fbe53")
+	for _, id := range []uuid.UUID{testRunnerID, deletedRunnerID} {
+		if err := testDatastore.CreateRunner(context.Background(), datastore.Runner{
+			UUID:           id,
+			ShoesType:      "shoes-test",
+			TargetID:       testTargetID,
+			CloudID:        "mycloud-uuid",
+			ResourceType:   datastore.ResourceTypeNano,
+			RepositoryURL:  "https://github.com/octocat/Hello-World",
+			RequestWebhook: "{}",
+		}); err != nil {
+			t.Fatalf("failed to create runner: %+v", err)
+		}
+	}
+	if err := testDatastore.DeleteRunner(context.Background(), deletedRunnerID, time.Now().UTC(), datastore.RunnerStatusCompleted); err != nil {
+		t.Fatalf("failed to delete runner: %+v", err)
+	}
+
+	tests := []struct {
+		input datastore.RunnerFilter
+		want  []uuid.UUID
+	}{
+		{
+			input: datastore.RunnerFilter{},
+			want:  []uuid.UUID{testRunnerID, deletedRunnerID},
+		},
+		{
+			input: datastore.RunnerFilter{Status: datastore.RunnerFilterStatusRunning},
+			want:  []uuid.UUID{testRunnerID},
+		},
+		{
+			input: datastore.RunnerFilter{Status: datastore.RunnerFilterStatusDeleted, TargetID: testTargetID, Repository: "octocat/Hello-World"},
+			want:  []uuid.UUID{deletedRunnerID},
+		},
+		{
+			input: datastore.RunnerFilter{Repository: "Hello-World"},
+			want:  nil,
+		},
+		{
+			input: datastore.RunnerFilter{Status: datastore.RunnerFilterStatusRunning, Limit: 1},
+			want:  []uuid.UUID{testRunnerID},
+		},
+		{
+			input: datastore.RunnerFilter{Status: datastore.RunnerFilterStatusDeleted, Offset: 1},
+			want:  nil,
+		},
+		{
+			input: datastore.RunnerFilter{Until: time.Now().Add(-time.Hour)},
+			want:  nil,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := testDatastore.ListRunnersWithFilter(context.Background(), test.input)
+		if err != nil {
+			t.Fatalf("failed to list runners: %+v", err)
+		}
+		var gotIDs []uuid.UUID
+		for _, r := range got {
+			gotIDs = append(gotIDs, r.UUID)
+			if uuid.Equal(r.UUID, deletedRunnerID) && (!r.Deleted || r.Status != datastore.RunnerStatusCompleted || !r.DeletedAt.Valid) {
+				t.Errorf("runner must be deleted, but got %+v", r)
+			}
+		}
+		// order of runners created in same second is not stable
+		sort.Slice(gotIDs, func(i, j int) bool { return gotIDs[i].String() < gotIDs[j].String() })
+
+		if diff := cmp.Diff(test.want, gotIDs); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestMySQL_ListRunnersByPeriod(t *testing.T) {
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	if err := testDatastore.CreateTarget(context.Background(), datastore.Target{
+		UUID:           testTargetID,
+		Scope:          testScopeRepo,
+		GitHubToken:    testGitHubToken,
+		TokenExpiredAt: testTime,
+		ResourceType:   datastore.ResourceTypeNano,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+
+	deletedRunnerID := uuid.FromStringOrNil("8943e412-c0ae-4068-ab24-3e71a13fbe53")
+	for _, id := range []uuid.UUID{testRunnerID, deletedRunnerID} {
+		if err := testDatastore.CreateRunner(context.Background(), datastore.Runner{
+			UUID:           id,
+			ShoesType:      "shoes-test",
+			TargetID:       testTargetID,
+			CloudID:        "mycloud-uuid",
+			ResourceType:   datastore.ResourceTypeNano,
+			RepositoryURL:  "https://github.com/octocat/Hello-World",
+			RequestWebhook: "{}",
+		}); err != nil {
+			t.Fatalf("failed to create runner: %+v", err)
+		}
+	}
+	deletedAt := time.Now().UTC().Add(time.Hour)
+	if err := testDatastore.DeleteRunner(context.Background(), deletedRunnerID, deletedAt, datastore.RunnerStatusCompleted); err != nil {
+		t.Fatalf("failed to delete runner: %+v", err)
+	}
+
+	tests := []struct {
+		from time.Time
+		to   time.Time
+		want []uuid.UUID
+	}{
+		{
+			from: time.Now().Add(-time.Hour),
+			to:   time.Now().Add(2 * time.Hour),
+			want: []uuid.UUID{testRunnerID, deletedRunnerID},
+		},
+		{
+			from: time.Now().Add(90 * time.Minute),
+			to:   time.Now().Add(2 * time.Hour),
+			want: []uuid.UUID{testRunnerID},
+		},
+		{
+			from: time.Now().Add(-2 * time.Hour),
+			to:   time.Now().Add(-time.Hour),
+			want: nil,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := testDatastore.ListRunnersByPeriod(context.Background(), test.from, test.to)
+		if err != nil {
+			t.Fatalf("failed to list runners: %+v", err)
+		}
+		var gotIDs []uuid.UUID
+		for _, r := range got {
+			gotIDs = append(gotIDs, r.UUID)
+		}
+		// order of runners created in same second is not stable
+		sort.Slice(gotIDs, func(i, j int) bool { return gotIDs[i].String() < gotIDs[j].String() })
+
+		if diff := cmp.Diff(test.want, gotIDs); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
 func TestMySQL_DeleteRunner(t *testing.T) {
 	testDatastore, teardown := testutils.GetTestDatastore()
 	defer teardown()
@@ -464,7 +691,7 @@ func TestMySQL_DeleteRunner(t *testing.T) {
 
 func getRunnerFromSQL(testDB *sqlx.DB, id uuid.UUID) (*datastore.Runner, error) {
 	var r datastore.Runner
-	query := `SELECT runner_id, shoes_type, ip_address, target_id, cloud_id, created_at, updated_at, resource_type, repository_url, request_webhook, runner_user, provider_url FROM runner_detail WHERE runner_id = ?`
+	query := `SELECT runner_id, shoes_type, ip_address, target_id, cloud_id, created_at, updated_at, resource_type, runner_arch, repository_url, request_webhook, runner_user, provider_url FROM runner_detail WHERE runner_id = ?`
 	stmt, err := testDB.Preparex(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare: %w", err)
@@ -505,3 +732,50 @@ FROM runner_detail AS detail JOIN runners_deleted AS deleted ON detail.runner_id
 	}
 	return &r, nil
 }
+
+func TestMySQL_SetRunnerOnline(t *testing.T) {
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	if err := testDatastore.CreateTarget(context.Background(), datastore.Target{
+		UUID:           testTargetID,
+		Scope:          testScopeRepo,
+		GitHubToken:    testGitHubToken,
+		TokenExpiredAt: testTime,
+		ResourceType:   datastore.ResourceTypeNano,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+
+	instanceCreatedAt := testTime.Add(-1 * time.Minute)
+	if err := testDatastore.CreateRunner(context.Background(), datastore.Runner{
+		UUID:              testRunnerID,
+		ShoesType:         "shoes-test",
+		TargetID:          testTargetID,
+		CloudID:           "mycloud-uuid",
+		ResourceType:      datastore.ResourceTypeNano,
+		RepositoryURL:     "https://github.com/octocat/Hello-World",
+		RequestWebhook:    "{}",
+		InstanceCreatedAt: sql.NullTime{Time: instanceCreatedAt, Valid: true},
+	}); err != nil {
+		t.Fatalf("failed to create runner: %+v", err)
+	}
+
+	if err := testDatastore.SetRunnerOnline(context.Background(), testRunnerID, testTime); err != nil {
+		t.Fatalf("failed to set runner online: %+v", err)
+	}
+	if err := testDatastore.SetRunnerOnline(context.Background(), uuid.NewV4(), testTime); !errors.Is(err, datastore.ErrNotFound) {
+		t.Fatalf("must be ErrNotFound, but got %+v", err)
+	}
+
+	got, err := testDatastore.GetRunner(context.Background(), testRunnerID)
+	if err != nil {
+		t.Fatalf("failed to get runner: %+v", err)
+	}
+	if !got.InstanceCreatedAt.Valid || !got.InstanceCreatedAt.Time.Equal(instanceCreatedAt) {
+		t.Errorf("instance_created_at must be %s, but got %+v", instanceCreatedAt, got.InstanceCreatedAt)
+	}
+	if !got.OnlineAt.Valid || !got.OnlineAt.Time.Equal(testTime) {
+		t.Errorf("online_at must be %s, but got %+v", testTime, got.OnlineAt)
+	}
+}
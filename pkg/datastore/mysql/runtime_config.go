@@ -0,0 +1,37 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+// runtimeConfigID is id of row, runtime config has only one row
+const runtimeConfigID = 1
+
+// GetRuntimeConfig get saved runtime config
+func (m *MySQL) GetRuntimeConfig(ctx context.Context) (*datastore.RuntimeConfig, error) {
+	var rc datastore.RuntimeConfig
+	query := `SELECT config, updated_by, updated_at FROM runtime_configs WHERE id = ?`
+	if err := m.Conn.GetContext(ctx, &rc, query, runtimeConfigID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, datastore.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
+	return &rc, nil
+}
+
+// PutRuntimeConfig save runtime config
+func (m *MySQL) PutRuntimeConfig(ctx context.Context, rc datastore.RuntimeConfig) error {
+	query := `INSERT INTO runtime_configs(id, config, updated_by) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE config = VALUES(config), updated_by = VALUES(updated_by)`
+	if _, err := m.Conn.ExecContext(ctx, query, runtimeConfigID, rc.Config, rc.UpdatedBy); err != nil {
+		return fmt.Errorf("failed to execute INSERT query: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package mysql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/whywaita/myshoes/internal/testutils"
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+func TestMySQL_RuntimeConfig(t *testing.T) {
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	ctx := context.Background()
+	if _, err := testDatastore.GetRuntimeConfig(ctx); !errors.Is(err, datastore.ErrNotFound) {
+		t.Fatalf("must be not found before saved, but got %+v", err)
+	}
+
+	for _, rc := range []datastore.RuntimeConfig{
+		{Config: `{"version":2}`, UpdatedBy: "anonymous"},
+		{Config: `{"version":3}`, UpdatedBy: "token:ops"},
+	} {
+		if err := testDatastore.PutRuntimeConfig(ctx, rc); err != nil {
+			t.Fatalf("failed to put runtime config: %+v", err)
+		}
+	}
+
+	got, err := testDatastore.GetRuntimeConfig(ctx)
+	if err != nil {
+		t.Fatalf("failed to get runtime config: %+v", err)
+	}
+	if got.Config != `{"version":3}` || got.UpdatedBy != "token:ops" {
+		t.Errorf("must be latest runtime config, but got %+v", got)
+	}
+}
@@ -6,6 +6,11 @@ CREATE TABLE `targets` (
     `token_expired_at` TIMESTAMP NOT NULL,
     `resource_type` ENUM('nano', 'micro', 'small', 'medium', 'large', 'xlarge', '2xlarge', '3xlarge', '4xlarge') NOT NULL,
     `provider_url` VARCHAR(255),
+    `runner_group` VARCHAR(255),
+    `use_jit_config` BOOLEAN NOT NULL DEFAULT FALSE,
+    `script_template` VARCHAR(255),
+    `runner_os` VARCHAR(255) NOT NULL DEFAULT 'linux',
+    `runner_arch` VARCHAR(255) NOT NULL DEFAULT 'x64',
     `status` VARCHAR(255) NOT NULL DEFAULT 'active',
     `status_description` VARCHAR(255),
     `created_at` TIMESTAMP NOT NULL DEFAULT current_timestamp,
@@ -25,10 +30,17 @@ CREATE TABLE `runner_detail` (
     `target_id` VARCHAR(36) NOT NULL,
     `cloud_id` TEXT NOT NULL,
     `resource_type` ENUM('nano', 'micro', 'small', 'medium', 'large', 'xlarge', '2xlarge', '3xlarge', '4xlarge') NOT NULL,
+    `runner_arch` VARCHAR(255) NOT NULL DEFAULT 'x64',
     `runner_user` VARCHAR(255),
     `provider_url` VARCHAR(255),
     `repository_url` VARCHAR(255) NOT NULL,
     `request_webhook` TEXT NOT NULL,
+    `executed_repository` VARCHAR(255),
+    `executed_run_id` BIGINT,
+    `executed_job_id` BIGINT,
+    `executed_at` TIMESTAMP NULL,
+    `instance_created_at` TIMESTAMP NULL,
+    `online_at` TIMESTAMP NULL,
     `created_at` TIMESTAMP NOT NULL DEFAULT current_timestamp,
     `updated_at` TIMESTAMP NOT NULL DEFAULT current_timestamp ON UPDATE current_timestamp,
     KEY `fk_runner_target_id` (`target_id`),
@@ -58,8 +70,69 @@ CREATE TABLE `jobs` (
     `repository` VARCHAR(255) NOT NULL,
     `check_event` TEXT NOT NULL,
     `target_id` VARCHAR(36) NOT NULL,
+    `trace_parent` VARCHAR(55) NOT NULL DEFAULT '',
+    `received_at` TIMESTAMP NULL,
     `created_at` TIMESTAMP NOT NULL DEFAULT current_timestamp,
     `updated_at` TIMESTAMP NOT NULL DEFAULT current_timestamp ON UPDATE current_timestamp,
     KEY `fk_job_target_id` (`target_id`),
     CONSTRAINT `jobs_ibfk_1` FOREIGN KEY fk_job_target_id(`target_id`) REFERENCES targets(`uuid`) ON DELETE RESTRICT
 );
+
+CREATE TABLE `script_templates` (
+    `name` VARCHAR(255) NOT NULL PRIMARY KEY,
+    `script` TEXT NOT NULL,
+    `pre_install` TEXT NOT NULL,
+    `post_install` TEXT NOT NULL,
+    `environment` TEXT NOT NULL,
+    `labels` TEXT NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    `updated_at` TIMESTAMP NOT NULL DEFAULT current_timestamp ON UPDATE current_timestamp
+);
+
+CREATE TABLE `api_tokens` (
+    `uuid` VARCHAR(36) NOT NULL PRIMARY KEY,
+    `name` VARCHAR(255) NOT NULL,
+    `token_hash` VARCHAR(64) NOT NULL UNIQUE,
+    `role` VARCHAR(255) NOT NULL,
+    `scope_prefix` VARCHAR(255) NOT NULL,
+    `created_by` VARCHAR(255) NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    `revoked_at` TIMESTAMP NULL DEFAULT NULL
+);
+
+CREATE TABLE `audit_logs` (
+    `uuid` VARCHAR(36) NOT NULL PRIMARY KEY,
+    `actor` VARCHAR(255) NOT NULL,
+    `action` VARCHAR(255) NOT NULL,
+    `resource_type` VARCHAR(255) NOT NULL,
+    `resource_id` VARCHAR(255) NOT NULL,
+    `changes` MEDIUMTEXT NOT NULL,
+    `created_at` TIMESTAMP(6) NOT NULL DEFAULT current_timestamp(6),
+    KEY `idx_audit_logs_created_at` (`created_at`),
+    KEY `idx_audit_logs_resource` (`resource_type`, `resource_id`)
+);
+
+CREATE TABLE `maintenance_windows` (
+    `uuid` VARCHAR(36) NOT NULL PRIMARY KEY,
+    `reason` VARCHAR(255) NOT NULL,
+    `start_at` TIMESTAMP NOT NULL,
+    `end_at` TIMESTAMP NULL DEFAULT NULL,
+    `created_by` VARCHAR(255) NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+CREATE TABLE `target_status_histories` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `target_id` VARCHAR(36) NOT NULL,
+    `status` VARCHAR(255) NOT NULL,
+    `description` VARCHAR(255) NOT NULL DEFAULT '',
+    `created_at` TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    KEY `idx_target_status_histories_target_id` (`target_id`)
+);
+
+CREATE TABLE `runtime_configs` (
+    `id` TINYINT NOT NULL PRIMARY KEY,
+    `config` TEXT NOT NULL,
+    `updated_by` VARCHAR(255) NOT NULL,
+    `updated_at` TIMESTAMP NOT NULL DEFAULT current_timestamp ON UPDATE current_timestamp
+);
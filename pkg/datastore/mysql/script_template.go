@@ -0,0 +1,66 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+// CreateScriptTemplate create a script template
+func (m *MySQL) CreateScriptTemplate(ctx context.Context, st datastore.ScriptTemplate) error {
+	query := `INSERT INTO script_templates(name, script, pre_install, post_install, environment, labels) VALUES (?, ?, ?, ?, ?, ?)`
+	if _, err := m.Conn.ExecContext(ctx, query, st.Name, st.Script, st.PreInstall, st.PostInstall, st.Environment, st.Labels); err != nil {
+		return fmt.Errorf("failed to execute INSERT query: %w", err)
+	}
+
+	return nil
+}
+
+// GetScriptTemplate get a script template
+func (m *MySQL) GetScriptTemplate(ctx context.Context, name string) (*datastore.ScriptTemplate, error) {
+	var st datastore.ScriptTemplate
+	query := `SELECT name, script, pre_install, post_install, environment, labels, created_at, updated_at FROM script_templates WHERE name = ?`
+	if err := m.Conn.GetContext(ctx, &st, query, name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, datastore.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
+	return &st, nil
+}
+
+// ListScriptTemplates get all script templates
+func (m *MySQL) ListScriptTemplates(ctx context.Context) ([]datastore.ScriptTemplate, error) {
+	var sts []datastore.ScriptTemplate
+	query := `SELECT name, script, pre_install, post_install, environment, labels, created_at, updated_at FROM script_templates`
+	if err := m.Conn.SelectContext(ctx, &sts, query); err != nil {
+		return nil, fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
+	return sts, nil
+}
+
+// UpdateScriptTemplate update a script template
+func (m *MySQL) UpdateScriptTemplate(ctx context.Context, st datastore.ScriptTemplate) error {
+	query := `UPDATE script_templates SET script = ?, pre_install = ?, post_install = ?, environment = ?, labels = ? WHERE name = ?`
+	if _, err := m.Conn.ExecContext(ctx, query, st.Script, st.PreInstall, st.PostInstall, st.Environment, st.Labels, st.Name); err != nil {
+		return fmt.Errorf("failed to execute UPDATE query: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteScriptTemplate delete a script template
+func (m *MySQL) DeleteScriptTemplate(ctx context.Context, name string) error {
+	query := `DELETE FROM script_templates WHERE name = ?`
+	if _, err := m.Conn.ExecContext(ctx, query, name); err != nil {
+		return fmt.Errorf("failed to execute DELETE query: %w", err)
+	}
+
+	return nil
+}
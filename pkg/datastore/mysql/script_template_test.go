@@ -0,0 +1,116 @@
+package mysql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/whywaita/myshoes/internal/testutils"
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+var testScriptTemplate = datastore.ScriptTemplate{
+	Name:        "gpu",
+	PreInstall:  "apt-get install -y nvidia-driver",
+	PostInstall: "nvidia-smi",
+	Environment: datastore.ScriptEnvironment{
+		"CUDA_VISIBLE_DEVICES": "0",
+	},
+	Labels: datastore.ScriptLabels{"gpu"},
+}
+
+func TestMySQL_CreateScriptTemplate(t *testing.T) {
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	tests := []struct {
+		input datastore.ScriptTemplate
+		want  *datastore.ScriptTemplate
+		err   bool
+	}{
+		{
+			input: testScriptTemplate,
+			want:  &testScriptTemplate,
+			err:   false,
+		},
+		{
+			input: datastore.ScriptTemplate{
+				Name:   "custom",
+				Script: "#!/bin/bash\necho {{.RunnerName}}",
+			},
+			want: &datastore.ScriptTemplate{
+				Name:        "custom",
+				Script:      "#!/bin/bash\necho {{.RunnerName}}",
+				Environment: datastore.ScriptEnvironment{},
+				Labels:      datastore.ScriptLabels{},
+			},
+			err: false,
+		},
+	}
+
+	for _, test := range tests {
+		err := testDatastore.CreateScriptTemplate(context.Background(), test.input)
+		if !test.err && err != nil {
+			t.Fatalf("failed to create script template: %+v", err)
+		}
+
+		got, err := testDatastore.GetScriptTemplate(context.Background(), test.input.Name)
+		if err != nil {
+			t.Fatalf("failed to get script template: %+v", err)
+		}
+		got.CreatedAt = time.Time{}
+		got.UpdatedAt = time.Time{}
+
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestMySQL_UpdateScriptTemplate(t *testing.T) {
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	if err := testDatastore.CreateScriptTemplate(context.Background(), testScriptTemplate); err != nil {
+		t.Fatalf("failed to create script template: %+v", err)
+	}
+
+	updated := testScriptTemplate
+	updated.PostInstall = "nvidia-smi -L"
+	updated.Labels = datastore.ScriptLabels{"gpu", "cuda"}
+	if err := testDatastore.UpdateScriptTemplate(context.Background(), updated); err != nil {
+		t.Fatalf("failed to update script template: %+v", err)
+	}
+
+	got, err := testDatastore.GetScriptTemplate(context.Background(), testScriptTemplate.Name)
+	if err != nil {
+		t.Fatalf("failed to get script template: %+v", err)
+	}
+	got.CreatedAt = time.Time{}
+	got.UpdatedAt = time.Time{}
+
+	if diff := cmp.Diff(&updated, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMySQL_DeleteScriptTemplate(t *testing.T) {
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	if err := testDatastore.CreateScriptTemplate(context.Background(), testScriptTemplate); err != nil {
+		t.Fatalf("failed to create script template: %+v", err)
+	}
+
+	if err := testDatastore.DeleteScriptTemplate(context.Background(), testScriptTemplate.Name); err != nil {
+		t.Fatalf("failed to delete script template: %+v", err)
+	}
+
+	_, err := testDatastore.GetScriptTemplate(context.Background(), testScriptTemplate.Name)
+	if !errors.Is(err, datastore.ErrNotFound) {
+		t.Fatalf("must be not found, but got %+v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/jmoiron/sqlx"
 	uuid "github.com/satori/go.uuid"
 	"github.com/whywaita/myshoes/pkg/datastore"
 )
@@ -15,8 +16,10 @@ import (
 func (m *MySQL) CreateTarget(ctx context.Context, target datastore.Target) error {
 	expiredAtRFC3339 := target.TokenExpiredAt.Format("2006-01-02 15:04:05")
 
-	query := `INSERT INTO targets(uuid, scope, ghe_domain, github_token, token_expired_at, resource_type, provider_url) VALUES (?, ?, ?, ?, ?, ?, ?)`
-	if _, err := m.Conn.ExecContext(
+	tx := m.Conn.MustBegin()
+
+	query := `INSERT INTO targets(uuid, scope, ghe_domain, github_token, token_expired_at, resource_type, provider_url, runner_group, use_jit_config, script_template, runner_os, runner_arch) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err := tx.ExecContext(
 		ctx,
 		query,
 		target.UUID,
@@ -26,9 +29,24 @@ func (m *MySQL) CreateTarget(ctx context.Context, target datastore.Target) error
 		expiredAtRFC3339,
 		target.ResourceType,
 		target.ProviderURL,
+		target.RunnerGroup,
+		target.UseJITConfig,
+		target.ScriptTemplate,
+		target.RunnerOS,
+		target.RunnerArch,
 	); err != nil {
+		tx.Rollback()
 		return fmt.Errorf("failed to execute INSERT query: %w", err)
 	}
+	if err := insertTargetStatusHistory(ctx, tx, target.UUID, datastore.TargetStatusActive, ""); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to execute COMMIT: %w", err)
+	}
 
 	return nil
 }
@@ -36,7 +54,7 @@ func (m *MySQL) CreateTarget(ctx context.Context, target datastore.Target) error
 // GetTarget get a target
 func (m *MySQL) GetTarget(ctx context.Context, id uuid.UUID) (*datastore.Target, error) {
 	var t datastore.Target
-	query := `SELECT uuid, scope, github_token, token_expired_at, resource_type, provider_url, status, status_description, created_at, updated_at FROM targets WHERE uuid = ?`
+	query := `SELECT uuid, scope, github_token, token_expired_at, resource_type, provider_url, runner_group, use_jit_config, script_template, runner_os, runner_arch, status, status_description, created_at, updated_at FROM targets WHERE uuid = ?`
 	if err := m.Conn.GetContext(ctx, &t, query, id.String()); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, datastore.ErrNotFound
@@ -51,7 +69,7 @@ func (m *MySQL) GetTarget(ctx context.Context, id uuid.UUID) (*datastore.Target,
 // GetTargetByScope get a target from scope
 func (m *MySQL) GetTargetByScope(ctx context.Context, scope string) (*datastore.Target, error) {
 	var t datastore.Target
-	query := `SELECT uuid, scope, github_token, token_expired_at, resource_type, provider_url, status, status_description, created_at, updated_at FROM targets WHERE scope = ?`
+	query := `SELECT uuid, scope, github_token, token_expired_at, resource_type, provider_url, runner_group, use_jit_config, script_template, runner_os, runner_arch, status, status_description, created_at, updated_at FROM targets WHERE scope = ?`
 	if err := m.Conn.GetContext(ctx, &t, query, scope); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, datastore.ErrNotFound
@@ -66,7 +84,7 @@ func (m *MySQL) GetTargetByScope(ctx context.Context, scope string) (*datastore.
 // ListTargets get a all target
 func (m *MySQL) ListTargets(ctx context.Context) ([]datastore.Target, error) {
 	var ts []datastore.Target
-	query := `SELECT uuid, scope, github_token, token_expired_at, resource_type, provider_url, status, status_description, created_at, updated_at FROM targets`
+	query := `SELECT uuid, scope, github_token, token_expired_at, resource_type, provider_url, runner_group, use_jit_config, script_template, runner_os, runner_arch, status, status_description, created_at, updated_at FROM targets`
 	if err := m.Conn.SelectContext(ctx, &ts, query); err != nil {
 		return nil, fmt.Errorf("failed to SELECT query: %w", err)
 	}
@@ -76,24 +94,84 @@ func (m *MySQL) ListTargets(ctx context.Context) ([]datastore.Target, error) {
 
 // DeleteTarget delete a target
 func (m *MySQL) DeleteTarget(ctx context.Context, id uuid.UUID) error {
+	tx := m.Conn.MustBegin()
+
 	query := `UPDATE targets SET status = "deleted" WHERE uuid = ?`
-	if _, err := m.Conn.ExecContext(ctx, query, id.String()); err != nil {
+	if _, err := tx.ExecContext(ctx, query, id.String()); err != nil {
+		tx.Rollback()
 		return fmt.Errorf("failed to execute DELETE query: %w", err)
 	}
+	if err := insertTargetStatusHistory(ctx, tx, id, datastore.TargetStatusDeleted, ""); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to execute COMMIT: %w", err)
+	}
 
 	return nil
 }
 
-// UpdateTargetStatus update status in target
+// UpdateTargetStatus update status in target, and record history if status or description is changed
 func (m *MySQL) UpdateTargetStatus(ctx context.Context, targetID uuid.UUID, newStatus datastore.TargetStatus, description string) error {
+	tx := m.Conn.MustBegin()
+
+	var current struct {
+		Status      datastore.TargetStatus `db:"status"`
+		Description sql.NullString         `db:"status_description"`
+	}
+	querySelect := `SELECT status, status_description FROM targets WHERE uuid = ? FOR UPDATE`
+	if err := tx.GetContext(ctx, &current, querySelect, targetID.String()); err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return datastore.ErrNotFound
+		}
+		return fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
 	query := `UPDATE targets SET status = ?, status_description = ? WHERE uuid = ?`
-	if _, err := m.Conn.ExecContext(ctx, query, newStatus, description, targetID.String()); err != nil {
+	if _, err := tx.ExecContext(ctx, query, newStatus, description, targetID.String()); err != nil {
+		tx.Rollback()
 		return fmt.Errorf("failed to execute UPDATE query: %w", err)
 	}
+	if current.Status != newStatus || current.Description.String != description {
+		if err := insertTargetStatusHistory(ctx, tx, targetID, newStatus, description); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to execute COMMIT: %w", err)
+	}
+
+	return nil
+}
 
+func insertTargetStatusHistory(ctx context.Context, tx *sqlx.Tx, targetID uuid.UUID, status datastore.TargetStatus, description string) error {
+	query := `INSERT INTO target_status_histories(target_id, status, description) VALUES (?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, query, targetID.String(), status, description); err != nil {
+		return fmt.Errorf("failed to execute INSERT query to history: %w", err)
+	}
 	return nil
 }
 
+// ListTargetStatusHistory get changes of target status
+func (m *MySQL) ListTargetStatusHistory(ctx context.Context, targetID uuid.UUID, limit, offset int) ([]datastore.TargetStatusHistory, error) {
+	query := `SELECT id, target_id, status, description, created_at FROM target_status_histories WHERE target_id = ? ORDER BY id DESC`
+	query, args := appendLimitOffset(query, []interface{}{targetID.String()}, limit, offset)
+
+	var histories []datastore.TargetStatusHistory
+	if err := m.Conn.SelectContext(ctx, &histories, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to execute SELECT query: %w", err)
+	}
+
+	return histories, nil
+}
+
 // UpdateToken update token in target
 func (m *MySQL) UpdateToken(ctx context.Context, targetID uuid.UUID, newToken string, newExpiredAt time.Time) error {
 	query := `UPDATE targets SET github_token = ?, token_expired_at = ? WHERE uuid = ?`
@@ -105,9 +183,9 @@ func (m *MySQL) UpdateToken(ctx context.Context, targetID uuid.UUID, newToken st
 }
 
 // UpdateTargetParam update parameter of target
-func (m *MySQL) UpdateTargetParam(ctx context.Context, targetID uuid.UUID, newResourceType datastore.ResourceType, newProviderURL sql.NullString) error {
-	query := `UPDATE targets SET resource_type = ?, provider_url = ? WHERE uuid = ?`
-	if _, err := m.Conn.ExecContext(ctx, query, newResourceType, newProviderURL, targetID.String()); err != nil {
+func (m *MySQL) UpdateTargetParam(ctx context.Context, targetID uuid.UUID, newParam datastore.TargetParam) error {
+	query := `UPDATE targets SET resource_type = ?, provider_url = ?, runner_group = ?, use_jit_config = ?, script_template = ?, runner_os = ?, runner_arch = ? WHERE uuid = ?`
+	if _, err := m.Conn.ExecContext(ctx, query, newParam.ResourceType, newParam.ProviderURL, newParam.RunnerGroup, newParam.UseJITConfig, newParam.ScriptTemplate, newParam.RunnerOS, newParam.RunnerArch, targetID.String()); err != nil {
 		return fmt.Errorf("failed to execute UPDATE query: %w", err)
 	}
 
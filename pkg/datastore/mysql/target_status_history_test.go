@@ -0,0 +1,80 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/whywaita/myshoes/internal/testutils"
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+func TestMySQL_TargetStatusHistory(t *testing.T) {
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	ctx := context.Background()
+	if err := testDatastore.CreateTarget(ctx, datastore.Target{
+		UUID:           testTargetID,
+		Scope:          testScopeRepo,
+		GitHubToken:    testGitHubToken,
+		TokenExpiredAt: testTime,
+		ResourceType:   datastore.ResourceTypeNano,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+
+	//lint:ignore SA1019 test status history of datastore
+	if err := testDatastore.UpdateTargetStatus(ctx, testTargetID, datastore.TargetStatusErr, "failed to create an instance"); err != nil {
+		t.Fatalf("failed to update target status: %+v", err)
+	}
+	// same status and description, must not be recorded
+	//lint:ignore SA1019 test status history of datastore
+	if err := testDatastore.UpdateTargetStatus(ctx, testTargetID, datastore.TargetStatusErr, "failed to create an instance"); err != nil {
+		t.Fatalf("failed to update target status: %+v", err)
+	}
+	//lint:ignore SA1019 test status history of datastore
+	if err := testDatastore.UpdateTargetStatus(ctx, testTargetID, datastore.TargetStatusActive, "recovered by health check"); err != nil {
+		t.Fatalf("failed to update target status: %+v", err)
+	}
+	if err := testDatastore.DeleteTarget(ctx, testTargetID); err != nil {
+		t.Fatalf("failed to delete target: %+v", err)
+	}
+
+	tests := []struct {
+		limit  int
+		offset int
+		want   []datastore.TargetStatusHistory
+	}{
+		{
+			want: []datastore.TargetStatusHistory{
+				{TargetID: testTargetID, Status: datastore.TargetStatusDeleted},
+				{TargetID: testTargetID, Status: datastore.TargetStatusActive, Description: "recovered by health check"},
+				{TargetID: testTargetID, Status: datastore.TargetStatusErr, Description: "failed to create an instance"},
+				{TargetID: testTargetID, Status: datastore.TargetStatusActive},
+			},
+		},
+		{
+			limit:  1,
+			offset: 2,
+			want: []datastore.TargetStatusHistory{
+				{TargetID: testTargetID, Status: datastore.TargetStatusErr, Description: "failed to create an instance"},
+			},
+		},
+	}
+	for _, test := range tests {
+		got, err := testDatastore.ListTargetStatusHistory(ctx, testTargetID, test.limit, test.offset)
+		if err != nil {
+			t.Fatalf("failed to list status history: %+v", err)
+		}
+		for i := range got {
+			got[i].ID = 0
+			got[i].CreatedAt = time.Time{}
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
@@ -30,6 +30,7 @@ var testGitHubToken = "this-code-is-github-token"
 var testGitHubTokenLong = strings.Repeat("t", 1024)
 var testRunnerUser = "testing-super-user"
 var testProviderURL = "/shoes-mock"
+var testRunnerGroup = "expensive-runners"
 var testTime = time.Date(2037, 9, 3, 0, 0, 0, 0, time.UTC)
 
 func TestMySQL_CreateTarget(t *testing.T) {
@@ -731,6 +732,8 @@ func TestMySQL_UpdateTargetParam(t *testing.T) {
 		resourceType datastore.ResourceType
 		runnerUser   sql.NullString
 		providerURL  sql.NullString
+		runnerGroup  sql.NullString
+		useJITConfig bool
 	}
 
 	tests := []struct {
@@ -822,6 +825,64 @@ func TestMySQL_UpdateTargetParam(t *testing.T) {
 			},
 			err: false,
 		},
+		{
+			input: input{
+				resourceType: datastore.ResourceTypeLarge,
+				providerURL: sql.NullString{
+					String: "",
+					Valid:  false,
+				},
+				runnerGroup: sql.NullString{
+					String: testRunnerGroup,
+					Valid:  true,
+				},
+			},
+			want: &datastore.Target{
+				Scope:        testScopeRepo,
+				GitHubToken:  testGitHubToken,
+				ResourceType: datastore.ResourceTypeLarge,
+				ProviderURL: sql.NullString{
+					String: "",
+					Valid:  false,
+				},
+				RunnerGroup: sql.NullString{
+					String: testRunnerGroup,
+					Valid:  true,
+				},
+				Status: datastore.TargetStatusActive,
+				StatusDescription: sql.NullString{
+					String: "",
+					Valid:  false,
+				},
+			},
+			err: false,
+		},
+		{
+			input: input{
+				resourceType: datastore.ResourceTypeLarge,
+				providerURL: sql.NullString{
+					String: "",
+					Valid:  false,
+				},
+				useJITConfig: true,
+			},
+			want: &datastore.Target{
+				Scope:        testScopeRepo,
+				GitHubToken:  testGitHubToken,
+				ResourceType: datastore.ResourceTypeLarge,
+				ProviderURL: sql.NullString{
+					String: "",
+					Valid:  false,
+				},
+				UseJITConfig: true,
+				Status:       datastore.TargetStatusActive,
+				StatusDescription: sql.NullString{
+					String: "",
+					Valid:  false,
+				},
+			},
+			err: false,
+		},
 	}
 
 	for _, test := range tests {
@@ -840,7 +901,12 @@ func TestMySQL_UpdateTargetParam(t *testing.T) {
 			t.Fatalf("failed to create target: %+v", err)
 		}
 
-		if err := testDatastore.UpdateTargetParam(context.Background(), tID, test.input.resourceType, test.input.providerURL); err != nil {
+		if err := testDatastore.UpdateTargetParam(context.Background(), tID, datastore.TargetParam{
+			ResourceType: test.input.resourceType,
+			ProviderURL:  test.input.providerURL,
+			RunnerGroup:  test.input.runnerGroup,
+			UseJITConfig: test.input.useJITConfig,
+		}); err != nil {
 			t.Fatalf("failed to UpdateResourceTyoe: %+v", err)
 		}
 
@@ -867,7 +933,7 @@ func TestMySQL_UpdateTargetParam(t *testing.T) {
 
 func getTargetFromSQL(testDB *sqlx.DB, uuid uuid.UUID) (*datastore.Target, error) {
 	var t datastore.Target
-	query := `SELECT uuid, scope, ghe_domain, github_token, token_expired_at, resource_type, provider_url, status, status_description, created_at, updated_at FROM targets WHERE uuid = ?`
+	query := `SELECT uuid, scope, ghe_domain, github_token, token_expired_at, resource_type, provider_url, runner_group, use_jit_config, script_template, runner_os, runner_arch, status, status_description, created_at, updated_at FROM targets WHERE uuid = ?`
 	stmt, err := testDB.Preparex(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare: %w", err)
@@ -0,0 +1,57 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/logger"
+)
+
+// SaveRuntimeConfig save runtime config to datastore
+func SaveRuntimeConfig(ctx context.Context, ds Datastore, rc config.Runtime, updatedBy string) error {
+	b, err := json.Marshal(rc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal runtime config: %w", err)
+	}
+	if err := ds.PutRuntimeConfig(ctx, RuntimeConfig{
+		Config:    string(b),
+		UpdatedBy: updatedBy,
+	}); err != nil {
+		return fmt.Errorf("failed to save runtime config: %w", err)
+	}
+	return nil
+}
+
+// LoadRuntimeConfig restore runtime config that saved in datastore.
+// saved config has priority over default values, but values that are set by flags, environment or config file have priority over saved config.
+func LoadRuntimeConfig(ctx context.Context, ds Datastore) error {
+	saved, err := ds.GetRuntimeConfig(ctx)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to get runtime config: %w", err)
+	}
+
+	rc := config.GetRuntime()
+	if err := json.Unmarshal([]byte(saved.Config), &rc); err != nil {
+		return fmt.Errorf("failed to unmarshal runtime config: %w", err)
+	}
+	explicit, keys, err := config.ExplicitRuntimePatch()
+	if err != nil {
+		return fmt.Errorf("failed to get explicit runtime config: %w", err)
+	}
+	rc = explicit.Apply(rc)
+	if len(keys) != 0 {
+		logger.Logf(false, "%s are set explicitly, these have priority over saved runtime config", strings.Join(keys, ", "))
+	}
+	if err := config.SetRuntime(rc); err != nil {
+		return fmt.Errorf("failed to set runtime config: %w", err)
+	}
+	logger.Logf(false, "restore runtime config (version: %d, updated by: %s, updated at: %s)", rc.Version, saved.UpdatedBy, saved.UpdatedAt)
+	return nil
+}
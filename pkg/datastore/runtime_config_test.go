@@ -0,0 +1,49 @@
+package datastore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/datastore/memory"
+)
+
+func TestLoadRuntimeConfig(t *testing.T) {
+	ctx := context.Background()
+	ds, err := memory.New()
+	if err != nil {
+		t.Fatalf("failed to create datastore: %+v", err)
+	}
+	defer func() {
+		config.SetFlagValues(map[string]string{})
+		config.SetRuntime(config.DefaultRuntime())
+	}()
+
+	saved := config.DefaultRuntime()
+	saved.MaxConnectionsToBackend = 10
+	saved.MaxConcurrencyDeleting = 5
+	saved.RunnerVersion = "v2.300.0"
+	if err := datastore.SaveRuntimeConfig(ctx, ds, saved, "test"); err != nil {
+		t.Fatalf("failed to save runtime config: %+v", err)
+	}
+
+	t.Setenv(config.EnvMaxConcurrencyDeleting, "3")
+	if err := config.SetFlagValues(map[string]string{"max_connections_to_backend": "100"}); err != nil {
+		t.Fatalf("failed to set flag values: %+v", err)
+	}
+
+	if err := datastore.LoadRuntimeConfig(ctx, ds); err != nil {
+		t.Fatalf("failed to load runtime config: %+v", err)
+	}
+	got := config.GetRuntime()
+	if got.MaxConnectionsToBackend != 100 {
+		t.Errorf("value of flag must have priority, but got %d", got.MaxConnectionsToBackend)
+	}
+	if got.MaxConcurrencyDeleting != 3 {
+		t.Errorf("value of environment must have priority, but got %d", got.MaxConcurrencyDeleting)
+	}
+	if got.RunnerVersion != "v2.300.0" {
+		t.Errorf("saved value must be used if not set explicitly, but got %s", got.RunnerVersion)
+	}
+}
@@ -0,0 +1,112 @@
+package datastore
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	scriptTemplateNameRegexp  = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,254}$`)
+	environmentVariableRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// ScriptEnvironment is environment variables that export in setup script
+type ScriptEnvironment map[string]string
+
+// Value implements the database/sql/driver Valuer interface
+func (e ScriptEnvironment) Value() (driver.Value, error) {
+	if e == nil {
+		return driver.Value("{}"), nil
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ScriptEnvironment: %w", err)
+	}
+	return driver.Value(string(b)), nil
+}
+
+// Scan implements the database/sql Scanner interface
+func (e *ScriptEnvironment) Scan(src interface{}) error {
+	b, err := scanJSONBytes(src)
+	if err != nil {
+		return fmt.Errorf("incompatible type for ScriptEnvironment: %w", err)
+	}
+	env := ScriptEnvironment{}
+	if len(b) != 0 {
+		if err := json.Unmarshal(b, &env); err != nil {
+			return fmt.Errorf("failed to unmarshal ScriptEnvironment: %w", err)
+		}
+	}
+
+	*e = env
+	return nil
+}
+
+// ScriptLabels is custom labels of runner
+type ScriptLabels []string
+
+// Value implements the database/sql/driver Valuer interface
+func (l ScriptLabels) Value() (driver.Value, error) {
+	if l == nil {
+		return driver.Value("[]"), nil
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ScriptLabels: %w", err)
+	}
+	return driver.Value(string(b)), nil
+}
+
+// Scan implements the database/sql Scanner interface
+func (l *ScriptLabels) Scan(src interface{}) error {
+	b, err := scanJSONBytes(src)
+	if err != nil {
+		return fmt.Errorf("incompatible type for ScriptLabels: %w", err)
+	}
+	var labels ScriptLabels
+	if len(b) != 0 {
+		if err := json.Unmarshal(b, &labels); err != nil {
+			return fmt.Errorf("failed to unmarshal ScriptLabels: %w", err)
+		}
+	}
+
+	*l = labels
+	return nil
+}
+
+func scanJSONBytes(src interface{}) ([]byte, error) {
+	switch src := src.(type) {
+	case string:
+		return []byte(src), nil
+	case []uint8:
+		return src, nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported type: %T", src)
+	}
+}
+
+// Validate check values of script template except template syntax
+func (st *ScriptTemplate) Validate() error {
+	if !scriptTemplateNameRegexp.MatchString(st.Name) {
+		return fmt.Errorf("invalid name (name: %s): must match %s", st.Name, scriptTemplateNameRegexp.String())
+	}
+
+	for k := range st.Environment {
+		if !environmentVariableRegexp.MatchString(k) {
+			return fmt.Errorf("invalid environment variable name (name: %s)", k)
+		}
+	}
+
+	for _, l := range st.Labels {
+		if l == "" || strings.ContainsAny(l, ", \t\n\"'") {
+			return fmt.Errorf("invalid label (label: %q): must not be empty or contain comma, space or quote", l)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package datastore
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/config"
+)
+
+// UsageGroupBy is key for grouping usage
+type UsageGroupBy string
+
+// UsageGroupBy values
+const (
+	UsageGroupByTarget       UsageGroupBy = "target"
+	UsageGroupByRepository   UsageGroupBy = "repository"
+	UsageGroupByResourceType UsageGroupBy = "resource_type"
+	UsageGroupByProvider     UsageGroupBy = "provider"
+)
+
+// ParseUsageGroupBy parse comma separated keys (e.g. "target,resource_type")
+func ParseUsageGroupBy(s string) ([]UsageGroupBy, error) {
+	if s == "" {
+		return []UsageGroupBy{UsageGroupByTarget}, nil
+	}
+
+	var groupBy []UsageGroupBy
+	for _, key := range strings.Split(s, ",") {
+		g := UsageGroupBy(strings.TrimSpace(key))
+		switch g {
+		case UsageGroupByTarget, UsageGroupByRepository, UsageGroupByResourceType, UsageGroupByProvider:
+			groupBy = append(groupBy, g)
+		default:
+			return nil, fmt.Errorf("unknown group_by %q, must be %s, %s, %s or %s", key, UsageGroupByTarget, UsageGroupByRepository, UsageGroupByResourceType, UsageGroupByProvider)
+		}
+	}
+	return groupBy, nil
+}
+
+// Usage is runner-minutes and estimated cost in a group, fields that are not grouped by are empty
+type Usage struct {
+	Target       string `json:"target,omitempty"`
+	Repository   string `json:"repository,omitempty"`
+	ResourceType string `json:"resource_type,omitempty"`
+	Provider     string `json:"provider,omitempty"`
+
+	Runners       int     `json:"runners"`
+	RunnerMinutes float64 `json:"runner_minutes"`
+	EstimatedCost float64 `json:"estimated_cost"`
+}
+
+// RunnerMinutes return minutes that runner was running in [from, to)
+func RunnerMinutes(r Runner, from, to time.Time) float64 {
+	start := r.CreatedAt
+	if start.Before(from) {
+		start = from
+	}
+	end := to
+	if r.DeletedAt.Valid && r.DeletedAt.Time.Before(to) {
+		end = r.DeletedAt.Time
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start).Minutes()
+}
+
+// RepositoryName return :owner/:repo from repository URL of runner
+func (r *Runner) RepositoryName() string {
+	u, err := url.Parse(r.RepositoryURL)
+	if err != nil {
+		return r.RepositoryURL
+	}
+	return strings.Trim(u.Path, "/")
+}
+
+// CalculateUsage sum runner-minutes and estimated cost of runners in [from, to) by groupBy.
+// scopes is scope of targets, runners of unknown target are grouped as target ID.
+func CalculateUsage(runners []Runner, scopes map[uuid.UUID]string, from, to time.Time, groupBy []UsageGroupBy, prices config.PriceTable) []Usage {
+	usages := map[Usage]*Usage{}
+	for _, r := range runners {
+		minutes := RunnerMinutes(r, from, to)
+		if minutes == 0 {
+			continue
+		}
+
+		var key Usage
+		for _, g := range groupBy {
+			switch g {
+			case UsageGroupByTarget:
+				key.Target = r.TargetID.String()
+				if scope, ok := scopes[r.TargetID]; ok {
+					key.Target = scope
+				}
+			case UsageGroupByRepository:
+				key.Repository = r.RepositoryName()
+			case UsageGroupByResourceType:
+				key.ResourceType = r.ResourceType.String()
+			case UsageGroupByProvider:
+				key.Provider = r.ShoesType
+			}
+		}
+
+		u, ok := usages[key]
+		if !ok {
+			u = &Usage{Target: key.Target, Repository: key.Repository, ResourceType: key.ResourceType, Provider: key.Provider}
+			usages[key] = u
+		}
+		u.Runners++
+		u.RunnerMinutes += minutes
+		u.EstimatedCost += minutes * prices.PerMinute(r.ShoesType, r.ResourceType.String())
+	}
+
+	result := make([]Usage, 0, len(usages))
+	for _, u := range usages {
+		result = append(result, *u)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		for _, pair := range [][2]string{
+			{a.Target, b.Target},
+			{a.Repository, b.Repository},
+			{a.ResourceType, b.ResourceType},
+			{a.Provider, b.Provider},
+		} {
+			if pair[0] != pair[1] {
+				return pair[0] < pair[1]
+			}
+		}
+		return false
+	})
+	return result
+}
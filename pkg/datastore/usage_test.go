@@ -0,0 +1,99 @@
+package datastore_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+func TestCalculateUsage(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	targetID := uuid.NewV4()
+	unknownTargetID := uuid.FromStringOrNil("f943e412-c0ae-4068-ab24-3e71a13fbe53")
+	scopes := map[uuid.UUID]string{targetID: "octocat"}
+
+	runners := []datastore.Runner{
+		{
+			// created before from, deleted in period: 30 minutes
+			TargetID:      targetID,
+			ShoesType:     "aws",
+			ResourceType:  datastore.ResourceTypeNano,
+			RepositoryURL: "https://github.com/octocat/Hello-World",
+			CreatedAt:     from.Add(-time.Hour),
+			DeletedAt:     sql.NullTime{Time: from.Add(30 * time.Minute), Valid: true},
+		},
+		{
+			// created in period, still running: 60 minutes
+			TargetID:      targetID,
+			ShoesType:     "aws",
+			ResourceType:  datastore.ResourceTypeLarge,
+			RepositoryURL: "https://github.com/octocat/Spoon-Knife",
+			CreatedAt:     to.Add(-time.Hour),
+		},
+		{
+			// deleted before from: not counted
+			TargetID:      targetID,
+			ShoesType:     "aws",
+			ResourceType:  datastore.ResourceTypeNano,
+			RepositoryURL: "https://github.com/octocat/Hello-World",
+			CreatedAt:     from.Add(-2 * time.Hour),
+			DeletedAt:     sql.NullTime{Time: from.Add(-time.Hour), Valid: true},
+		},
+		{
+			// target is deleted: 10 minutes
+			TargetID:      unknownTargetID,
+			ShoesType:     "lxd",
+			ResourceType:  datastore.ResourceTypeNano,
+			RepositoryURL: "https://github.com/whywaita/myshoes",
+			CreatedAt:     from,
+			DeletedAt:     sql.NullTime{Time: from.Add(10 * time.Minute), Valid: true},
+		},
+	}
+	prices := config.PriceTable{
+		{Provider: "aws", ResourceType: config.PriceWildcard, PerMinute: 0.01},
+		{Provider: "aws", ResourceType: "large", PerMinute: 0.1},
+	}
+
+	tests := []struct {
+		groupBy []datastore.UsageGroupBy
+		want    []datastore.Usage
+	}{
+		{
+			groupBy: []datastore.UsageGroupBy{datastore.UsageGroupByTarget},
+			want: []datastore.Usage{
+				{Target: unknownTargetID.String(), Runners: 1, RunnerMinutes: 10, EstimatedCost: 0},
+				{Target: "octocat", Runners: 2, RunnerMinutes: 90, EstimatedCost: 0.3 + 6},
+			},
+		},
+		{
+			groupBy: []datastore.UsageGroupBy{datastore.UsageGroupByProvider, datastore.UsageGroupByResourceType},
+			want: []datastore.Usage{
+				{Provider: "aws", ResourceType: "large", Runners: 1, RunnerMinutes: 60, EstimatedCost: 6},
+				{Provider: "aws", ResourceType: "nano", Runners: 1, RunnerMinutes: 30, EstimatedCost: 0.3},
+				{Provider: "lxd", ResourceType: "nano", Runners: 1, RunnerMinutes: 10, EstimatedCost: 0},
+			},
+		},
+		{
+			groupBy: []datastore.UsageGroupBy{datastore.UsageGroupByRepository},
+			want: []datastore.Usage{
+				{Repository: "octocat/Hello-World", Runners: 1, RunnerMinutes: 30, EstimatedCost: 0.3},
+				{Repository: "octocat/Spoon-Knife", Runners: 1, RunnerMinutes: 60, EstimatedCost: 6},
+				{Repository: "whywaita/myshoes", Runners: 1, RunnerMinutes: 10, EstimatedCost: 0},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got := datastore.CalculateUsage(runners, scopes, from, to, test.groupBy, prices)
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
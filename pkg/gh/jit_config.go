@@ -0,0 +1,67 @@
+package gh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v80/github"
+	"github.com/whywaita/myshoes/pkg/config"
+)
+
+// DefaultRunnerGroupID is ID of "Default" runner group
+const DefaultRunnerGroupID int64 = 1
+
+var (
+	// ErrJITConfigNotSupported is error for GitHub that not support just-in-time runner configuration
+	ErrJITConfigNotSupported = fmt.Errorf("just-in-time runner configuration is not supported")
+)
+
+// GenerateJITConfig generate encoded just-in-time configuration for runner
+func GenerateJITConfig(ctx context.Context, installationID int64, scope, runnerName string, runnerGroupID int64, labels []string) (string, error) {
+	clientInstallation, err := NewClientInstallation(installationID)
+	if err != nil {
+		return "", fmt.Errorf("failed to create a client installation: %w", err)
+	}
+
+	req := &github.GenerateJITConfigRequest{
+		Name:          runnerName,
+		RunnerGroupID: runnerGroupID,
+		Labels:        labels,
+	}
+
+	var jitConfig *github.JITRunnerConfig
+	var resp *github.Response
+	switch DetectScope(scope) {
+	case Organization:
+		jitConfig, resp, err = clientInstallation.Actions.GenerateOrgJITConfig(ctx, scope, req)
+	case Repository:
+		owner, repo := DivideScope(scope)
+		jitConfig, resp, err = clientInstallation.Actions.GenerateRepoJITConfig(ctx, owner, repo, req)
+	default:
+		return "", fmt.Errorf("failed to detect scope (scope: %s)", scope)
+	}
+	if err != nil {
+		if isJITConfigNotSupported(err) {
+			return "", fmt.Errorf("failed to generate jit config (scope: %s): %w", scope, ErrJITConfigNotSupported)
+		}
+		return "", fmt.Errorf("failed to generate jit config (scope: %s): %w", scope, err)
+	}
+	storeRateLimit(getRateLimitKey(DivideScope(scope)), resp.Rate)
+
+	return jitConfig.GetEncodedJITConfig(), nil
+}
+
+// isJITConfigNotSupported return true if GitHub Enterprise Server doesn't have JIT endpoint
+func isJITConfigNotSupported(err error) bool {
+	if !config.Config.IsGHES() {
+		return false
+	}
+
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		return errResp.Response.StatusCode == http.StatusNotFound
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package gh
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v80/github"
+	"github.com/whywaita/myshoes/pkg/config"
+)
+
+func TestIsJITConfigNotSupported(t *testing.T) {
+	notFound := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	forbidden := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusForbidden}}
+
+	tests := []struct {
+		githubURL string
+		input     error
+		want      bool
+	}{
+		{
+			githubURL: "https://github.com",
+			input:     notFound,
+			want:      false,
+		},
+		{
+			githubURL: "https://github-enterprise.example.com",
+			input:     fmt.Errorf("wrapped: %w", notFound),
+			want:      true,
+		},
+		{
+			githubURL: "https://github-enterprise.example.com",
+			input:     forbidden,
+			want:      false,
+		},
+		{
+			githubURL: "https://github-enterprise.example.com",
+			input:     fmt.Errorf("unknown error"),
+			want:      false,
+		},
+	}
+
+	for _, test := range tests {
+		config.Config.GitHubURL = test.githubURL
+
+		got := isJITConfigNotSupported(test.input)
+		if got != test.want {
+			t.Fatalf("want %t, but got %t (url: %s, err: %+v)", test.want, got, test.githubURL, test.input)
+		}
+	}
+}
@@ -11,6 +11,9 @@ import (
 	"github.com/m4ns0ur/httpcache"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/whywaita/myshoes/pkg/tracing"
 )
 
 const githubAPINamespace = "myshoes"
@@ -93,12 +96,24 @@ func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, er
 	githubAPIInflight.WithLabelValues(path, method).Inc()
 	defer githubAPIInflight.WithLabelValues(path, method).Dec()
 
+	ctx := context.Background()
+	if req != nil {
+		ctx = req.Context()
+	}
+	_, span := tracing.Start(ctx, "GitHub API "+method,
+		attribute.String("http.request.method", method),
+		attribute.String("url.path", path),
+	)
+	defer span.End()
+
 	resp, err := t.next.RoundTrip(req)
 
 	statusClass := "error"
 	if err == nil && resp != nil {
 		statusClass = fmt.Sprintf("%dxx", resp.StatusCode/100)
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
 	}
+	tracing.RecordError(span, err)
 	githubAPIRequestsTotal.WithLabelValues(path, method, statusClass).Inc()
 	githubAPIRequestDuration.WithLabelValues(path, method, statusClass).Observe(time.Since(start).Seconds())
 
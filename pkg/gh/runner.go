@@ -86,8 +86,9 @@ func listRunners(ctx context.Context, client *github.Client, owner, repo string,
 	return runners, resp, nil
 }
 
-// GetLatestRunnerVersion get a latest version of actions/runner
-func GetLatestRunnerVersion(ctx context.Context, scope string) (string, error) {
+// GetLatestRunnerVersion get a latest version of actions/runner.
+// runnerOS and runnerArch is platform of runner (e.g. "linux" and "x64", "win" and "arm64")
+func GetLatestRunnerVersion(ctx context.Context, scope, runnerOS, runnerArch string) (string, error) {
 	clientApps, err := NewClientGitHubApps()
 	if err != nil {
 		return "", fmt.Errorf("failed to create a client from Apps: %+v", err)
@@ -113,29 +114,30 @@ func GetLatestRunnerVersion(ctx context.Context, scope string) (string, error) {
 			return "", fmt.Errorf("failed to get latest runner version: %w", err)
 		}
 		storeRateLimit(getRateLimitKey(owner, repo), resp.Rate)
-		return getRunnerVersion(applications)
+		return getRunnerVersion(applications, runnerOS, runnerArch)
 	case Organization:
 		applications, resp, err := client.Actions.ListOrganizationRunnerApplicationDownloads(ctx, scope)
 		if err != nil {
 			return "", fmt.Errorf("failed to get latest runner version: %w", err)
 		}
 		storeRateLimit(getRateLimitKey(scope, ""), resp.Rate)
-		return getRunnerVersion(applications)
+		return getRunnerVersion(applications, runnerOS, runnerArch)
 	}
 	return "", fmt.Errorf("invalid scope: %s", scope)
 }
 
-func getRunnerVersion(applications []*github.RunnerApplicationDownload) (string, error) {
-	// filename": "actions-runner-linux-x64-2.164.0.tar.gz"
+func getRunnerVersion(applications []*github.RunnerApplicationDownload, runnerOS, runnerArch string) (string, error) {
+	// filename": "actions-runner-linux-x64-2.164.0.tar.gz", "actions-runner-win-arm64-2.164.0.zip"
 	for _, app := range applications {
-		if *app.OS == "linux" && *app.Architecture == "x64" {
-			v := strings.ReplaceAll(*app.Filename, "actions-runner-linux-x64-", "")
-			v = strings.ReplaceAll(v, ".tar.gz", "")
+		if app.GetOS() == runnerOS && app.GetArchitecture() == runnerArch {
+			v := strings.TrimPrefix(app.GetFilename(), fmt.Sprintf("actions-runner-%s-%s-", runnerOS, runnerArch))
+			v = strings.TrimSuffix(v, ".tar.gz")
+			v = strings.TrimSuffix(v, ".zip")
 			return fmt.Sprintf("v%s", v), nil
 		}
 	}
 
-	return "", fmt.Errorf("not found runner version")
+	return "", fmt.Errorf("not found runner version (os: %s, arch: %s)", runnerOS, runnerArch)
 }
 
 // ConcatLabels concat labels from check event JSON
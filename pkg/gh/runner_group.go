@@ -0,0 +1,59 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v80/github"
+	"github.com/whywaita/myshoes/pkg/logger"
+)
+
+// ExistRunnerGroup check exist of runner group in organization
+func ExistRunnerGroup(ctx context.Context, client *github.Client, org, runnerGroupName string) (*github.RunnerGroup, error) {
+	groups, err := ListRunnerGroups(ctx, client, org)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list of runner groups: %w", err)
+	}
+
+	return ExistRunnerGroupWithRunnerGroup(groups, runnerGroupName)
+}
+
+// ExistRunnerGroupWithRunnerGroup check exist of runner group from a list of runner group
+func ExistRunnerGroupWithRunnerGroup(groups []*github.RunnerGroup, runnerGroupName string) (*github.RunnerGroup, error) {
+	for _, g := range groups {
+		if strings.EqualFold(g.GetName(), runnerGroupName) {
+			return g, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// ListRunnerGroups get runner groups that registered organization
+func ListRunnerGroups(ctx context.Context, client *github.Client, org string) ([]*github.RunnerGroup, error) {
+	var opts = &github.ListOrgRunnerGroupOptions{
+		ListOptions: github.ListOptions{
+			Page:    0,
+			PerPage: 100,
+		},
+	}
+
+	var groups []*github.RunnerGroup
+	for {
+		logger.Logf(true, "get runner groups from GitHub, page: %d, now all runner groups: %d", opts.Page, len(groups))
+		gs, resp, err := client.Actions.ListOrganizationRunnerGroups(ctx, org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list organization runner groups: %w", err)
+		}
+		storeRateLimit(getRateLimitKey(org, ""), resp.Rate)
+
+		groups = append(groups, gs.RunnerGroups...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.ListOptions.Page = resp.NextPage
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,54 @@
+package gh
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-github/v80/github"
+)
+
+func TestExistRunnerGroupWithRunnerGroup(t *testing.T) {
+	groups := []*github.RunnerGroup{
+		{
+			ID:   github.Ptr(int64(1)),
+			Name: github.Ptr("Default"),
+		},
+		{
+			ID:   github.Ptr(int64(2)),
+			Name: github.Ptr("expensive-runners"),
+		},
+	}
+
+	tests := []struct {
+		input  string
+		wantID int64
+		err    error
+	}{
+		{
+			input:  "expensive-runners",
+			wantID: 2,
+			err:    nil,
+		},
+		{
+			input:  "default",
+			wantID: 1,
+			err:    nil,
+		},
+		{
+			input:  "not-found",
+			wantID: 0,
+			err:    ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ExistRunnerGroupWithRunnerGroup(groups, test.input)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("ExistRunnerGroupWithRunnerGroup want err %+v, but return err %+v", test.err, err)
+		}
+
+		if got.GetID() != test.wantID {
+			t.Fatalf("want %d, but got %d", test.wantID, got.GetID())
+		}
+	}
+}
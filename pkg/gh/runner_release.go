@@ -0,0 +1,43 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v80/github"
+)
+
+// RunnerReleaseDownloadURL is base URL of actions/runner release files
+const RunnerReleaseDownloadURL = "https://github.com/actions/runner/releases/download"
+
+// release note of actions/runner has checksum like "<!-- BEGIN SHA linux-x64 -->{sha256}<!-- END SHA linux-x64 -->"
+var runnerReleaseChecksumRegexp = regexp.MustCompile(`<!-- BEGIN SHA ([a-zA-Z0-9_-]+) -->([0-9a-fA-F]{64})<!-- END SHA`)
+
+// GetRunnerReleaseChecksums get SHA-256 checksums of actions/runner release files from release note in github.com.
+// key of return value is platform of runner (e.g. linux-x64, win-x64)
+func GetRunnerReleaseChecksums(ctx context.Context, runnerVersion string) (map[string]string, error) {
+	client := github.NewClient(newGitHubHTTPClient(newInstrumentedTransport(http.DefaultTransport)))
+	release, _, err := client.Repositories.GetReleaseByTag(ctx, "actions", "runner", runnerVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release of actions/runner (version: %s): %w", runnerVersion, err)
+	}
+
+	checksums := ParseRunnerReleaseChecksums(release.GetBody())
+	if len(checksums) == 0 {
+		return nil, fmt.Errorf("checksum is not found in release note (version: %s): %w", runnerVersion, ErrNotFound)
+	}
+	return checksums, nil
+}
+
+// ParseRunnerReleaseChecksums parse checksums from release note of actions/runner
+func ParseRunnerReleaseChecksums(body string) map[string]string {
+	checksums := map[string]string{}
+	for _, m := range runnerReleaseChecksumRegexp.FindAllStringSubmatch(body, -1) {
+		checksums[m[1]] = strings.ToLower(m[2])
+	}
+
+	return checksums
+}
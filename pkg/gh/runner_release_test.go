@@ -0,0 +1,32 @@
+package gh
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRunnerReleaseChecksums(t *testing.T) {
+	body := `## Windows x64
+` + "```" + `
+Invoke-WebRequest -Uri https://github.com/actions/runner/releases/download/v2.311.0/actions-runner-win-x64-2.311.0.zip -OutFile actions-runner-win-x64-2.311.0.zip
+` + "```" + `
+## SHA-256 Checksums
+
+- actions-runner-win-x64-2.311.0.zip <!-- BEGIN SHA win-x64 -->E629628EF9F1A2A5D5B1A2B3C4D5E6F708192A3B4C5D6E7F8091A2B3C4D5E6F7<!-- END SHA win-x64 -->
+- actions-runner-linux-x64-2.311.0.tar.gz <!-- BEGIN SHA linux-x64 -->29fc8cf2dab4c195bb147384e7e2c94cfd4d4022c793b346a6175435265aa278<!-- END SHA linux-x64 -->
+- actions-runner-linux-x64-2.311.0-noexternals.tar.gz <!-- BEGIN SHA linux-x64_noexternals -->0000000000000000000000000000000000000000000000000000000000000000<!-- END SHA linux-x64_noexternals -->
+- invalid <!-- BEGIN SHA linux-arm64 -->not-checksum<!-- END SHA linux-arm64 -->
+`
+
+	want := map[string]string{
+		"win-x64":               "e629628ef9f1a2a5d5b1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f7",
+		"linux-x64":             "29fc8cf2dab4c195bb147384e7e2c94cfd4d4022c793b346a6175435265aa278",
+		"linux-x64_noexternals": "0000000000000000000000000000000000000000000000000000000000000000",
+	}
+
+	got := ParseRunnerReleaseChecksums(body)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,43 @@
+package gh
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v80/github"
+)
+
+func TestGetRunnerVersion(t *testing.T) {
+	applications := []*github.RunnerApplicationDownload{
+		{OS: github.Ptr("osx"), Architecture: github.Ptr("x64"), Filename: github.Ptr("actions-runner-osx-x64-2.311.0.tar.gz")},
+		{OS: github.Ptr("linux"), Architecture: github.Ptr("x64"), Filename: github.Ptr("actions-runner-linux-x64-2.311.0.tar.gz")},
+		{OS: github.Ptr("linux"), Architecture: github.Ptr("arm64"), Filename: github.Ptr("actions-runner-linux-arm64-2.311.0.tar.gz")},
+		{OS: github.Ptr("win"), Architecture: github.Ptr("x64"), Filename: github.Ptr("actions-runner-win-x64-2.311.0.zip")},
+		{OS: github.Ptr("win"), Architecture: github.Ptr("arm64"), Filename: github.Ptr("actions-runner-win-arm64-2.311.0.zip")},
+	}
+
+	tests := []struct {
+		os   string
+		arch string
+		want string
+		err  bool
+	}{
+		{os: "linux", arch: "x64", want: "v2.311.0"},
+		{os: "linux", arch: "arm64", want: "v2.311.0"},
+		{os: "win", arch: "x64", want: "v2.311.0"},
+		{os: "win", arch: "arm64", want: "v2.311.0"},
+		{os: "linux", arch: "arm", err: true},
+	}
+
+	for _, test := range tests {
+		got, err := getRunnerVersion(applications, test.os, test.arch)
+		if !test.err && err != nil {
+			t.Fatalf("failed to get runner version (os: %s, arch: %s): %+v", test.os, test.arch, err)
+		}
+		if test.err && err == nil {
+			t.Fatalf("must be error (os: %s, arch: %s)", test.os, test.arch)
+		}
+		if got != test.want {
+			t.Fatalf("want %s, but got %s (os: %s, arch: %s)", test.want, got, test.os, test.arch)
+		}
+	}
+}
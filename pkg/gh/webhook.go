@@ -48,3 +48,69 @@ func ExtractRunsOnLabels(in []byte) ([]string, error) {
 
 	return []string{}, nil
 }
+
+// ExtractWorkflowJobID extract ID of job from github.WorkflowJobEvent.
+// return 0 if input is not workflow_job (e.g. check_run)
+func ExtractWorkflowJobID(in []byte) (int64, error) {
+	event, err := parseEventJSON(in)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse event json: %w", err)
+	}
+
+	switch t := event.(type) {
+	case *github.WorkflowJobEvent:
+		return t.GetWorkflowJob().GetID(), nil
+	case *github.WorkflowJob:
+		return t.GetID(), nil
+	}
+
+	return 0, nil
+}
+
+// WorkflowJobInfo is information of a job in webhook
+type WorkflowJobInfo struct {
+	RunID        int64    `json:"run_id,omitempty"`
+	JobID        int64    `json:"job_id,omitempty"`
+	WorkflowName string   `json:"workflow_name,omitempty"`
+	JobName      string   `json:"job_name,omitempty"`
+	HeadBranch   string   `json:"head_branch,omitempty"`
+	Labels       []string `json:"labels"`
+	HTMLURL      string   `json:"html_url,omitempty"`
+}
+
+// ExtractWorkflowJobInfo extract information of job from webhook (workflow_job or check_run)
+func ExtractWorkflowJobInfo(in []byte) (*WorkflowJobInfo, error) {
+	event, err := parseEventJSON(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse event json: %w", err)
+	}
+
+	var job *github.WorkflowJob
+	switch t := event.(type) {
+	case *github.WorkflowJobEvent:
+		job = t.GetWorkflowJob()
+	case *github.WorkflowJob:
+		job = t
+	case *github.CheckRunEvent:
+		return &WorkflowJobInfo{
+			JobName:    t.GetCheckRun().GetName(),
+			HeadBranch: t.GetCheckRun().GetCheckSuite().GetHeadBranch(),
+			Labels:     []string{},
+			HTMLURL:    t.GetCheckRun().GetHTMLURL(),
+		}, nil
+	}
+
+	labels := job.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	return &WorkflowJobInfo{
+		RunID:        job.GetRunID(),
+		JobID:        job.GetID(),
+		WorkflowName: job.GetWorkflowName(),
+		JobName:      job.GetName(),
+		HeadBranch:   job.GetHeadBranch(),
+		Labels:       labels,
+		HTMLURL:      job.GetHTMLURL(),
+	}, nil
+}
@@ -0,0 +1,87 @@
+package gh
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExtractWorkflowJobID(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int64
+		err   bool
+	}{
+		{
+			input: `{"action": "queued", "workflow_job": {"id": 100, "run_id": 10, "labels": ["myshoes"]}}`,
+			want:  100,
+		},
+		{
+			input: `{"action": "created", "check_run": {"id": 200}}`,
+			want:  0,
+		},
+		{
+			input: `invalid`,
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ExtractWorkflowJobID([]byte(test.input))
+		if !test.err && err != nil {
+			t.Fatalf("failed to extract job id: %+v", err)
+		}
+		if test.err && err == nil {
+			t.Fatalf("must be error (input: %s)", test.input)
+		}
+		if got != test.want {
+			t.Fatalf("want %d, but got %d", test.want, got)
+		}
+	}
+}
+
+func TestExtractWorkflowJobInfo(t *testing.T) {
+	tests := []struct {
+		input string
+		want  *WorkflowJobInfo
+		err   bool
+	}{
+		{
+			input: `{"action": "queued", "workflow_job": {"id": 100, "run_id": 10, "workflow_name": "CI", "name": "test", "head_branch": "main", "labels": ["myshoes"], "html_url": "https://github.com/octocat/Hello-World/actions/runs/10/job/100"}}`,
+			want: &WorkflowJobInfo{
+				RunID:        10,
+				JobID:        100,
+				WorkflowName: "CI",
+				JobName:      "test",
+				HeadBranch:   "main",
+				Labels:       []string{"myshoes"},
+				HTMLURL:      "https://github.com/octocat/Hello-World/actions/runs/10/job/100",
+			},
+		},
+		{
+			input: `{"action": "created", "check_run": {"id": 200, "name": "build", "check_suite": {"head_branch": "main"}}}`,
+			want: &WorkflowJobInfo{
+				JobName:    "build",
+				HeadBranch: "main",
+				Labels:     []string{},
+			},
+		},
+		{
+			input: `invalid`,
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ExtractWorkflowJobInfo([]byte(test.input))
+		if !test.err && err != nil {
+			t.Fatalf("failed to extract job info: %+v", err)
+		}
+		if test.err && err == nil {
+			t.Fatalf("must be error (input: %s)", test.input)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
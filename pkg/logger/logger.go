@@ -1,16 +1,38 @@
 package logger
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/whywaita/myshoes/pkg/config"
 )
 
+// Keys of fields in logs
+const (
+	KeyTargetID    = "target_id"
+	KeyJobID       = "job_id"
+	KeyRunnerName  = "runner_name"
+	KeyGitHubRunID = "gh_run_id"
+	KeyGitHubJobID = "gh_job_id"
+	KeyDeliveryID  = "delivery_id"
+	KeyTraceID     = "trace_id"
+)
+
+// Formats of logs
+const (
+	FormatPlain = "plain"
+	FormatText  = "text"
+	FormatJSON  = "json"
+)
+
 var (
-	logger = log.New(os.Stderr, "", log.LstdFlags)
-	logMu  sync.Mutex
+	handler slog.Handler = newPlainHandler(log.New(os.Stderr, "", log.LstdFlags))
+	logMu   sync.RWMutex
 )
 
 // SetLogger set logger in outside of library
@@ -18,23 +40,149 @@ func SetLogger(l *log.Logger) {
 	if l == nil {
 		l = log.New(os.Stderr, "", log.LstdFlags)
 	}
+	SetHandler(newPlainHandler(l))
+}
+
+// SetHandler set slog.Handler in outside of library
+func SetHandler(h slog.Handler) {
 	logMu.Lock()
-	logger = l
+	handler = h
 	logMu.Unlock()
 }
 
+// SetFormat set format of logs that output to stderr
+func SetFormat(format string) error {
+	opts := &slog.HandlerOptions{Level: runtimeLevel{}}
+	switch format {
+	case "", FormatPlain:
+		SetLogger(nil)
+	case FormatText:
+		SetHandler(slog.NewTextHandler(os.Stderr, opts))
+	case FormatJSON:
+		SetHandler(slog.NewJSONHandler(os.Stderr, opts))
+	default:
+		return fmt.Errorf("unknown log format: %s", format)
+	}
+	return nil
+}
+
+// Logger return *slog.Logger, fields in context are added to logs
+func Logger() *slog.Logger {
+	logMu.RLock()
+	defer logMu.RUnlock()
+	return slog.New(contextHandler{Handler: handler})
+}
+
 // Logf is interface for logger
 func Logf(isDebug bool, format string, v ...interface{}) {
-	logMu.Lock()
-	defer logMu.Unlock()
-
-	switch {
-	case !isDebug:
-		// normal logging
-		logger.Printf(format, v...)
-	case isDebug && config.Config.Debug:
-		// debug logging
-		format = "[DEBUG] " + format
-		logger.Printf(format, v...)
+	level := slog.LevelInfo
+	if isDebug {
+		level = slog.LevelDebug
+	}
+	output(context.Background(), level, fmt.Sprintf(format, v...))
+}
+
+// Debug output log in debug level with fields in ctx and args (key-value pairs)
+func Debug(ctx context.Context, msg string, args ...any) {
+	output(ctx, slog.LevelDebug, msg, args...)
+}
+
+// Info output log in info level with fields in ctx and args (key-value pairs)
+func Info(ctx context.Context, msg string, args ...any) {
+	output(ctx, slog.LevelInfo, msg, args...)
+}
+
+// Warn output log in warn level with fields in ctx and args (key-value pairs)
+func Warn(ctx context.Context, msg string, args ...any) {
+	output(ctx, slog.LevelWarn, msg, args...)
+}
+
+// Error output log in error level with fields in ctx and args (key-value pairs)
+func Error(ctx context.Context, msg string, args ...any) {
+	output(ctx, slog.LevelError, msg, args...)
+}
+
+func output(ctx context.Context, level slog.Level, msg string, args ...any) {
+	l := Logger()
+	if !l.Enabled(ctx, level) {
+		return
+	}
+	l.Log(ctx, level, msg, args...)
+}
+
+type fieldsKey struct{}
+
+// WithFields return context that has fields of logs, args are key-value pairs (e.g. KeyJobID, job.UUID).
+// a field that has same key is overwritten.
+func WithFields(ctx context.Context, args ...any) context.Context {
+	r := slog.NewRecord(time.Time{}, 0, "", 0)
+	r.Add(args...)
+
+	current := fieldsFromContext(ctx)
+	fields := make([]slog.Attr, 0, len(current)+r.NumAttrs())
+	fields = append(fields, current...)
+	r.Attrs(func(a slog.Attr) bool {
+		for i := range fields {
+			if fields[i].Key == a.Key {
+				fields[i] = a
+				return true
+			}
+		}
+		fields = append(fields, a)
+		return true
+	})
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+func fieldsFromContext(ctx context.Context) []slog.Attr {
+	fields, _ := ctx.Value(fieldsKey{}).([]slog.Attr)
+	return fields
+}
+
+// contextHandler add fields in context to records
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle is implementation of slog.Handler
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := fieldsFromContext(ctx)
+	if len(fields) == 0 {
+		return h.Handler.Handle(ctx, r)
+	}
+
+	// fields in context are output before attributes of record
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(fields...)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(a)
+		return true
+	})
+	return h.Handler.Handle(ctx, nr)
+}
+
+// WithAttrs is implementation of slog.Handler
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup is implementation of slog.Handler
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// runtimeLevel is slog.Leveler from log level in runtime config
+type runtimeLevel struct{}
+
+// Level is implementation of slog.Leveler
+func (runtimeLevel) Level() slog.Level {
+	switch config.GetRuntime().LogLevel {
+	case config.LogLevelDebug:
+		return slog.LevelDebug
+	case config.LogLevelWarn:
+		return slog.LevelWarn
+	case config.LogLevelError:
+		return slog.LevelError
 	}
+	return slog.LevelInfo
 }
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"testing"
+
+	"github.com/whywaita/myshoes/pkg/config"
+)
+
+func TestLogf(t *testing.T) {
+	defer SetLogger(nil)
+	var buf bytes.Buffer
+	SetLogger(log.New(&buf, "", 0))
+
+	Logf(false, "start job (job id: %s)\n", "job-1")
+	Logf(true, "debug message")
+	if got, want := buf.String(), "start job (job id: job-1)\n"; got != want {
+		t.Errorf("Logf must output compatible format, want %q but got %q", want, got)
+	}
+
+	buf.Reset()
+	ctx := WithFields(context.Background(), KeyTargetID, "target-1", KeyJobID, "job-1")
+	Info(ctx, "start job", "repo", "octocat/hello world")
+	if got, want := buf.String(), "start job target_id=target-1 job_id=job-1 repo=\"octocat/hello world\"\n"; got != want {
+		t.Errorf("fields must be appended, want %q but got %q", want, got)
+	}
+
+	buf.Reset()
+	Info(WithFields(ctx, KeyJobID, "job-2"), "overwrite")
+	if got, want := buf.String(), "overwrite target_id=target-1 job_id=job-2\n"; got != want {
+		t.Errorf("field that has same key must be overwritten, want %q but got %q", want, got)
+	}
+
+	buf.Reset()
+	Warn(ctx, "mismatched")
+	if got, want := buf.String(), "[WARN] mismatched target_id=target-1 job_id=job-1\n"; got != want {
+		t.Errorf("warn must have prefix, want %q but got %q", want, got)
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	defer SetLogger(nil)
+	defer config.SetRuntime(config.DefaultRuntime())
+
+	var buf bytes.Buffer
+	SetHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: runtimeLevel{}}))
+
+	ctx := WithFields(context.Background(), KeyDeliveryID, "delivery-1", KeyGitHubRunID, int64(10))
+	Debug(ctx, "debug message")
+	if buf.Len() != 0 {
+		t.Errorf("debug log must not be output in info level, but got %q", buf.String())
+	}
+
+	rc := config.DefaultRuntime()
+	rc.LogLevel = config.LogLevelDebug
+	if err := config.SetRuntime(rc); err != nil {
+		t.Fatalf("failed to set runtime: %+v", err)
+	}
+	Debug(ctx, "debug message", KeyJobID, "job-1")
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal log: %+v (log: %s)", err, buf.String())
+	}
+	want := map[string]interface{}{
+		"level":        "DEBUG",
+		"msg":          "debug message",
+		KeyDeliveryID:  "delivery-1",
+		KeyGitHubRunID: float64(10),
+		KeyJobID:       "job-1",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s must be %v, but got %v", k, v, got[k])
+		}
+	}
+}
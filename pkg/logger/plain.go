@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// plainHandler is slog.Handler that output to log.Logger in compatible format with Logf.
+// fields are appended as key=value.
+type plainHandler struct {
+	logger *log.Logger
+	attrs  []slog.Attr
+	prefix string // prefix of keys from groups
+}
+
+func newPlainHandler(l *log.Logger) *plainHandler {
+	return &plainHandler{logger: l}
+}
+
+// Enabled is implementation of slog.Handler
+func (h *plainHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= runtimeLevel{}.Level()
+}
+
+// Handle is implementation of slog.Handler
+func (h *plainHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	switch {
+	case r.Level < slog.LevelInfo:
+		b.WriteString("[DEBUG] ")
+	case r.Level >= slog.LevelError:
+		b.WriteString("[ERROR] ")
+	case r.Level >= slog.LevelWarn:
+		b.WriteString("[WARN] ")
+	}
+
+	if len(h.attrs) == 0 && r.NumAttrs() == 0 {
+		b.WriteString(r.Message)
+		h.logger.Print(b.String())
+		return nil
+	}
+
+	b.WriteString(strings.TrimRight(r.Message, "\n"))
+	for _, a := range h.attrs {
+		writeAttr(&b, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(&b, h.prefix, a)
+		return true
+	})
+	h.logger.Print(b.String())
+	return nil
+}
+
+func writeAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		for _, ga := range a.Value.Group() {
+			writeAttr(b, prefix+a.Key+".", ga)
+		}
+		return
+	}
+
+	value := a.Value.String()
+	if strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+	b.WriteString(" " + prefix + a.Key + "=" + value)
+}
+
+// WithAttrs is implementation of slog.Handler
+func (h *plainHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	h2.attrs = append(h2.attrs, h.attrs...)
+	for _, a := range attrs {
+		a.Key = h.prefix + a.Key
+		h2.attrs = append(h2.attrs, a)
+	}
+	return &h2
+}
+
+// WithGroup is implementation of slog.Handler
+func (h *plainHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
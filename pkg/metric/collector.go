@@ -61,8 +61,18 @@ func (c *Collector) scrape(ctx context.Context, ch chan<- prometheus.Metric) {
 	c.metrics.TotalScrapes.Inc()
 	c.metrics.Error.Set(0)
 
+	snapshotter := currentSnapshotter.Load()
+
 	var wg sync.WaitGroup
 	for _, scraper := range c.scrapers {
+		if snapshotter != nil && snapshotter.has(scraper.Name()) {
+			if err := snapshotter.collect(scraper.Name(), ch); err != nil {
+				c.metrics.ScrapeErrors.WithLabelValues(fmt.Sprintf("collect.%s", scraper.Name())).Inc()
+				c.metrics.Error.Set(1)
+			}
+			continue
+		}
+
 		wg.Add(1)
 		go func(scraper Scraper) {
 			defer wg.Done()
@@ -55,7 +55,7 @@ var (
 	datastoreRunnersRunningDesc = prometheus.NewDesc(
 		prometheus.BuildFQName(namespace, datastoreName, "runners_running"),
 		"Number of runners running",
-		[]string{"target_id"}, nil,
+		[]string{"target_id", "runner_arch"}, nil,
 	)
 )
 
@@ -215,13 +215,21 @@ func scrapeRunners(ctx context.Context, ds datastore.Datastore, ch chan<- promet
 		return fmt.Errorf("failed to list runners: %w", err)
 	}
 
-	result := map[string]float64{} // key: target_id, value: number
+	type key struct {
+		targetID   string
+		runnerArch string
+	}
+	result := map[key]float64{} // value: number
 	for _, r := range runners {
-		result[r.TargetID.String()]++
+		runnerArch := r.RunnerArch
+		if runnerArch == "" {
+			runnerArch = datastore.RunnerArchX64
+		}
+		result[key{targetID: r.TargetID.String(), runnerArch: string(runnerArch)}]++
 	}
-	for targetID, number := range result {
+	for k, number := range result {
 		ch <- prometheus.MustNewConstMetric(
-			datastoreRunnersRunningDesc, prometheus.GaugeValue, number, targetID,
+			datastoreRunnersRunningDesc, prometheus.GaugeValue, number, k.targetID, k.runnerArch,
 		)
 	}
 
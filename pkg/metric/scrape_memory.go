@@ -3,10 +3,9 @@ package metric
 import (
 	"context"
 	"fmt"
+	"sync"
 	"sync/atomic"
 
-	uuid "github.com/satori/go.uuid"
-
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/whywaita/myshoes/pkg/config"
 	"github.com/whywaita/myshoes/pkg/datastore"
@@ -69,14 +68,14 @@ var (
 		"deleting concurrency in runner",
 		[]string{"runner"}, nil,
 	)
-	memoryRunnerDeleteRetryCount = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, memoryName, "runner_delete_retry_count"),
-		"retry count of deleting in runner",
+	memoryRunnersRetryingDelete = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, memoryName, "runners_retrying_delete"),
+		"The number of runners that are retried to delete in runner",
 		[]string{"runner"}, nil,
 	)
-	memoryRunnerCreateRetryCount = prometheus.NewDesc(
-		prometheus.BuildFQName(namespace, memoryName, "runner_create_retry_count"),
-		"retry count of creating in runner",
+	memoryJobsRetryingCreate = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, memoryName, "jobs_retrying_create"),
+		"The number of jobs that are retried to create runner in starter",
 		[]string{"runner"}, nil,
 	)
 )
@@ -111,7 +110,8 @@ func (ScraperMemory) Scrape(ctx context.Context, ds datastore.Datastore, ch chan
 }
 
 func scrapeStarterValues(ch chan<- prometheus.Metric) error {
-	configMax := config.Config.MaxConnectionsToBackend
+	rc := config.GetRuntime()
+	configMax := rc.MaxConnectionsToBackend
 
 	const labelStarter = "starter"
 
@@ -136,7 +136,7 @@ func scrapeStarterValues(ch chan<- prometheus.Metric) error {
 
 	const labelRunner = "runner"
 
-	configRunnerDeletingMax := config.Config.MaxConcurrencyDeleting
+	configRunnerDeletingMax := rc.MaxConcurrencyDeleting
 	countRunnerDeletingNow := runner.ConcurrencyDeleting.Load()
 
 	ch <- prometheus.MustNewConstMetric(
@@ -144,19 +144,25 @@ func scrapeStarterValues(ch chan<- prometheus.Metric) error {
 	ch <- prometheus.MustNewConstMetric(
 		memoryRunnerQueueConcurrencyDeleting, prometheus.GaugeValue, float64(countRunnerDeletingNow), labelRunner)
 
-	runner.DeleteRetryCount.Range(func(key, value any) bool {
-		ch <- prometheus.MustNewConstMetric(
-			memoryRunnerDeleteRetryCount, prometheus.GaugeValue, float64(value.(int)), key.(uuid.UUID).String())
-		return true
-	})
+	// count of retrying instead of retry count per runner and job, these are unbounded labels
+	ch <- prometheus.MustNewConstMetric(
+		memoryRunnersRetryingDelete, prometheus.GaugeValue, float64(countRetrying(&runner.DeleteRetryCount)), labelRunner)
+	ch <- prometheus.MustNewConstMetric(
+		memoryJobsRetryingCreate, prometheus.GaugeValue, float64(countRetrying(&starter.AddInstanceRetryCount)), labelStarter)
 
-	starter.AddInstanceRetryCount.Range(func(key, value any) bool {
-		ch <- prometheus.MustNewConstMetric(
-			memoryRunnerCreateRetryCount, prometheus.GaugeValue, float64(value.(int)), key.(uuid.UUID).String())
+	return nil
+}
+
+// countRetrying return the number of keys that retry count is more than zero
+func countRetrying(m *sync.Map) int {
+	count := 0
+	m.Range(func(key, value any) bool {
+		if c, _ := value.(int); c > 0 {
+			count++
+		}
 		return true
 	})
-
-	return nil
+	return count
 }
 
 func scrapeGitHubValues(ch chan<- prometheus.Metric) error {
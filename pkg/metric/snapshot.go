@@ -0,0 +1,181 @@
+package metric
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/logger"
+)
+
+var (
+	snapshotTimestampDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "collector_snapshot_timestamp_seconds"),
+		"Unix time of last successful refresh of snapshot.",
+		[]string{"collector"}, nil,
+	)
+	snapshotAgeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "collector_snapshot_age_seconds"),
+		"Seconds since last successful refresh of snapshot.",
+		[]string{"collector"}, nil,
+	)
+
+	// currentSnapshotter is used by Collector if set
+	currentSnapshotter atomic.Pointer[Snapshotter]
+)
+
+// snapshot is metrics of a scraper at a time
+type snapshot struct {
+	metrics     []prometheus.Metric
+	duration    time.Duration
+	refreshedAt time.Time // time of last successful refresh
+	err         error     // error of last refresh
+}
+
+// Snapshotter refresh scrapers in background, Collector serves cached metrics instead of scraping on each request
+type Snapshotter struct {
+	ds        datastore.Datastore
+	scrapers  []Scraper
+	intervals map[string]time.Duration
+
+	mu        sync.RWMutex
+	snapshots map[string]snapshot
+}
+
+// NewSnapshotter create a Snapshotter, intervals is key of scraper name or config.SnapshotIntervalWildcard
+func NewSnapshotter(ds datastore.Datastore, scrapers []Scraper, intervals map[string]time.Duration) (*Snapshotter, error) {
+	names := map[string]struct{}{}
+	for _, scraper := range scrapers {
+		names[scraper.Name()] = struct{}{}
+	}
+
+	s := &Snapshotter{
+		ds:        ds,
+		intervals: map[string]time.Duration{},
+		snapshots: map[string]snapshot{},
+	}
+	for name := range intervals {
+		if _, ok := names[name]; !ok && name != config.SnapshotIntervalWildcard {
+			return nil, fmt.Errorf("unknown scraper %s", name)
+		}
+	}
+	for _, scraper := range scrapers {
+		interval, ok := intervals[scraper.Name()]
+		if !ok {
+			interval, ok = intervals[config.SnapshotIntervalWildcard]
+		}
+		if !ok {
+			continue
+		}
+		s.scrapers = append(s.scrapers, scraper)
+		s.intervals[scraper.Name()] = interval
+	}
+	return s, nil
+}
+
+// StartSnapshot refresh scrapers in background until ctx is done, it is used by Collector after started
+func StartSnapshot(ctx context.Context, ds datastore.Datastore, intervals map[string]time.Duration) error {
+	s, err := NewSnapshotter(ds, NewScrapers(), intervals)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshotter: %w", err)
+	}
+	currentSnapshotter.Store(s)
+	defer currentSnapshotter.Store(nil)
+
+	s.Run(ctx)
+	return nil
+}
+
+// Run refresh each scraper on its interval until ctx is done
+func (s *Snapshotter) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, scraper := range s.scrapers {
+		wg.Add(1)
+		go func(scraper Scraper) {
+			defer wg.Done()
+			interval := s.intervals[scraper.Name()]
+			logger.Logf(false, "start to refresh snapshot of metrics (name: %s, interval: %s)", scraper.Name(), interval)
+
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				s.refresh(ctx, scraper)
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(scraper)
+	}
+	wg.Wait()
+}
+
+// refresh scrape metrics and store these, metrics of previous snapshot are kept if scraping is failed
+func (s *Snapshotter) refresh(ctx context.Context, scraper Scraper) {
+	ctx, cancel := context.WithTimeout(ctx, s.intervals[scraper.Name()])
+	defer cancel()
+
+	ch := make(chan prometheus.Metric)
+	done := make(chan struct{})
+	var metrics []prometheus.Metric
+	go func() {
+		defer close(done)
+		for m := range ch {
+			metrics = append(metrics, m)
+		}
+	}()
+
+	startTime := time.Now()
+	err := scraper.Scrape(ctx, s.ds, ch)
+	close(ch)
+	<-done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap := s.snapshots[scraper.Name()]
+	snap.err = err
+	if err != nil {
+		logger.Logf(false, "failed to refresh snapshot of metrics (name: %s): %+v", scraper.Name(), err)
+		s.snapshots[scraper.Name()] = snap
+		return
+	}
+	snap.metrics = metrics
+	snap.duration = time.Since(startTime)
+	snap.refreshedAt = time.Now()
+	s.snapshots[scraper.Name()] = snap
+}
+
+// has return true if scraper is refreshed by Snapshotter
+func (s *Snapshotter) has(name string) bool {
+	_, ok := s.intervals[name]
+	return ok
+}
+
+// collect send metrics in snapshot with staleness, return error of last refresh.
+// nothing is sent until first refresh is succeeded.
+func (s *Snapshotter) collect(name string, ch chan<- prometheus.Metric) error {
+	s.mu.RLock()
+	snap, ok := s.snapshots[name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	label := fmt.Sprintf("collect.%s", name)
+	if !snap.refreshedAt.IsZero() {
+		for _, m := range snap.metrics {
+			ch <- m
+		}
+		ch <- prometheus.MustNewConstMetric(scrapeDurationDesc, prometheus.GaugeValue, snap.duration.Seconds(), label)
+		ch <- prometheus.MustNewConstMetric(snapshotTimestampDesc, prometheus.GaugeValue, float64(snap.refreshedAt.UnixNano())/1e9, label)
+		ch <- prometheus.MustNewConstMetric(snapshotAgeDesc, prometheus.GaugeValue, time.Since(snap.refreshedAt).Seconds(), label)
+	}
+	return snap.err
+}
@@ -0,0 +1,94 @@
+package metric
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+var stubDesc = prometheus.NewDesc("myshoes_stub_value", "stub", nil, nil)
+
+type stubScraper struct {
+	calls atomic.Int64
+	err   error
+}
+
+func (s *stubScraper) Name() string { return "stub" }
+func (s *stubScraper) Help() string { return "stub" }
+func (s *stubScraper) Scrape(ctx context.Context, ds datastore.Datastore, ch chan<- prometheus.Metric) error {
+	calls := s.calls.Add(1)
+	if s.err != nil {
+		return s.err
+	}
+	ch <- prometheus.MustNewConstMetric(stubDesc, prometheus.GaugeValue, float64(calls))
+	return nil
+}
+
+func TestNewSnapshotter(t *testing.T) {
+	scraper := &stubScraper{}
+	if _, err := NewSnapshotter(nil, []Scraper{scraper}, map[string]time.Duration{"unknown": time.Minute}); err == nil {
+		t.Errorf("unknown scraper must be error")
+	}
+
+	s, err := NewSnapshotter(nil, []Scraper{scraper}, map[string]time.Duration{"*": time.Minute})
+	if err != nil {
+		t.Fatalf("failed to create snapshotter: %+v", err)
+	}
+	if !s.has("stub") {
+		t.Errorf("scraper must be refreshed by wildcard")
+	}
+}
+
+func TestSnapshotter_collect(t *testing.T) {
+	scraper := &stubScraper{}
+	s, err := NewSnapshotter(nil, []Scraper{scraper}, map[string]time.Duration{"stub": time.Minute})
+	if err != nil {
+		t.Fatalf("failed to create snapshotter: %+v", err)
+	}
+	c := &Collector{ctx: context.Background(), metrics: NewMetrics(), scrapers: []Scraper{scraper}}
+	currentSnapshotter.Store(s)
+	defer currentSnapshotter.Store(nil)
+
+	// scraped metrics are not described by Collector, so pedantic registry of testutil.CollectAndCount can not be used
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(c)
+	count := func(names ...string) int {
+		got, err := testutil.GatherAndCount(registry, names...)
+		if err != nil {
+			t.Fatalf("failed to gather: %+v", err)
+		}
+		return got
+	}
+
+	// nothing is served before first refresh
+	if got := count("myshoes_stub_value"); got != 0 {
+		t.Errorf("must be no metrics before refresh, but got %d", got)
+	}
+
+	s.refresh(context.Background(), scraper)
+	for i := 0; i < 3; i++ {
+		if got := count("myshoes_stub_value", "myshoes_collector_snapshot_age_seconds"); got != 2 {
+			t.Errorf("must be cached value and staleness, but got %d", got)
+		}
+	}
+	if got := scraper.calls.Load(); got != 1 {
+		t.Errorf("scraper must not be called by collect, but called %d times", got)
+	}
+
+	// previous snapshot is served if refresh is failed
+	scraper.err = errors.New("datastore is down")
+	s.refresh(context.Background(), scraper)
+	if got := count("myshoes_stub_value"); got != 1 {
+		t.Errorf("previous snapshot must be served, but got %d", got)
+	}
+	if got := testutil.ToFloat64(c.metrics.Error); got != 1 {
+		t.Errorf("last_scrape_error must be 1, but got %v", got)
+	}
+}
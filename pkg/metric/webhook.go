@@ -39,4 +39,15 @@ var (
 		},
 		[]string{"event_type", "repository", "runs_on"},
 	)
+
+	// WebhookRunnerJobBinding is the total number of jobs that started in runners of myshoes
+	WebhookRunnerJobBinding = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "webhook",
+			Name:      "runner_job_binding_total",
+			Help:      "Total number of jobs that started in runners, result is matched or mismatched with requested job",
+		},
+		[]string{"result", "repository"},
+	)
 )
@@ -0,0 +1,298 @@
+package mirror
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/gh"
+	"github.com/whywaita/myshoes/pkg/logger"
+)
+
+// Path is path of runner mirror in myshoes HTTP server
+const Path = "/runner/mirror"
+
+// ChecksumSuffix is suffix of checksum file
+const ChecksumSuffix = ".sha256"
+
+var (
+	// GHGetRunnerReleaseChecksums is function of get checksums of release, for testing
+	GHGetRunnerReleaseChecksums = gh.GetRunnerReleaseChecksums
+
+	// UpstreamBaseURL is base URL of runner release files, for testing
+	UpstreamBaseURL = gh.RunnerReleaseDownloadURL
+
+	// ErrInvalidFile is error for invalid version or file name
+	ErrInvalidFile = errors.New("invalid runner version or file name")
+	// ErrNotCached is error for not cached file in offline mode
+	ErrNotCached = errors.New("runner file is not cached")
+	// ErrChecksumMismatch is error for mismatch checksum
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+var (
+	// e.g. v2.311.0
+	versionRegexp = regexp.MustCompile(`^v[0-9]+\.[0-9]+\.[0-9]+$`)
+	// e.g. actions-runner-linux-x64-2.311.0.tar.gz, actions-runner-win-x64-2.311.0.zip
+	fileRegexp = regexp.MustCompile(`^actions-runner-([a-z]+-[a-z0-9]+)-([0-9]+\.[0-9]+\.[0-9]+)(\.tar\.gz|\.zip)$`)
+
+	group singleflight.Group
+
+	// verified store checksum of verified file, key is path of file
+	verified   = map[string]string{}
+	verifiedMu sync.Mutex
+)
+
+// Enabled return true if runner mirror is enabled
+func Enabled() bool {
+	return config.Config.RunnerMirrorDirectory != ""
+}
+
+// DownloadBaseURL return base URL that runner download from.
+// a runner download file from "<base URL>/<version>/<file>".
+func DownloadBaseURL() string {
+	if !Enabled() {
+		return gh.RunnerReleaseDownloadURL
+	}
+	return config.Config.RunnerMirrorURL + Path
+}
+
+// Get get a path and checksum of runner file from mirror directory.
+// a file is downloaded from upstream and verified if it is not cached.
+func Get(ctx context.Context, version, file string) (string, string, error) {
+	platform, err := validate(version, file)
+	if err != nil {
+		return "", "", err
+	}
+
+	p := filepath.Join(config.Config.RunnerMirrorDirectory, version, file)
+	v, err, _ := group.Do(p, func() (interface{}, error) {
+		if checksum, ok := getVerified(p); ok {
+			return checksum, nil
+		}
+
+		checksum, err := verifyCache(p)
+		switch {
+		case err == nil:
+		case errors.Is(err, os.ErrNotExist):
+			if config.Config.RunnerMirrorOffline {
+				return "", fmt.Errorf("%s/%s: %w", version, file, ErrNotCached)
+			}
+			checksum, err = download(ctx, version, file, platform, p)
+			if err != nil {
+				return "", fmt.Errorf("failed to download runner file: %w", err)
+			}
+		default:
+			return "", fmt.Errorf("failed to verify cached runner file: %w", err)
+		}
+
+		setVerified(p, checksum)
+		return checksum, nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return p, v.(string), nil
+}
+
+// LatestVersion return latest version that has a runner file of platform (e.g. "linux-x64") in mirror directory.
+// it is used instead of GitHub API in offline mode, the version from GitHub may not be cached.
+func LatestVersion(platform string) (string, error) {
+	entries, err := os.ReadDir(config.Config.RunnerMirrorDirectory)
+	if err != nil {
+		return "", fmt.Errorf("failed to read mirror directory: %w", err)
+	}
+
+	var latest string
+	var latestParts []int
+	for _, e := range entries {
+		if !e.IsDir() || !versionRegexp.MatchString(e.Name()) {
+			continue
+		}
+		files, err := filepath.Glob(filepath.Join(config.Config.RunnerMirrorDirectory, e.Name(), "actions-runner-"+platform+"-*"))
+		if err != nil {
+			return "", fmt.Errorf("failed to find runner files: %w", err)
+		}
+		found := false
+		for _, f := range files {
+			if m := fileRegexp.FindStringSubmatch(filepath.Base(f)); m != nil && m[1] == platform {
+				found = true
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+
+		parts := versionParts(e.Name())
+		if latest == "" || lessVersion(latestParts, parts) {
+			latest, latestParts = e.Name(), parts
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("runner file of %s: %w", platform, ErrNotCached)
+	}
+	return latest, nil
+}
+
+// versionParts return major, minor and patch of version, version must match versionRegexp
+func versionParts(version string) []int {
+	var parts []int
+	for _, s := range strings.Split(strings.TrimPrefix(version, "v"), ".") {
+		n, _ := strconv.Atoi(s)
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+func lessVersion(a, b []int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+func validate(version, file string) (string, error) {
+	if !versionRegexp.MatchString(version) {
+		return "", fmt.Errorf("version %q: %w", version, ErrInvalidFile)
+	}
+	m := fileRegexp.FindStringSubmatch(file)
+	if m == nil {
+		return "", fmt.Errorf("file %q: %w", file, ErrInvalidFile)
+	}
+	if strings.TrimPrefix(version, "v") != m[2] {
+		return "", fmt.Errorf("file %q is not version %s: %w", file, version, ErrInvalidFile)
+	}
+
+	return m[1], nil
+}
+
+// verifyCache verify a cached file with checksum file.
+// checksum file is generated if not exists, it is for a file that placed by admin.
+func verifyCache(p string) (string, error) {
+	actual, err := fileChecksum(p)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := os.ReadFile(p + ChecksumSuffix)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		logger.Logf(false, "checksum file of runner mirror is not found, generate it (path: %s)", p)
+		if err := os.WriteFile(p+ChecksumSuffix, []byte(actual), 0644); err != nil {
+			return "", fmt.Errorf("failed to write checksum file: %w", err)
+		}
+		return actual, nil
+	case err != nil:
+		return "", fmt.Errorf("failed to read checksum file: %w", err)
+	}
+
+	expected := strings.ToLower(strings.TrimSpace(string(b)))
+	if expected != actual {
+		return "", fmt.Errorf("%s (expected: %s, actual: %s): %w", p, expected, actual, ErrChecksumMismatch)
+	}
+	return actual, nil
+}
+
+func fileChecksum(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// download download a runner file from upstream and verify with checksum in release note
+func download(ctx context.Context, version, file, platform, p string) (string, error) {
+	checksums, err := GHGetRunnerReleaseChecksums(ctx, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to get checksums of runner release: %w", err)
+	}
+	expected, ok := checksums[platform]
+	if !ok {
+		return "", fmt.Errorf("checksum of %s is not found in release %s", platform, version)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), file+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	u := fmt.Sprintf("%s/%s/%s", UpstreamBaseURL, version, file)
+	logger.Logf(false, "download runner file to mirror (url: %s)", u)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("invalid status code (url: %s, status code: %d)", u, resp.StatusCode)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), resp.Body); err != nil {
+		return "", fmt.Errorf("failed to write runner file: %w", err)
+	}
+	actual := hex.EncodeToString(h.Sum(nil))
+	if actual != expected {
+		return "", fmt.Errorf("%s (expected: %s, actual: %s): %w", u, expected, actual, ErrChecksumMismatch)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err := os.WriteFile(p+ChecksumSuffix, []byte(actual), 0644); err != nil {
+		return "", fmt.Errorf("failed to write checksum file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return "", fmt.Errorf("failed to rename runner file: %w", err)
+	}
+
+	return actual, nil
+}
+
+func getVerified(p string) (string, bool) {
+	verifiedMu.Lock()
+	defer verifiedMu.Unlock()
+
+	checksum, ok := verified[p]
+	return checksum, ok
+}
+
+func setVerified(p, checksum string) {
+	verifiedMu.Lock()
+	defer verifiedMu.Unlock()
+
+	verified[p] = checksum
+}
@@ -0,0 +1,188 @@
+package mirror
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/whywaita/myshoes/pkg/config"
+)
+
+const (
+	testVersion = "v2.311.0"
+	testFile    = "actions-runner-linux-x64-2.311.0.tar.gz"
+	testBody    = "runner binary"
+)
+
+func testChecksum(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+func setStubs(t *testing.T, checksum string, offline bool) *int {
+	t.Helper()
+
+	var requested int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested++
+		if r.URL.Path != "/"+testVersion+"/"+testFile {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(testBody))
+	}))
+	t.Cleanup(ts.Close)
+
+	oldConfig, oldURL, oldChecksums := config.Config, UpstreamBaseURL, GHGetRunnerReleaseChecksums
+	t.Cleanup(func() {
+		config.Config, UpstreamBaseURL, GHGetRunnerReleaseChecksums = oldConfig, oldURL, oldChecksums
+		verified = map[string]string{}
+	})
+
+	config.Config.RunnerMirrorDirectory = t.TempDir()
+	config.Config.RunnerMirrorOffline = offline
+	UpstreamBaseURL = ts.URL
+	GHGetRunnerReleaseChecksums = func(ctx context.Context, version string) (map[string]string, error) {
+		return map[string]string{"linux-x64": checksum}, nil
+	}
+
+	return &requested
+}
+
+func TestGet(t *testing.T) {
+	requested := setStubs(t, testChecksum(testBody), false)
+
+	for i := 0; i < 2; i++ {
+		p, checksum, err := Get(context.Background(), testVersion, testFile)
+		if err != nil {
+			t.Fatalf("failed to get runner file: %+v", err)
+		}
+		if checksum != testChecksum(testBody) {
+			t.Fatalf("want checksum %s, but got %s", testChecksum(testBody), checksum)
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatalf("failed to read cached file: %+v", err)
+		}
+		if string(b) != testBody {
+			t.Fatalf("want %q, but got %q", testBody, string(b))
+		}
+	}
+
+	if *requested != 1 {
+		t.Fatalf("upstream must be requested only once, but requested %d times", *requested)
+	}
+}
+
+func TestGet_ChecksumMismatch(t *testing.T) {
+	setStubs(t, testChecksum("other binary"), false)
+
+	_, _, err := Get(context.Background(), testVersion, testFile)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("want ErrChecksumMismatch, but got %+v", err)
+	}
+	if _, err := os.Stat(filepath.Join(config.Config.RunnerMirrorDirectory, testVersion, testFile)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("mismatched file must not be cached: %+v", err)
+	}
+}
+
+func TestGet_Offline(t *testing.T) {
+	requested := setStubs(t, testChecksum(testBody), true)
+
+	_, _, err := Get(context.Background(), testVersion, testFile)
+	if !errors.Is(err, ErrNotCached) {
+		t.Fatalf("want ErrNotCached, but got %+v", err)
+	}
+
+	// file placed by admin
+	dir := filepath.Join(config.Config.RunnerMirrorDirectory, testVersion)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create directory: %+v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, testFile), []byte(testBody), 0644); err != nil {
+		t.Fatalf("failed to write file: %+v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, testFile+ChecksumSuffix), []byte(testChecksum("other binary")+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %+v", err)
+	}
+	if _, _, err := Get(context.Background(), testVersion, testFile); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("want ErrChecksumMismatch, but got %+v", err)
+	}
+
+	if err := os.Remove(filepath.Join(dir, testFile+ChecksumSuffix)); err != nil {
+		t.Fatalf("failed to remove file: %+v", err)
+	}
+	_, checksum, err := Get(context.Background(), testVersion, testFile)
+	if err != nil {
+		t.Fatalf("failed to get runner file: %+v", err)
+	}
+	if checksum != testChecksum(testBody) {
+		t.Fatalf("want checksum %s, but got %s", testChecksum(testBody), checksum)
+	}
+
+	if *requested != 0 {
+		t.Fatalf("upstream must not be requested in offline mode, but requested %d times", *requested)
+	}
+}
+
+func TestGet_Invalid(t *testing.T) {
+	setStubs(t, testChecksum(testBody), false)
+
+	tests := []struct {
+		version string
+		file    string
+	}{
+		{version: "latest", file: testFile},
+		{version: "v2.311.0", file: "../../etc/passwd"},
+		{version: "v2.311.0", file: "actions-runner-linux-x64-2.310.0.tar.gz"},
+		{version: "../v2.311.0", file: testFile},
+		{version: "v2.311.0", file: testFile + ChecksumSuffix},
+	}
+
+	for _, test := range tests {
+		if _, _, err := Get(context.Background(), test.version, test.file); !errors.Is(err, ErrInvalidFile) {
+			t.Fatalf("want ErrInvalidFile (version: %s, file: %s), but got %+v", test.version, test.file, err)
+		}
+	}
+}
+
+func TestLatestVersion(t *testing.T) {
+	setStubs(t, testChecksum(testBody), true)
+
+	if _, err := LatestVersion("linux-x64"); !errors.Is(err, ErrNotCached) {
+		t.Fatalf("want ErrNotCached, but got %+v", err)
+	}
+
+	for _, f := range []string{
+		"v2.9.0/actions-runner-linux-x64-2.9.0.tar.gz",
+		"v2.311.0/actions-runner-linux-x64-2.311.0.tar.gz",
+		"v2.312.0/actions-runner-win-x64-2.312.0.zip",
+	} {
+		p := filepath.Join(config.Config.RunnerMirrorDirectory, f)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("failed to create directory: %+v", err)
+		}
+		if err := os.WriteFile(p, []byte(testBody), 0644); err != nil {
+			t.Fatalf("failed to write file: %+v", err)
+		}
+	}
+
+	for platform, want := range map[string]string{"linux-x64": "v2.311.0", "win-x64": "v2.312.0"} {
+		got, err := LatestVersion(platform)
+		if err != nil {
+			t.Fatalf("failed to get latest version: %+v", err)
+		}
+		if got != want {
+			t.Errorf("want %s (platform: %s), but got %s", want, platform, got)
+		}
+	}
+	if _, err := LatestVersion("linux-arm64"); !errors.Is(err, ErrNotCached) {
+		t.Fatalf("want ErrNotCached, but got %+v", err)
+	}
+}
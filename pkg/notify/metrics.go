@@ -0,0 +1,24 @@
+package notify
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// SentTotal is counter of sent notifications
+	SentTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "myshoes",
+		Subsystem: "notify",
+		Name:      "sent_total",
+		Help:      "Total number of notifications sent to sinks",
+	}, []string{"sink", "kind", "result"})
+
+	// DroppedTotal is counter of dropped notifications by deduplication or rate limiting
+	DroppedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "myshoes",
+		Subsystem: "notify",
+		Name:      "dropped_total",
+		Help:      "Total number of notifications dropped by deduplication or rate limiting",
+	}, []string{"kind", "reason"})
+)
@@ -0,0 +1,153 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/logger"
+)
+
+// Kinds of event
+const (
+	KindTargetError        = "target_error"
+	KindJobRetryExceeded   = "job_retry_exceeded"
+	KindRunnerDeleteFailed = "runner_delete_failed"
+)
+
+// sendTimeout is timeout of sending an event to a sink
+const sendTimeout = 10 * time.Second
+
+// Event is a notification
+type Event struct {
+	Kind    string            `json:"kind"`
+	Key     string            `json:"key"` // same Kind and Key are deduplicated (e.g. ID of target)
+	Title   string            `json:"title"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+	Time    time.Time         `json:"time"`
+}
+
+// Sink send an event to external service
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, e Event) error
+}
+
+// Notifier send events to sinks with deduplication and rate limiting
+type Notifier struct {
+	sinks       []Sink
+	dedupWindow time.Duration
+	rateLimit   int // per minute
+
+	mu          sync.Mutex
+	sent        map[string]time.Time // key: Kind/Key, value: time of last sent
+	windowStart time.Time
+	windowCount int
+
+	wg sync.WaitGroup
+}
+
+// New create a Notifier
+func New(sinks []Sink, dedupWindow time.Duration, rateLimit int) *Notifier {
+	return &Notifier{
+		sinks:       sinks,
+		dedupWindow: dedupWindow,
+		rateLimit:   rateLimit,
+		sent:        map[string]time.Time{},
+	}
+}
+
+// Notify send an event to sinks in background, an event that is duplicated or over rate limit is dropped
+func (n *Notifier) Notify(ctx context.Context, e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if reason := n.allow(e); reason != "" {
+		logger.Debug(ctx, "notification is dropped", "kind", e.Kind, "key", e.Key, "reason", reason)
+		DroppedTotal.WithLabelValues(e.Kind, reason).Inc()
+		return
+	}
+
+	// not cancel sending even if request or job is finished
+	ctx = context.WithoutCancel(ctx)
+	for _, sink := range n.sinks {
+		n.wg.Add(1)
+		go func(sink Sink) {
+			defer n.wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+			defer cancel()
+
+			if err := sink.Send(ctx, e); err != nil {
+				logger.Error(ctx, "failed to send notification", "sink", sink.Name(), "kind", e.Kind, "error", err)
+				SentTotal.WithLabelValues(sink.Name(), e.Kind, "error").Inc()
+				return
+			}
+			SentTotal.WithLabelValues(sink.Name(), e.Kind, "success").Inc()
+		}(sink)
+	}
+}
+
+// reasons of dropping
+const (
+	reasonDuplicated  = "duplicated"
+	reasonRateLimited = "rate_limited"
+)
+
+// allow return reason if event must be dropped, return empty if event can be sent
+func (n *Notifier) allow(e Event) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for k, sentAt := range n.sent {
+		if e.Time.Sub(sentAt) >= n.dedupWindow {
+			delete(n.sent, k)
+		}
+	}
+	key := fmt.Sprintf("%s/%s", e.Kind, e.Key)
+	if _, ok := n.sent[key]; ok {
+		return reasonDuplicated
+	}
+
+	if e.Time.Sub(n.windowStart) >= time.Minute {
+		n.windowStart = e.Time
+		n.windowCount = 0
+	}
+	if n.windowCount >= n.rateLimit {
+		return reasonRateLimited
+	}
+
+	n.windowCount++
+	n.sent[key] = e.Time
+	return ""
+}
+
+var current atomic.Pointer[Notifier]
+
+// Init configure sinks by config, notifications are disabled if no sink is configured
+func Init(c config.Notify) {
+	if !c.Enabled() {
+		current.Store(nil)
+		return
+	}
+
+	var sinks []Sink
+	for _, u := range c.WebhookURLs {
+		sinks = append(sinks, NewWebhookSink(u))
+	}
+	for _, u := range c.SlackWebhookURLs {
+		sinks = append(sinks, NewSlackSink(u))
+	}
+	current.Store(New(sinks, c.DedupWindow, c.RateLimit))
+	logger.Logf(false, "notifications are enabled (sinks: %d)", len(sinks))
+}
+
+// Notify send an event by configured Notifier, do nothing if notifications are disabled
+func Notify(ctx context.Context, e Event) {
+	if n := current.Load(); n != nil {
+		n.Notify(ctx, e)
+	}
+}
@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type stubSink struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (s *stubSink) Name() string { return "stub" }
+func (s *stubSink) Send(ctx context.Context, e Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, e)
+	return nil
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	sink := &stubSink{}
+	n := New([]Sink{sink}, time.Hour, 2)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	events := []Event{
+		{Kind: KindTargetError, Key: "target-1", Time: now},
+		// duplicated
+		{Kind: KindTargetError, Key: "target-1", Time: now.Add(time.Second)},
+		{Kind: KindJobRetryExceeded, Key: "target-1", Time: now.Add(2 * time.Second)},
+		// rate limited
+		{Kind: KindTargetError, Key: "target-2", Time: now.Add(3 * time.Second)},
+		// next window of rate limit
+		{Kind: KindTargetError, Key: "target-2", Time: now.Add(time.Minute)},
+		// after dedup window
+		{Kind: KindTargetError, Key: "target-1", Time: now.Add(time.Hour)},
+	}
+	for _, e := range events {
+		n.Notify(context.Background(), e)
+	}
+	n.wg.Wait()
+
+	var got []string
+	for _, e := range sink.events {
+		got = append(got, e.Kind+"/"+e.Key)
+	}
+	// events are sent in background, order is not stable
+	sort.Strings(got)
+	want := []string{"job_retry_exceeded/target-1", "target_error/target-1", "target_error/target-1", "target_error/target-2"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("must be sent %v, but got %v", want, got)
+	}
+}
+
+func TestSinks(t *testing.T) {
+	var mu sync.Mutex
+	bodies := map[string]string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies[r.URL.Path] = string(b)
+		mu.Unlock()
+		if r.URL.Path == "/error" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	e := Event{
+		Kind:    KindTargetError,
+		Key:     "target-1",
+		Title:   "target octocat/Hello-World is error",
+		Message: "failed to create an instance",
+		Fields:  map[string]string{"scope": "octocat/Hello-World", "target_id": "target-1"},
+	}
+
+	if err := NewWebhookSink(ts.URL+"/webhook").Send(context.Background(), e); err != nil {
+		t.Fatalf("failed to send webhook: %+v", err)
+	}
+	var got Event
+	if err := json.Unmarshal([]byte(bodies["/webhook"]), &got); err != nil {
+		t.Fatalf("failed to unmarshal webhook payload: %+v", err)
+	}
+	if got.Kind != e.Kind || got.Fields["scope"] != "octocat/Hello-World" {
+		t.Errorf("invalid webhook payload: %s", bodies["/webhook"])
+	}
+
+	if err := NewSlackSink(ts.URL+"/slack").Send(context.Background(), e); err != nil {
+		t.Fatalf("failed to send slack: %+v", err)
+	}
+	var msg slackMessage
+	if err := json.Unmarshal([]byte(bodies["/slack"]), &msg); err != nil {
+		t.Fatalf("failed to unmarshal slack payload: %+v", err)
+	}
+	wantText := "*[myshoes] target octocat/Hello-World is error*\nfailed to create an instance\nscope: `octocat/Hello-World`\ntarget_id: `target-1`"
+	if msg.Text != wantText {
+		t.Errorf("must be %q, but got %q", wantText, msg.Text)
+	}
+
+	if err := NewWebhookSink(ts.URL+"/error").Send(context.Background(), e); err == nil {
+		t.Errorf("status code 500 must be error")
+	}
+}
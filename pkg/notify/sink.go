@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"sort"
+	"strings"
+)
+
+// WebhookSink post an event as JSON to a HTTP endpoint
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink create a WebhookSink
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{url: url, client: http.DefaultClient}
+}
+
+// Name return name of sink
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+// Send post an event
+func (s *WebhookSink) Send(ctx context.Context, e Event) error {
+	return postJSON(ctx, s.client, s.url, e)
+}
+
+// SlackSink post an event to Slack incoming webhook (or compatible endpoint)
+type SlackSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewSlackSink create a SlackSink
+func NewSlackSink(url string) *SlackSink {
+	return &SlackSink{url: url, client: http.DefaultClient}
+}
+
+// Name return name of sink
+func (s *SlackSink) Name() string {
+	return "slack"
+}
+
+// slackMessage is payload of Slack incoming webhook
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// Send post an event as text message
+func (s *SlackSink) Send(ctx context.Context, e Event) error {
+	return postJSON(ctx, s.client, s.url, slackMessage{Text: formatText(e)})
+}
+
+// formatText format event for chat, fields are sorted by key
+func formatText(e Event) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*[myshoes] %s*\n%s", e.Title, e.Message)
+
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\n%s: `%s`", k, e.Fields[k])
+	}
+	return b.String()
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// not output URL, it may have token
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to post payload: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("invalid status code %d", resp.StatusCode)
+	}
+	return nil
+}
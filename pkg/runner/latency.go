@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v80/github"
+
+	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/logger"
+)
+
+// RecordRunnerOnline save time that runner became online in GitHub, and observe duration from instance created
+func RecordRunnerOnline(ctx context.Context, ds datastore.Datastore, r datastore.Runner, scope string, onlineAt time.Time) error {
+	if err := ds.SetRunnerOnline(ctx, r.UUID, onlineAt); err != nil {
+		return fmt.Errorf("failed to set runner online: %w", err)
+	}
+	if r.InstanceCreatedAt.Valid {
+		RunnerOnlineDuration.WithLabelValues(scope, r.ResourceType.String()).Observe(onlineAt.Sub(r.InstanceCreatedAt.Time).Seconds())
+	}
+	return nil
+}
+
+// recordOnlineRunners record runners that are found as online in GitHub at first time
+func (m *Manager) recordOnlineRunners(ctx context.Context, t datastore.Target, runners []datastore.Runner, ghRunners []*github.Runner) {
+	online := map[string]struct{}{}
+	for _, ghRunner := range ghRunners {
+		if ghRunner.GetStatus() == StatusSleep {
+			online[ghRunner.GetName()] = struct{}{}
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, r := range runners {
+		if r.OnlineAt.Valid {
+			continue
+		}
+		if _, ok := online[ToName(r.UUID.String())]; !ok {
+			continue
+		}
+		if err := RecordRunnerOnline(ctx, m.ds, r, t.Scope, now); err != nil {
+			logger.Logf(false, "failed to record runner online (runner: %s): %+v", r.UUID, err)
+		}
+	}
+}
+
+// recordUsage add runner-minutes and estimated cost of deleted runner
+func recordUsage(ctx context.Context, ds datastore.Datastore, r datastore.Runner, deletedAt time.Time) {
+	scope := r.TargetID.String()
+	if t, err := ds.GetTarget(ctx, r.TargetID); err == nil {
+		scope = t.Scope
+	}
+
+	minutes := deletedAt.Sub(r.CreatedAt).Minutes()
+	if minutes <= 0 {
+		return
+	}
+	resourceType := r.ResourceType.String()
+	UsageRunnerMinutesTotal.WithLabelValues(scope, resourceType, r.ShoesType).Add(minutes)
+	UsageEstimatedCostTotal.WithLabelValues(scope, resourceType, r.ShoesType).Add(minutes * config.Config.PriceTable.PerMinute(r.ShoesType, resourceType))
+}
@@ -1,8 +1,13 @@
 package runner
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/whywaita/myshoes/internal/util"
+	"github.com/whywaita/myshoes/pkg/datastore"
 )
 
 var (
@@ -13,13 +18,81 @@ var (
 		Name:      "delete_runner_backoff_duration_seconds",
 		Help:      "Histogram of exponential backoff duration in seconds for deleting runner",
 		Buckets:   prometheus.ExponentialBuckets(1, 2, 10), // 1s, 2s, 4s, 8s, 16s, 32s, 64s, 128s, 256s, 512s
-	}, []string{"runner_uuid"})
+	}, []string{"target", "resource_type"})
 
 	// DeleteRunnerRetryTotal is counter of total retries for deleting runner
 	DeleteRunnerRetryTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "myshoes",
 		Subsystem: "runner",
 		Name:      "delete_runner_retry_total",
-		Help:      "Total number of retries for deleting runner",
-	}, []string{"runner_uuid"})
+		Help:      "Total number of failures of deleting runner that will be retried",
+	}, []string{"target", "resource_type", "error_class"})
+
+	// RunnerOnlineDuration is histogram of duration from instance created to runner online in GitHub
+	RunnerOnlineDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "myshoes",
+		Subsystem: "runner",
+		Name:      "online_duration_seconds",
+		Help:      "Histogram of duration in seconds from instance created to runner online in GitHub",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 12), // 0.5s ... 1024s
+	}, []string{"target", "resource_type"})
+
+	// JobPickupDuration is histogram of duration from runner online to job started in runner
+	JobPickupDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "myshoes",
+		Subsystem: "runner",
+		Name:      "job_pickup_duration_seconds",
+		Help:      "Histogram of duration in seconds from runner online in GitHub to job started in runner",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 12), // 0.5s ... 1024s
+	}, []string{"target", "resource_type"})
+
+	// UsageRunnerMinutesTotal is counter of runner-minutes of deleted runners
+	UsageRunnerMinutesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "myshoes",
+		Subsystem: "usage",
+		Name:      "runner_minutes_total",
+		Help:      "Total runner-minutes of deleted runners",
+	}, []string{"target", "resource_type", "provider"})
+
+	// UsageEstimatedCostTotal is counter of estimated cost of deleted runners
+	UsageEstimatedCostTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "myshoes",
+		Subsystem: "usage",
+		Name:      "estimated_cost_total",
+		Help:      "Total estimated cost of deleted runners calculated by price table",
+	}, []string{"target", "resource_type", "provider"})
 )
+
+// observeDeleteRetry count a failure of deleting runner, runner name is attached as exemplar
+func observeDeleteRetry(scope string, r datastore.Runner, err error) {
+	counter := DeleteRunnerRetryTotal.WithLabelValues(scope, r.ResourceType.String(), util.ErrorClass(err))
+	if adder, ok := counter.(prometheus.ExemplarAdder); ok {
+		adder.AddWithExemplar(1, prometheus.Labels{"runner_name": ToName(r.UUID.String())})
+		return
+	}
+	counter.Inc()
+}
+
+// observeDeleteBackoff observe backoff duration before retrying, runner name is attached as exemplar
+func observeDeleteBackoff(scope string, r datastore.Runner, sleep time.Duration) {
+	observer := DeleteRunnerBackoffDuration.WithLabelValues(scope, r.ResourceType.String())
+	if eo, ok := observer.(prometheus.ExemplarObserver); ok {
+		eo.ObserveWithExemplar(sleep.Seconds(), prometheus.Labels{"runner_name": ToName(r.UUID.String())})
+		return
+	}
+	observer.Observe(sleep.Seconds())
+}
+
+// DeleteTargetMetrics delete series of target, it is called when target is deleted
+func DeleteTargetMetrics(scope string) {
+	for _, vec := range []*prometheus.MetricVec{
+		DeleteRunnerBackoffDuration.MetricVec,
+		DeleteRunnerRetryTotal.MetricVec,
+		RunnerOnlineDuration.MetricVec,
+		JobPickupDuration.MetricVec,
+		UsageRunnerMinutesTotal.MetricVec,
+		UsageEstimatedCostTotal.MetricVec,
+	} {
+		vec.DeletePartialMatch(prometheus.Labels{"target": scope})
+	}
+}
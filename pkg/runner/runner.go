@@ -3,9 +3,11 @@ package runner
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-version"
+	"github.com/whywaita/myshoes/pkg/config"
 	"github.com/whywaita/myshoes/pkg/datastore"
 	"github.com/whywaita/myshoes/pkg/logger"
 )
@@ -13,20 +15,47 @@ import (
 var (
 	// GoalCheckerInterval is interval time of check deleting runner
 	GoalCheckerInterval = 1 * time.Minute
+	// TargetTokenInterval is interval time of checking target token
+	TargetTokenInterval = 5 * time.Minute
+	//NeedRefreshToken is time of token expired
+	NeedRefreshToken = 10 * time.Minute
+	// TargetHealthCheckInterval is interval time of health check for targets in error status
+	TargetHealthCheckInterval = 5 * time.Minute
+)
+
+var (
 	// MustGoalTime is hard limit for idle runner.
 	// So it is same as the limit of GitHub Actions
+	//
+	// Deprecated: use must_goal_time of runtime config. it is used instead of runtime config if changed.
 	MustGoalTime = 6 * time.Hour
 	// MustRunningTime is set time of instance create + download binaries + etc
+	//
+	// Deprecated: use must_running_time of runtime config. it is used instead of runtime config if changed.
 	MustRunningTime = 5 * time.Minute
-	// TargetTokenInterval is interval time of checking target token
-	TargetTokenInterval = 5 * time.Minute
-	//NeedRefreshToken is time of token expired
-	NeedRefreshToken = 10 * time.Minute
 )
 
+// GetMustGoalTime return hard limit for idle runner from runtime config
+func GetMustGoalTime() time.Duration {
+	if MustGoalTime != time.Duration(config.DefaultRuntime().MustGoalTime) {
+		return MustGoalTime
+	}
+	return time.Duration(config.GetRuntime().MustGoalTime)
+}
+
+// GetMustRunningTime return time of instance create + download binaries + etc from runtime config
+func GetMustRunningTime() time.Duration {
+	if MustRunningTime != time.Duration(config.DefaultRuntime().MustRunningTime) {
+		return MustRunningTime
+	}
+	return time.Duration(config.GetRuntime().MustRunningTime)
+}
+
 // Manager is runner management
 type Manager struct {
-	ds            datastore.Datastore
+	ds datastore.Datastore
+
+	mu            sync.RWMutex
 	runnerVersion string
 }
 
@@ -38,10 +67,26 @@ func New(ds datastore.Datastore, runnerVersion string) *Manager {
 	}
 }
 
+func (m *Manager) getRunnerVersion() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.runnerVersion
+}
+
+func (m *Manager) setRunnerVersion(runnerVersion string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runnerVersion = runnerVersion
+}
+
 // Loop check
 func (m *Manager) Loop(ctx context.Context) error {
 	logger.Logf(false, "start runner loop")
 
+	config.OnRuntimeChange(func(rc config.Runtime) {
+		m.setRunnerVersion(rc.RunnerVersion)
+	})
+
 	ticker := time.NewTicker(GoalCheckerInterval)
 	defer ticker.Stop()
 
@@ -65,6 +110,22 @@ func (m *Manager) Loop(ctx context.Context) error {
 		}
 	}(ctx)
 
+	go func(ctx context.Context) {
+		healthCheckTicker := time.NewTicker(TargetHealthCheckInterval)
+		defer healthCheckTicker.Stop()
+
+		for {
+			select {
+			case <-healthCheckTicker.C:
+				if err := m.doTargetHealth(ctx); err != nil {
+					logger.Logf(false, "failed to check health of targets: %+v", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}(ctx)
+
 	for {
 		select {
 		case <-ticker.C:
@@ -10,11 +10,13 @@ import (
 	"time"
 
 	"github.com/google/go-github/v80/github"
+	uuid "github.com/satori/go.uuid"
 	"github.com/whywaita/myshoes/internal/util"
 	"github.com/whywaita/myshoes/pkg/config"
 	"github.com/whywaita/myshoes/pkg/datastore"
 	"github.com/whywaita/myshoes/pkg/gh"
 	"github.com/whywaita/myshoes/pkg/logger"
+	"github.com/whywaita/myshoes/pkg/notify"
 	"github.com/whywaita/myshoes/pkg/shoes"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
@@ -36,19 +38,47 @@ var (
 func (m *Manager) do(ctx context.Context) error {
 	logger.Logf(true, "start runner manager")
 
-	targets, err := datastore.ListTargets(ctx, m.ds)
+	// runners in paused (suspended) target also need to be deleted for draining
+	targets, err := m.ds.ListTargets(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get targets: %w", err)
 	}
 
 	logger.Logf(true, "found %d targets in datastore", len(targets))
 	for _, target := range targets {
+		if target.Status == datastore.TargetStatusDeleted {
+			continue
+		}
 		logger.Logf(true, "start to search runner in %s", target.Scope)
 		if err := m.removeRunners(ctx, target); err != nil {
 			logger.Logf(false, "failed to delete runners (target: %s): %+v", target.Scope, err)
 		}
 	}
 
+	if err := m.pruneRetryCount(ctx); err != nil {
+		logger.Logf(false, "failed to prune retry count of deleting runner: %+v", err)
+	}
+
+	return nil
+}
+
+// pruneRetryCount delete retry count of runners that are already deleted
+func (m *Manager) pruneRetryCount(ctx context.Context) error {
+	runners, err := m.ds.ListRunners(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve list of running runner: %w", err)
+	}
+	running := make(map[uuid.UUID]struct{}, len(runners))
+	for _, r := range runners {
+		running[r.UUID] = struct{}{}
+	}
+
+	DeleteRetryCount.Range(func(key, value any) bool {
+		if _, ok := running[key.(uuid.UUID)]; !ok {
+			DeleteRetryCount.Delete(key)
+		}
+		return true
+	})
 	return nil
 }
 
@@ -59,10 +89,10 @@ func (m *Manager) removeRunners(ctx context.Context, t datastore.Target) error {
 	}
 
 	var mode TemporaryMode
-	if strings.EqualFold(m.runnerVersion, "latest") {
+	if runnerVersion := m.getRunnerVersion(); strings.EqualFold(runnerVersion, "latest") {
 		mode = TemporaryEphemeral
 	} else {
-		_, m, err := GetRunnerTemporaryMode(m.runnerVersion)
+		_, m, err := GetRunnerTemporaryMode(runnerVersion)
 		if err != nil {
 			return fmt.Errorf("failed to get runner mode: %w", err)
 		}
@@ -73,6 +103,7 @@ func (m *Manager) removeRunners(ctx context.Context, t datastore.Target) error {
 	if err != nil {
 		return fmt.Errorf("failed to check number of registerd runner: %w", err)
 	}
+	m.recordOnlineRunners(ctx, t, runners, ghRunners)
 
 	if len(ghRunners) == 0 && len(runners) == 0 {
 		switch mode {
@@ -92,7 +123,7 @@ func (m *Manager) removeRunners(ctx context.Context, t datastore.Target) error {
 		return nil
 	}
 
-	sem := semaphore.NewWeighted(config.Config.MaxConcurrencyDeleting)
+	sem := semaphore.NewWeighted(config.GetRuntime().MaxConcurrencyDeleting)
 	var eg errgroup.Group
 	ConcurrencyDeleting.Store(0)
 
@@ -113,6 +144,7 @@ func (m *Manager) removeRunners(ctx context.Context, t datastore.Target) error {
 		eg.Go(func() error {
 			cctx, cancel := context.WithTimeout(ctx, DeletingTimeout)
 			defer cancel()
+			cctx = logger.WithFields(cctx, logger.KeyTargetID, t.UUID.String(), logger.KeyRunnerName, ToName(runner.UUID.String()))
 
 			defer func() {
 				sem.Release(1)
@@ -120,14 +152,17 @@ func (m *Manager) removeRunners(ctx context.Context, t datastore.Target) error {
 			}()
 			sleep := util.CalcRetryTime(count)
 			if count > 0 {
-				DeleteRunnerRetryTotal.WithLabelValues(runner.UUID.String()).Inc()
-				DeleteRunnerBackoffDuration.WithLabelValues(runner.UUID.String()).Observe(sleep.Seconds())
+				observeDeleteBackoff(t.Scope, runner, sleep)
 			}
 			time.Sleep(sleep)
 
 			if err := m.removeRunner(cctx, t, runner, ghRunners); err != nil {
 				DeleteRetryCount.Store(runner.UUID, count+1)
-				logger.Logf(false, "failed to delete runner (runner UUID: %s): %+v", runner.UUID, err)
+				observeDeleteRetry(t.Scope, runner, err)
+				logger.Error(cctx, "failed to delete runner", "error", err, "error_class", util.ErrorClass(err), "retry_count", count+1)
+				if count+1 > MaxDeleteRetry {
+					notifyDeleteFailed(cctx, t, runner, err)
+				}
 			} else {
 				DeleteRetryCount.Delete(runner.UUID)
 			}
@@ -148,16 +183,32 @@ func (m *Manager) removeRunners(ctx context.Context, t datastore.Target) error {
 	return nil
 }
 
+// notifyDeleteFailed notify that a runner will not be deleted because retry count is over MaxDeleteRetry
+func notifyDeleteFailed(ctx context.Context, t datastore.Target, runner datastore.Runner, err error) {
+	notify.Notify(ctx, notify.Event{
+		Kind:    notify.KindRunnerDeleteFailed,
+		Key:     runner.UUID.String(),
+		Title:   fmt.Sprintf("failed to delete runner in %s over %d retries", t.Scope, MaxDeleteRetry),
+		Message: err.Error(),
+		Fields: map[string]string{
+			"runner_name": ToName(runner.UUID.String()),
+			"target":      t.Scope,
+			"cloud_id":    runner.CloudID,
+			"error_class": util.ErrorClass(err),
+		},
+	})
+}
+
 func (m *Manager) removeRunner(ctx context.Context, t datastore.Target, runner datastore.Runner, ghRunners []*github.Runner) error {
 	if err := sanitizeRunnerMustRunningTime(runner); errors.Is(err, ErrNotWillDeleteRunner) {
-		logger.Logf(false, "%s is not running MustRunningTime", runner.UUID)
+		logger.Info(ctx, "runner is not running MustRunningTime")
 		return nil
 	}
 	var mode TemporaryMode
-	if strings.EqualFold(m.runnerVersion, "latest") {
+	if runnerVersion := m.getRunnerVersion(); strings.EqualFold(runnerVersion, "latest") {
 		mode = TemporaryEphemeral
 	} else {
-		_, m, err := GetRunnerTemporaryMode(m.runnerVersion)
+		_, m, err := GetRunnerTemporaryMode(runnerVersion)
 		if err != nil {
 			return fmt.Errorf("failed to get runner mode: %w", err)
 		}
@@ -208,6 +259,8 @@ var (
 	StatusWillDelete = "offline"
 	// StatusSleep is sleeping runners
 	StatusSleep = "online"
+	// StatusManual is runners that deleted by admin
+	StatusManual = "manual"
 )
 
 func sanitizeGitHubRunner(ghRunner github.Runner, dsRunner datastore.Runner) error {
@@ -218,14 +271,16 @@ func sanitizeGitHubRunner(ghRunner github.Runner, dsRunner datastore.Runner) err
 
 	switch ghRunner.GetStatus() {
 	case StatusWillDelete:
-		if err := sanitizeRunner(dsRunner, MustRunningTime); err != nil {
-			logger.Logf(false, "%s is offline and not running %s, so not will delete (created_at: %s, now: %s)", dsRunner.UUID, MustRunningTime, dsRunner.CreatedAt, time.Now().UTC())
+		mustRunningTime := GetMustRunningTime()
+		if err := sanitizeRunner(dsRunner, mustRunningTime); err != nil {
+			logger.Logf(false, "%s is offline and not running %s, so not will delete (created_at: %s, now: %s)", dsRunner.UUID, mustRunningTime, dsRunner.CreatedAt, time.Now().UTC())
 			return fmt.Errorf("failed to sanitize will delete runner: %w", err)
 		}
 		return nil
 	case StatusSleep:
-		if err := sanitizeRunner(dsRunner, MustGoalTime); err != nil {
-			logger.Logf(false, "%s is idle and not running %s, so not will delete (created_at: %s, now: %s)", dsRunner.UUID, MustGoalTime, dsRunner.CreatedAt, time.Now().UTC())
+		mustGoalTime := GetMustGoalTime()
+		if err := sanitizeRunner(dsRunner, mustGoalTime); err != nil {
+			logger.Logf(false, "%s is idle and not running %s, so not will delete (created_at: %s, now: %s)", dsRunner.UUID, mustGoalTime, dsRunner.CreatedAt, time.Now().UTC())
 			return fmt.Errorf("failed to sanitize idle runner: %w", err)
 		}
 		return nil
@@ -235,7 +290,7 @@ func sanitizeGitHubRunner(ghRunner github.Runner, dsRunner datastore.Runner) err
 }
 
 func sanitizeRunnerMustRunningTime(runner datastore.Runner) error {
-	return sanitizeRunner(runner, MustRunningTime)
+	return sanitizeRunner(runner, GetMustRunningTime())
 }
 
 func sanitizeRunner(runner datastore.Runner, needTime time.Duration) error {
@@ -251,7 +306,7 @@ func sanitizeRunner(runner datastore.Runner, needTime time.Duration) error {
 // deleteRunnerWithGitHub delete runner in github, shoes, datastore.
 // runnerUUID is uuid in datastore, runnerID is id from GitHub.
 func (m *Manager) deleteRunnerWithGitHub(ctx context.Context, githubClient *github.Client, runner datastore.Runner, runnerID int64, owner, repo, runnerStatus string) error {
-	logger.Logf(false, "will delete runner with GitHub: %s", runner.UUID.String())
+	logger.Info(ctx, "will delete runner with GitHub", "runner_status", runnerStatus)
 	isOrg := false
 	if repo == "" {
 		isOrg = true
@@ -275,7 +330,7 @@ func (m *Manager) deleteRunnerWithGitHub(ctx context.Context, githubClient *gith
 
 // deleteRunner delete runner in shoes, datastore.
 func (m *Manager) deleteRunner(ctx context.Context, runner datastore.Runner, runnerStatus string) error {
-	logger.Logf(false, "will delete runner: %s", runner.UUID.String())
+	logger.Info(ctx, "will delete runner", "runner_status", runnerStatus)
 
 	client, teardown, err := shoes.GetClient()
 	if err != nil {
@@ -290,7 +345,7 @@ func (m *Manager) deleteRunner(ctx context.Context, runner datastore.Runner, run
 
 	if err := client.DeleteInstance(ctx, runner.CloudID, labels); err != nil {
 		if status.Code(errors.Unwrap(err)) == codes.NotFound {
-			logger.Logf(true, "%s is not found, will ignore from shoes", runner.UUID)
+			logger.Debug(ctx, "instance is not found, will ignore from shoes", "cloud_id", runner.CloudID)
 		} else {
 			return fmt.Errorf("failed to delete instance: %w", err)
 		}
@@ -300,6 +355,7 @@ func (m *Manager) deleteRunner(ctx context.Context, runner datastore.Runner, run
 	if err := m.ds.DeleteRunner(ctx, runner.UUID, now, ToReason(runnerStatus)); err != nil {
 		return fmt.Errorf("failed to remove runner from datastore (runner uuid: %s): %+v", runner.UUID.String(), err)
 	}
+	recordUsage(ctx, m.ds, runner, now)
 
 	return nil
 }
@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/gh"
+	"github.com/whywaita/myshoes/pkg/logger"
+)
+
+// DeleteRunnerManually delete a runner by admin regardless of status in GitHub.
+// a runner is removed from GitHub if registered, and deleted in shoes, datastore.
+func (m *Manager) DeleteRunnerManually(ctx context.Context, runner datastore.Runner) error {
+	t, err := m.ds.GetTarget(ctx, runner.TargetID)
+	if err != nil {
+		return fmt.Errorf("failed to get target (target ID: %s): %w", runner.TargetID, err)
+	}
+
+	token, err := IssueToken(ctx, m.ds, *t)
+	if err != nil {
+		return fmt.Errorf("failed to issue token: %w", err)
+	}
+
+	owner, repo := t.OwnerRepo()
+	client, err := gh.NewClient(token)
+	if err != nil {
+		return fmt.Errorf("failed to create github client: %w", err)
+	}
+	ghRunners, err := gh.ListRunners(ctx, client, owner, repo)
+	if err != nil {
+		return fmt.Errorf("failed to get list of runner in GitHub: %w", err)
+	}
+
+	ghRunner, err := gh.ExistGitHubRunnerWithRunner(ghRunners, ToName(runner.UUID.String()))
+	switch {
+	case errors.Is(err, gh.ErrNotFound):
+		logger.Logf(false, "%s is not registered in GitHub, will delete only instance", runner.UUID)
+		if err := m.deleteRunner(ctx, runner, StatusManual); err != nil {
+			return fmt.Errorf("failed to delete runner: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to check runner exist in GitHub (runner: %s): %w", runner.UUID, err)
+	default:
+		if err := m.deleteRunnerWithGitHub(ctx, client, runner, ghRunner.GetID(), owner, repo, StatusManual); err != nil {
+			return fmt.Errorf("failed to delete runner with GitHub: %w", err)
+		}
+	}
+
+	DeleteRetryCount.Delete(runner.UUID)
+	return nil
+}
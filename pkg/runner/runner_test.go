@@ -0,0 +1,21 @@
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/whywaita/myshoes/pkg/config"
+)
+
+func TestGetMustRunningTime(t *testing.T) {
+	if got, want := GetMustRunningTime(), time.Duration(config.GetRuntime().MustRunningTime); got != want {
+		t.Fatalf("want value of runtime config %s, but got %s", want, got)
+	}
+
+	old := MustRunningTime
+	t.Cleanup(func() { MustRunningTime = old })
+	MustRunningTime = time.Second
+	if got := GetMustRunningTime(); got != time.Second {
+		t.Fatalf("changed MustRunningTime must be used, but got %s", got)
+	}
+}
@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/gh"
+	"github.com/whywaita/myshoes/pkg/logger"
+	"github.com/whywaita/myshoes/pkg/shoes"
+)
+
+// DescriptionRecovered is status description of target that recovered by health check
+const DescriptionRecovered = "recovered by health check"
+
+// function pointer (for testing)
+var (
+	GHIsInstalledGitHubAppFunc = gh.IsInstalledGitHubApp
+	GHGenerateTokenFunc        = generateToken
+	ProbeShoesFunc             = probeShoes
+)
+
+// Descriptions of failed health check
+const (
+	ErrDescriptionNotInstalled      = "health check failed: GitHub App is not installed"
+	ErrDescriptionCanNotIssueToken  = "health check failed: can not issue token"
+	ErrDescriptionPluginUnreachable = "health check failed: shoes-provider is not reachable"
+)
+
+// doTargetHealth probe causes of failure in targets that status is error, and recover targets if healthy
+func (m *Manager) doTargetHealth(ctx context.Context) error {
+	logger.Logf(true, "start health check of targets")
+
+	targets, err := m.ds.ListTargets(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get targets: %w", err)
+	}
+
+	// shoes-provider is common in all targets, check once
+	var pluginErr error
+	pluginChecked := false
+	for _, t := range targets {
+		if !needHealthCheck(t) {
+			continue
+		}
+		if !pluginChecked {
+			pluginErr = ProbeShoesFunc(ctx)
+			pluginChecked = true
+		}
+
+		description := DescriptionRecovered
+		if err := m.probeTarget(ctx, t); err != nil {
+			logger.Logf(false, "target is still unhealthy (target: %s): %+v", t.UUID, err)
+			description = err.Error()
+		} else if pluginErr != nil {
+			logger.Logf(false, "target is still unhealthy (target: %s): %+v", t.UUID, pluginErr)
+			description = ErrDescriptionPluginUnreachable
+		}
+
+		status := datastore.TargetStatus(datastore.TargetStatusErr)
+		if description == DescriptionRecovered {
+			status = datastore.TargetStatusActive
+			logger.Logf(false, "target is healthy, recover status to active (target: %s, previous: %s)", t.UUID, t.StatusDescription.String)
+		}
+		if err := datastore.UpdateTargetStatus(ctx, m.ds, t.UUID, status, description); err != nil {
+			logger.Logf(false, "failed to update target status (target ID: %s): %+v\n", t.UUID, err)
+		}
+	}
+
+	return nil
+}
+
+// needHealthCheck return true if target is in error that can be recovered by health check
+func needHealthCheck(t datastore.Target) bool {
+	if t.Status != datastore.TargetStatusErr {
+		return false
+	}
+	// this error is recovered by runner manager when runner for queueing is created
+	return !strings.EqualFold(t.StatusDescription.String, ErrDescriptionRunnerForQueueingIsNotFound)
+}
+
+// probeTarget check GitHub App installation and token of target, refresh token if succeeded
+func (m *Manager) probeTarget(ctx context.Context, t datastore.Target) error {
+	installationID, err := GHIsInstalledGitHubAppFunc(ctx, t.Scope)
+	if err != nil {
+		logger.Logf(false, "failed to get installation (target: %s): %+v", t.UUID, err)
+		return errors.New(ErrDescriptionNotInstalled)
+	}
+
+	token, expiredAt, err := GHGenerateTokenFunc(ctx, installationID, t.Scope)
+	if err != nil {
+		logger.Logf(false, "failed to issue token (target: %s): %+v", t.UUID, err)
+		return errors.New(ErrDescriptionCanNotIssueToken)
+	}
+	if err := m.ds.UpdateToken(ctx, t.UUID, token, *expiredAt); err != nil {
+		logger.Logf(false, "failed to update token (target: %s): %+v", t.UUID, err)
+		return errors.New(ErrDescriptionCanNotIssueToken)
+	}
+
+	return nil
+}
+
+func generateToken(ctx context.Context, installationID int64, scope string) (string, *time.Time, error) {
+	clientApps, err := gh.NewClientGitHubApps()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create a client from Apps: %w", err)
+	}
+	return gh.GenerateGitHubAppsToken(ctx, clientApps, installationID, scope)
+}
+
+// probeShoes check shoes-provider plugin can be started
+func probeShoes(ctx context.Context) error {
+	_, teardown, err := shoes.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to get plugin client: %w", err)
+	}
+	teardown()
+	return nil
+}
@@ -0,0 +1,131 @@
+package runner
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/datastore/memory"
+)
+
+func TestManager_doTargetHealth(t *testing.T) {
+	oldInstalled, oldToken, oldProbe := GHIsInstalledGitHubAppFunc, GHGenerateTokenFunc, ProbeShoesFunc
+	t.Cleanup(func() {
+		GHIsInstalledGitHubAppFunc, GHGenerateTokenFunc, ProbeShoesFunc = oldInstalled, oldToken, oldProbe
+	})
+
+	const initialDescription = "failed to create an instance"
+	errTest := errors.New("error for test")
+
+	tests := []struct {
+		name        string
+		description string
+		installErr  error
+		tokenErr    error
+		probeErr    error
+		wantStatus  datastore.TargetStatus
+		wantDesc    string
+		wantHistory bool // new history is recorded
+	}{
+		{
+			name:        "recovered",
+			description: initialDescription,
+			wantStatus:  datastore.TargetStatusActive,
+			wantDesc:    DescriptionRecovered,
+			wantHistory: true,
+		},
+		{
+			name:        "not installed",
+			description: initialDescription,
+			installErr:  errTest,
+			wantStatus:  datastore.TargetStatusErr,
+			wantDesc:    ErrDescriptionNotInstalled,
+			wantHistory: true,
+		},
+		{
+			name:        "can not issue token",
+			description: initialDescription,
+			tokenErr:    errTest,
+			wantStatus:  datastore.TargetStatusErr,
+			wantDesc:    ErrDescriptionCanNotIssueToken,
+			wantHistory: true,
+		},
+		{
+			name:        "plugin unreachable",
+			description: initialDescription,
+			probeErr:    errTest,
+			wantStatus:  datastore.TargetStatusErr,
+			wantDesc:    ErrDescriptionPluginUnreachable,
+			wantHistory: true,
+		},
+		{
+			name:        "runner for queueing is not found",
+			description: ErrDescriptionRunnerForQueueingIsNotFound,
+			wantStatus:  datastore.TargetStatusErr,
+			wantDesc:    ErrDescriptionRunnerForQueueingIsNotFound,
+			wantHistory: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			ds, err := memory.New()
+			if err != nil {
+				t.Fatalf("failed to create datastore: %+v", err)
+			}
+			targetID := uuid.NewV4()
+			if err := ds.CreateTarget(ctx, datastore.Target{
+				UUID:              targetID,
+				Scope:             "octocat",
+				Status:            datastore.TargetStatusErr,
+				StatusDescription: sql.NullString{String: test.description, Valid: true},
+			}); err != nil {
+				t.Fatalf("failed to create target: %+v", err)
+			}
+
+			GHIsInstalledGitHubAppFunc = func(ctx context.Context, scope string) (int64, error) {
+				return 1, test.installErr
+			}
+			GHGenerateTokenFunc = func(ctx context.Context, installationID int64, scope string) (string, *time.Time, error) {
+				expiredAt := time.Now().Add(time.Hour)
+				return "token", &expiredAt, test.tokenErr
+			}
+			ProbeShoesFunc = func(ctx context.Context) error {
+				return test.probeErr
+			}
+
+			m := New(ds, "")
+			if err := m.doTargetHealth(ctx); err != nil {
+				t.Fatalf("failed to check health of targets: %+v", err)
+			}
+
+			got, err := ds.GetTarget(ctx, targetID)
+			if err != nil {
+				t.Fatalf("failed to get target: %+v", err)
+			}
+			if got.Status != test.wantStatus || got.StatusDescription.String != test.wantDesc {
+				t.Errorf("want status %s (%s), but got %s (%s)", test.wantStatus, test.wantDesc, got.Status, got.StatusDescription.String)
+			}
+
+			histories, err := ds.ListTargetStatusHistory(ctx, targetID, 0, 0)
+			if err != nil {
+				t.Fatalf("failed to list status history: %+v", err)
+			}
+			if !test.wantHistory {
+				if len(histories) != 1 {
+					t.Errorf("status history must not be recorded, but got %+v", histories)
+				}
+				return
+			}
+			if len(histories) != 2 || histories[0].Status != test.wantStatus || histories[0].Description != test.wantDesc {
+				t.Errorf("want history %s (%s), but got %+v", test.wantStatus, test.wantDesc, histories)
+			}
+		})
+	}
+}
@@ -2,14 +2,17 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/whywaita/myshoes/pkg/datastore"
-	"github.com/whywaita/myshoes/pkg/gh"
 	"github.com/whywaita/myshoes/pkg/logger"
 )
 
+// errUpdateToken is error that issued token can not be stored to datastore
+var errUpdateToken = errors.New("can not update token")
+
 func (m *Manager) doTargetToken(ctx context.Context) error {
 	logger.Logf(true, "start refresh token")
 
@@ -28,31 +31,32 @@ func (m *Manager) doTargetToken(ctx context.Context) error {
 		// do refresh
 		logger.Logf(true, "%s need to update GitHub token, will be update", target.UUID)
 
-		clientApps, err := gh.NewClientGitHubApps()
-		if err != nil {
-			logger.Logf(false, "failed to create a client from Apps: %+v", err)
-			continue
-		}
-		installationID, err := gh.IsInstalledGitHubApp(ctx, target.Scope)
-		if err != nil {
-			logger.Logf(false, "failed to get installationID: %+v", err)
-			continue
-		}
-		// TODO: replace to ghinstallation.AppTransport
-		token, expiredAt, err := gh.GenerateGitHubAppsToken(ctx, clientApps, installationID, target.Scope)
-		if err != nil {
-			logger.Logf(false, "failed to get Apps Token: %+v", err)
-			continue
-		}
-
-		if err := m.ds.UpdateToken(ctx, target.UUID, token, *expiredAt); err != nil {
-			logger.Logf(false, "failed to update token (target: %s): %+v", target.UUID, err)
-			if err := datastore.UpdateTargetStatus(ctx, m.ds, target.UUID, datastore.TargetStatusErr, "can not update token"); err != nil {
-				logger.Logf(false, "failed to update target status (target ID: %s): %+v\n", target.UUID, err)
+		if _, err := IssueToken(ctx, m.ds, target); err != nil {
+			logger.Logf(false, "failed to refresh token (target: %s): %+v", target.UUID, err)
+			if errors.Is(err, errUpdateToken) {
+				if err := datastore.UpdateTargetStatus(ctx, m.ds, target.UUID, datastore.TargetStatusErr, "can not update token"); err != nil {
+					logger.Logf(false, "failed to update target status (target ID: %s): %+v\n", target.UUID, err)
+				}
 			}
-			continue
 		}
 	}
 
 	return nil
 }
+
+// IssueToken issue a fresh installation token of target and store it to datastore.
+// stored token may be expired if token refresh has not run yet (e.g. called from API).
+func IssueToken(ctx context.Context, ds datastore.Datastore, t datastore.Target) (string, error) {
+	installationID, err := GHIsInstalledGitHubAppFunc(ctx, t.Scope)
+	if err != nil {
+		return "", fmt.Errorf("failed to get installationID: %w", err)
+	}
+	token, expiredAt, err := GHGenerateTokenFunc(ctx, installationID, t.Scope)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Apps Token: %w", err)
+	}
+	if err := ds.UpdateToken(ctx, t.UUID, token, *expiredAt); err != nil {
+		return "", fmt.Errorf("failed to update token (target: %s): %v: %w", t.UUID, err, errUpdateToken)
+	}
+	return token, nil
+}
@@ -28,6 +28,8 @@ func ToReason(status string) datastore.RunnerStatus {
 	case StatusSleep:
 		// is idle, reach hard limit
 		return datastore.RunnerStatusReachHardLimit
+	case StatusManual:
+		return datastore.RunnerStatusManual
 	}
 
 	return ""
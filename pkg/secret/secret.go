@@ -0,0 +1,52 @@
+// Package secret provides stores of secret values
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound is error for secret that is not found in store
+var ErrNotFound = errors.New("secret is not found")
+
+// Provider is a store of secret values
+type Provider interface {
+	// Get return value of secret, return ErrNotFound if secret is not exist
+	Get(ctx context.Context, name string) (string, error)
+}
+
+// Env is Provider that read environment values, name is converted to upper case
+type Env struct{}
+
+// Get return value of environment
+func (Env) Get(ctx context.Context, name string) (string, error) {
+	v, ok := os.LookupEnv(strings.ToUpper(name))
+	if !ok {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
+
+// File is Provider that read files in Directory, name is file name (e.g. Kubernetes Secret mounted as volume)
+type File struct {
+	Directory string
+}
+
+// Get return content of file, trailing newline is trimmed
+func (f File) Get(ctx context.Context, name string) (string, error) {
+	if strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid secret name: %s", name)
+	}
+	b, err := os.ReadFile(filepath.Join(f.Directory, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
@@ -0,0 +1,105 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Vault is Provider that read a secret in KV secrets engine version 2 of HashiCorp Vault compatible HTTP API.
+// name is key in data of the secret.
+type Vault struct {
+	Address   string // e.g. https://vault.example.com:8200
+	Token     string
+	MountPath string // e.g. secret
+	Path      string // path of secret in mount, e.g. myshoes
+
+	HTTPClient *http.Client
+
+	mu   sync.Mutex
+	data map[string]interface{} // cache of secret data in a Load
+}
+
+// vaultKVResponse is response of GET /v1/:mount/data/:path
+type vaultKVResponse struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+}
+
+// Get return value in the secret, the secret is fetched at first call and cached until Reset
+func (v *Vault) Get(ctx context.Context, name string) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.data == nil {
+		data, err := v.fetch(ctx)
+		if err != nil {
+			return "", err
+		}
+		v.data = data
+	}
+
+	value, ok := v.data[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("value of %s is not string", name)
+	}
+	return s, nil
+}
+
+// Reset clear cache, the secret is fetched again at next Get
+func (v *Vault) Reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.data = nil
+}
+
+func (v *Vault) fetch(ctx context.Context) (map[string]interface{}, error) {
+	u, err := url.Parse(v.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse address of vault: %w", err)
+	}
+	u = u.JoinPath("v1", strings.Trim(v.MountPath, "/"), "data", strings.Trim(v.Path, "/"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", v.Token)
+
+	client := v.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request to vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		// not ErrNotFound, a wrong path must not be fallen back to other providers silently
+		return nil, fmt.Errorf("path of secret is not found in vault (path: %s/%s)", v.MountPath, v.Path)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to get secret from vault (status code: %d)", resp.StatusCode)
+	}
+
+	var kv vaultKVResponse
+	if err := json.NewDecoder(resp.Body).Decode(&kv); err != nil {
+		return nil, fmt.Errorf("failed to decode response of vault: %w", err)
+	}
+	if kv.Data.Data == nil {
+		return map[string]interface{}{}, nil
+	}
+	return kv.Data.Data, nil
+}
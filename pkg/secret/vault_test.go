@@ -0,0 +1,55 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVault_Get(t *testing.T) {
+	requested := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested++
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/secret/data/myshoes" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"data":{"data":{"github_app_secret":"secret","number":1}}}`))
+	}))
+	defer ts.Close()
+
+	v := &Vault{Address: ts.URL, Token: "token", MountPath: "secret", Path: "myshoes"}
+	got, err := v.Get(context.Background(), "github_app_secret")
+	if err != nil {
+		t.Fatalf("failed to get secret: %+v", err)
+	}
+	if got != "secret" {
+		t.Errorf("github_app_secret must be secret, but got %s", got)
+	}
+	if _, err := v.Get(context.Background(), "not_found"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("not_found must return ErrNotFound, but got %+v", err)
+	}
+	if _, err := v.Get(context.Background(), "number"); err == nil {
+		t.Errorf("number is not string, must return error")
+	}
+	if requested != 1 {
+		t.Errorf("secret must be fetched once until Reset, but requested %d times", requested)
+	}
+
+	v.Reset()
+	v.Path = "unknown"
+	if _, err := v.Get(context.Background(), "github_app_secret"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown path must return error that is not ErrNotFound, but got %+v", err)
+	}
+
+	v = &Vault{Address: ts.URL, Token: "invalid", MountPath: "secret", Path: "myshoes"}
+	if _, err := v.Get(context.Background(), "github_app_secret"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("invalid token must return error that is not ErrNotFound, but got %+v", err)
+	}
+}
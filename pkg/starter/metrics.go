@@ -1,8 +1,15 @@
 package starter
 
 import (
+	"context"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/internal/util"
+	"github.com/whywaita/myshoes/pkg/datastore"
 )
 
 var (
@@ -13,13 +20,72 @@ var (
 		Name:      "add_instance_backoff_duration_seconds",
 		Help:      "Histogram of exponential backoff duration in seconds for adding instance",
 		Buckets:   prometheus.ExponentialBuckets(1, 2, 10), // 1s, 2s, 4s, 8s, 16s, 32s, 64s, 128s, 256s, 512s
-	}, []string{"job_uuid"})
+	}, []string{"target", "resource_type"})
 
 	// AddInstanceRetryTotal is counter of total retries for adding instance
 	AddInstanceRetryTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "myshoes",
 		Subsystem: "starter",
 		Name:      "add_instance_retry_total",
-		Help:      "Total number of retries for adding instance",
-	}, []string{"job_uuid"})
+		Help:      "Total number of failures of adding instance that will be retried",
+	}, []string{"target", "resource_type", "error_class"})
+
+	// JobQueueDuration is histogram of duration from webhook received to job dequeued
+	JobQueueDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "myshoes",
+		Subsystem: "starter",
+		Name:      "job_queue_duration_seconds",
+		Help:      "Histogram of duration in seconds from webhook received to job dequeued",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 12), // 0.5s ... 1024s
+	}, []string{"target", "resource_type"})
+
+	// AddInstanceDuration is histogram of duration of AddInstance in shoes-provider
+	AddInstanceDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "myshoes",
+		Subsystem: "starter",
+		Name:      "add_instance_duration_seconds",
+		Help:      "Histogram of duration in seconds of AddInstance that succeeded in shoes-provider",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 12), // 0.5s ... 1024s
+	}, []string{"target", "resource_type"})
 )
+
+// jobMetricLabels return target and resource_type labels of job
+func (s *Starter) jobMetricLabels(ctx context.Context, job datastore.Job) (string, string) {
+	target, err := s.ds.GetTarget(ctx, job.TargetID)
+	if err != nil {
+		return job.TargetID.String(), datastore.ResourceTypeUnknown.String()
+	}
+	return target.Scope, target.ResourceType.String()
+}
+
+// observeRetry count a failure of adding instance, job ID is attached as exemplar
+func observeRetry(scope, resourceType string, jobID uuid.UUID, err error) {
+	counter := AddInstanceRetryTotal.WithLabelValues(scope, resourceType, util.ErrorClass(err))
+	if adder, ok := counter.(prometheus.ExemplarAdder); ok {
+		adder.AddWithExemplar(1, prometheus.Labels{"job_id": jobID.String()})
+		return
+	}
+	counter.Inc()
+}
+
+// observeBackoff observe backoff duration before retrying, job ID is attached as exemplar
+func observeBackoff(scope, resourceType string, jobID uuid.UUID, sleep time.Duration) {
+	observer := AddInstanceBackoffDuration.WithLabelValues(scope, resourceType)
+	if eo, ok := observer.(prometheus.ExemplarObserver); ok {
+		eo.ObserveWithExemplar(sleep.Seconds(), prometheus.Labels{"job_id": jobID.String()})
+		return
+	}
+	observer.Observe(sleep.Seconds())
+}
+
+// DeleteTargetMetrics delete series of target, it is called when target is deleted
+func DeleteTargetMetrics(scope string) {
+	for _, vec := range []*prometheus.MetricVec{
+		AddInstanceBackoffDuration.MetricVec,
+		AddInstanceRetryTotal.MetricVec,
+		JobQueueDuration.MetricVec,
+		AddInstanceDuration.MetricVec,
+	} {
+		vec.DeletePartialMatch(prometheus.Labels{"target": scope})
+	}
+}
@@ -0,0 +1,66 @@
+package starter
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	uuid "github.com/satori/go.uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+func TestObserveRetry(t *testing.T) {
+	AddInstanceRetryTotal.Reset()
+	AddInstanceBackoffDuration.Reset()
+
+	err := fmt.Errorf("failed to add instance: %w", status.Error(codes.Unavailable, "unavailable"))
+	for i := 0; i < 10; i++ {
+		jobID := uuid.NewV4()
+		observeRetry("octocat", "nano", jobID, err)
+		observeBackoff("octocat", "nano", jobID, 2*time.Second)
+	}
+
+	// series must not be increased by jobs
+	if got := testutil.CollectAndCount(AddInstanceRetryTotal); got != 1 {
+		t.Errorf("must be 1 series, but got %d", got)
+	}
+	if got := testutil.ToFloat64(AddInstanceRetryTotal.WithLabelValues("octocat", "nano", "shoes_provider")); got != 10 {
+		t.Errorf("must be 10 retries, but got %v", got)
+	}
+
+	DeleteTargetMetrics("octocat")
+	if got := testutil.CollectAndCount(AddInstanceRetryTotal); got != 0 {
+		t.Errorf("series of deleted target must be deleted, but got %d", got)
+	}
+	if got := testutil.CollectAndCount(AddInstanceBackoffDuration); got != 0 {
+		t.Errorf("series of deleted target must be deleted, but got %d", got)
+	}
+}
+
+func TestPruneRetryCount(t *testing.T) {
+	queued := uuid.NewV4()
+	finished := uuid.NewV4()
+	processing := uuid.NewV4()
+	for _, id := range []uuid.UUID{queued, finished, processing} {
+		AddInstanceRetryCount.Store(id, 1)
+	}
+	inProgress.Store(processing, struct{}{})
+	defer func() {
+		inProgress.Delete(processing)
+		for _, id := range []uuid.UUID{queued, finished, processing} {
+			AddInstanceRetryCount.Delete(id)
+		}
+	}()
+
+	pruneRetryCount([]datastore.Job{{UUID: queued}})
+
+	for id, want := range map[uuid.UUID]int{queued: 1, finished: 0, processing: 1} {
+		if got := GetRetryCount(id); got != want {
+			t.Errorf("retry count of %s must be %d, but got %d", id, want, got)
+		}
+	}
+}
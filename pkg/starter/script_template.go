@@ -0,0 +1,227 @@
+package starter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/gh"
+	"github.com/whywaita/myshoes/pkg/logger"
+)
+
+// scriptTemplateFuncs is functions that can use in script templates
+var scriptTemplateFuncs = template.FuncMap{
+	"quote":   shellQuote,
+	"psquote": powerShellQuote,
+}
+
+// shellQuote quote a string for bash
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// powerShellQuote quote a string for PowerShell
+func powerShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func newScriptTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(scriptTemplateFuncs).Option("missingkey=error").Parse(text)
+}
+
+// GetScriptTemplate get a script template from datastore or script template directory.
+// a template in datastore has priority.
+func GetScriptTemplate(ctx context.Context, ds datastore.Datastore, name string) (*datastore.ScriptTemplate, error) {
+	if ds != nil {
+		st, err := ds.GetScriptTemplate(ctx, name)
+		if err == nil {
+			return st, nil
+		} else if !errors.Is(err, datastore.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get script template from datastore: %w", err)
+		}
+	}
+
+	for _, st := range directoryScriptTemplates() {
+		if st.Name == name {
+			return &st, nil
+		}
+	}
+
+	return nil, datastore.ErrNotFound
+}
+
+// ListScriptTemplates get all script templates from datastore and script template directory
+func ListScriptTemplates(ctx context.Context, ds datastore.Datastore) ([]datastore.ScriptTemplate, error) {
+	sts, err := ds.ListScriptTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get script templates from datastore: %w", err)
+	}
+
+	stored := map[string]struct{}{}
+	for _, st := range sts {
+		stored[st.Name] = struct{}{}
+	}
+
+	for _, st := range directoryScriptTemplates() {
+		if _, ok := stored[st.Name]; ok {
+			continue
+		}
+		sts = append(sts, st)
+	}
+
+	sort.SliceStable(sts, func(i, j int) bool {
+		return sts[i].Name < sts[j].Name
+	})
+	return sts, nil
+}
+
+var (
+	directoryTemplatesMu sync.RWMutex
+	// directoryTemplates is script templates that loaded from script template directory
+	directoryTemplates []datastore.ScriptTemplate
+)
+
+// directoryScriptTemplates return script templates that loaded by LoadScriptTemplateDirectory
+func directoryScriptTemplates() []datastore.ScriptTemplate {
+	directoryTemplatesMu.RLock()
+	defer directoryTemplatesMu.RUnlock()
+	return directoryTemplates
+}
+
+// LoadScriptTemplateDirectory load script templates from script template directory, it is called at startup and SIGHUP.
+// invalid files are skipped, so these do not affect other templates.
+func LoadScriptTemplateDirectory() error {
+	sts, err := loadScriptTemplatesFromDirectory(config.Config.ScriptTemplateDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to load script templates from directory: %w", err)
+	}
+
+	directoryTemplatesMu.Lock()
+	defer directoryTemplatesMu.Unlock()
+	directoryTemplates = sts
+	return nil
+}
+
+// loadScriptTemplatesFromDirectory load script templates from JSON files in dir.
+// file name without extension is used as name if name is not set in file.
+func loadScriptTemplatesFromDirectory(dir string) ([]datastore.ScriptTemplate, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find script template files: %w", err)
+	}
+
+	var sts []datastore.ScriptTemplate
+	for _, p := range paths {
+		st, err := loadScriptTemplateFile(p)
+		if err != nil {
+			logger.Logf(false, "failed to load script template, will skip (path: %s): %+v", p, err)
+			continue
+		}
+		sts = append(sts, *st)
+	}
+
+	return sts, nil
+}
+
+func loadScriptTemplateFile(p string) (*datastore.ScriptTemplate, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	st, err := DecodeScriptTemplate(f)
+	if err != nil {
+		return nil, err
+	}
+	if st.Name == "" {
+		st.Name = strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+	}
+
+	if err := ValidateScriptTemplate(*st); err != nil {
+		return nil, fmt.Errorf("invalid script template: %w", err)
+	}
+	return st, nil
+}
+
+// DecodeScriptTemplate decode a script template from JSON
+func DecodeScriptTemplate(r io.Reader) (*datastore.ScriptTemplate, error) {
+	var st datastore.ScriptTemplate
+	d := json.NewDecoder(r)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&st); err != nil {
+		return nil, fmt.Errorf("failed to decode script template: %w", err)
+	}
+
+	return &st, nil
+}
+
+// ValidateScriptTemplate check values and syntax of script template.
+// templates are executed with dummy values, so it can detect unknown values.
+func ValidateScriptTemplate(st datastore.ScriptTemplate) error {
+	if err := st.Validate(); err != nil {
+		return err
+	}
+
+	v := templateCreateLatestRunnerOnceValue{
+		Scope:                   "octocat/hello-world",
+		GHEDomain:               "https://github.com",
+		RunnerRegistrationToken: "dummy-token",
+		RunnerName:              "myshoes-dummy",
+		RunnerUser:              "runner",
+		RunnerVersion:           "v2.300.0",
+		RunnerArg:               "--ephemeral",
+		RunnerBaseDirectory:     "/tmp",
+		RunnerArch:              string(datastore.RunnerArchX64),
+		RunnerDownloadBaseURL:   gh.RunnerReleaseDownloadURL,
+		TemplateName:            st.Name,
+		Environment:             st.Environment,
+	}
+
+	for _, t := range []struct {
+		name string
+		text string
+	}{
+		{name: "pre_install", text: st.PreInstall},
+		{name: "post_install", text: st.PostInstall},
+		{name: "script", text: st.Script},
+	} {
+		if _, err := renderScriptTemplate(t.name, t.text, v); err != nil {
+			return fmt.Errorf("invalid %s: %w", t.name, err)
+		}
+	}
+
+	return nil
+}
+
+// renderScriptTemplate render a template text with values of setup script
+func renderScriptTemplate(name, text string, v templateCreateLatestRunnerOnceValue) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+
+	t, err := newScriptTemplate(name, text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+	var buff bytes.Buffer
+	if err := t.Execute(&buff, v); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	return buff.String(), nil
+}
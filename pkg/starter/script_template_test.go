@@ -0,0 +1,145 @@
+package starter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+func TestValidateScriptTemplate(t *testing.T) {
+	tests := []struct {
+		input datastore.ScriptTemplate
+		err   bool
+	}{
+		{
+			input: datastore.ScriptTemplate{
+				Name:        "gpu",
+				PreInstall:  "echo {{ quote .RunnerName }}",
+				PostInstall: "echo {{ index .Environment \"CUDA_VISIBLE_DEVICES\" }}",
+				Environment: datastore.ScriptEnvironment{"CUDA_VISIBLE_DEVICES": "0"},
+				Labels:      datastore.ScriptLabels{"gpu"},
+			},
+			err: false,
+		},
+		{
+			input: datastore.ScriptTemplate{
+				Name:   "full",
+				Script: templateCreateLatestRunnerOnce,
+			},
+			err: false,
+		},
+		{
+			input: datastore.ScriptTemplate{
+				Name: "invalid name",
+			},
+			err: true,
+		},
+		{
+			input: datastore.ScriptTemplate{
+				Name:       "unknown-value",
+				PreInstall: "echo {{.Unknown}}",
+			},
+			err: true,
+		},
+		{
+			input: datastore.ScriptTemplate{
+				Name:        "invalid-syntax",
+				PostInstall: "echo {{.RunnerName",
+			},
+			err: true,
+		},
+		{
+			input: datastore.ScriptTemplate{
+				Name:        "invalid-env",
+				Environment: datastore.ScriptEnvironment{"A-B": "c"},
+			},
+			err: true,
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateScriptTemplate(test.input)
+		if test.err != (err != nil) {
+			t.Fatalf("ValidateScriptTemplate(%s) want err: %t, but return err %+v", test.input.Name, test.err, err)
+		}
+	}
+}
+
+func TestRenderSetupScript(t *testing.T) {
+	st := datastore.ScriptTemplate{
+		Name:        "gpu",
+		PreInstall:  "echo pre {{.RunnerName}}",
+		PostInstall: "echo post {{.TemplateName}}",
+		Environment: datastore.ScriptEnvironment{"GREETING": "it's me"},
+	}
+	v := templateCreateLatestRunnerOnceValue{
+		RunnerName:   "myshoes-test",
+		RunnerArg:    "--ephemeral",
+		TemplateName: st.Name,
+		Environment:  st.Environment,
+	}
+
+	got, err := renderSetupScript(st, datastore.RunnerOSLinux, v)
+	if err != nil {
+		t.Fatalf("failed to render setup script: %+v", err)
+	}
+
+	for _, want := range []string{
+		`export GREETING='it'"'"'s me'`,
+		"echo pre myshoes-test",
+		"echo post gpu",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("setup script must contain %q", want)
+		}
+	}
+	if strings.Index(got, "echo pre") > strings.Index(got, "./config.sh") {
+		t.Errorf("pre_install must be before ./config.sh")
+	}
+	if strings.Index(got, "echo post") < strings.Index(got, "./config.sh") {
+		t.Errorf("post_install must be after ./config.sh")
+	}
+}
+
+func TestLoadScriptTemplateDirectory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"gpu.json":     `{"pre_install": "echo gpu"}`,
+		"broken.json":  `{"pre_install": `,
+		"unknown.json": `{"pre_install": "echo {{.Unknown}}"}`,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("failed to write file: %+v", err)
+		}
+	}
+
+	config.Config.ScriptTemplateDirectory = dir
+	t.Cleanup(func() {
+		config.Config.ScriptTemplateDirectory = ""
+		if err := LoadScriptTemplateDirectory(); err != nil {
+			t.Errorf("failed to reset script templates: %+v", err)
+		}
+	})
+	if err := LoadScriptTemplateDirectory(); err != nil {
+		t.Fatalf("failed to load script template directory: %+v", err)
+	}
+
+	sts := directoryScriptTemplates()
+	if len(sts) != 1 || sts[0].Name != "gpu" {
+		t.Fatalf("invalid files must be skipped, but got %+v", sts)
+	}
+
+	// files are not read again until next load
+	if err := os.Remove(filepath.Join(dir, "gpu.json")); err != nil {
+		t.Fatalf("failed to remove file: %+v", err)
+	}
+	if _, err := GetScriptTemplate(context.Background(), nil, "gpu"); err != nil {
+		t.Fatalf("failed to get script template: %+v", err)
+	}
+}
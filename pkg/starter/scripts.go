@@ -6,13 +6,18 @@ import (
 	"context"
 	_ "embed" // TODO:
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"strings"
 	"text/template"
 
 	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/datastore"
 	"github.com/whywaita/myshoes/pkg/gh"
+	"github.com/whywaita/myshoes/pkg/logger"
+	"github.com/whywaita/myshoes/pkg/mirror"
 	"github.com/whywaita/myshoes/pkg/runner"
+	"github.com/whywaita/myshoes/pkg/tracing"
 )
 
 //go:embed scripts/RunnerService.js
@@ -27,8 +32,17 @@ type templateCompressedScriptValue struct {
 	RunnerBaseDirectory string
 }
 
-func (s *Starter) GetSetupScript(ctx context.Context, targetScope, runnerName string) (string, error) {
-	rawScript, err := s.getSetupRawScript(ctx, targetScope, runnerName)
+// GetSetupScript create a setup script for target.
+// target.Scope is used as scope of runner.
+// runsOnLabels is used as labels of runner if target use just-in-time configuration.
+// return PowerShell script if runner OS is windows, otherwise bash script.
+func (s *Starter) GetSetupScript(ctx context.Context, target datastore.Target, runnerName string, runsOnLabels []string) (string, error) {
+	ctx, span := tracing.Start(ctx, "GetSetupScript", tracing.AttributeRunnerName.String(runnerName))
+	defer span.End()
+
+	runnerOS := getRunnerOS(target, runsOnLabels)
+	runnerArch := getRunnerArch(target, runsOnLabels)
+	rawScript, err := s.getSetupRawScript(ctx, target, runnerName, runsOnLabels, runnerOS, runnerArch)
 	if err != nil {
 		return "", fmt.Errorf("failed to get raw setup scripts: %w", err)
 	}
@@ -46,12 +60,20 @@ func (s *Starter) GetSetupScript(ctx context.Context, targetScope, runnerName st
 	}
 	encoded := base64.StdEncoding.EncodeToString(compressedScript.Bytes())
 
+	return renderCompressedScript(runnerOS, encoded)
+}
+
+func renderCompressedScript(runnerOS datastore.RunnerOS, encoded string) (string, error) {
 	v := templateCompressedScriptValue{
 		CompressedScript:    encoded,
-		RunnerBaseDirectory: config.Config.RunnerBaseDirectory,
+		RunnerBaseDirectory: getRunnerBaseDirectory(runnerOS),
 	}
 
-	t, err := template.New("templateCompressedScript").Parse(templateCompressedScript)
+	text := templateCompressedScript
+	if runnerOS == datastore.RunnerOSWindows {
+		text = templateCompressedScriptPowerShell
+	}
+	t, err := template.New("templateCompressedScript").Parse(text)
 	if err != nil {
 		return "", fmt.Errorf("failed to create template: %w", err)
 	}
@@ -62,12 +84,13 @@ func (s *Starter) GetSetupScript(ctx context.Context, targetScope, runnerName st
 	return buff.String(), nil
 }
 
-func (s *Starter) getSetupRawScript(ctx context.Context, targetScope, runnerName string) (string, error) {
+func (s *Starter) getSetupRawScript(ctx context.Context, target datastore.Target, runnerName string, runsOnLabels []string, runnerOS datastore.RunnerOS, runnerArch datastore.RunnerArch) (string, error) {
+	targetScope := target.Scope
 	runnerUser := config.Config.RunnerUser
 
-	targetRunnerVersion := s.runnerVersion
-	if strings.EqualFold(s.runnerVersion, "latest") {
-		latestVersion, err := gh.GetLatestRunnerVersion(ctx, targetScope)
+	targetRunnerVersion := s.getRunnerVersion()
+	if strings.EqualFold(targetRunnerVersion, "latest") {
+		latestVersion, err := getLatestRunnerVersion(ctx, targetScope, runnerOS, runnerArch)
 		if err != nil {
 			return "", fmt.Errorf("failed to get latest version of actions/runner: %w", err)
 		}
@@ -88,15 +111,42 @@ func (s *Starter) getSetupRawScript(ctx context.Context, targetScope, runnerName
 	if err != nil {
 		return "", fmt.Errorf("failed to get installlation id: %w", err)
 	}
-	token, err := gh.GetRunnerRegistrationToken(ctx, installationID, targetScope)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate runner register token: %w", err)
-	}
-
 	var labels []string
 	// The "dependabot" label is always added to ensure compatibility with Dependabot-related workflows.
 	labels = append(labels, "dependabot")
 
+	st := &datastore.ScriptTemplate{}
+	if target.ScriptTemplate.Valid {
+		st, err = GetScriptTemplate(ctx, s.ds, target.ScriptTemplate.String)
+		if err != nil {
+			return "", fmt.Errorf("failed to get script template (name: %s): %w", target.ScriptTemplate.String, err)
+		}
+		labels = append(labels, st.Labels...)
+	}
+
+	var jitConfig string
+	if target.UseJITConfig {
+		jitLabels := append([]string{"self-hosted", "myshoes"}, labels...)
+		jitLabels = append(jitLabels, runsOnLabels...)
+		c, err := getJITConfig(ctx, installationID, target, runnerName, jitLabels)
+		switch {
+		case errors.Is(err, gh.ErrJITConfigNotSupported):
+			logger.Logf(false, "just-in-time runner configuration is not supported, fallback to registration token (scope: %s)", targetScope)
+		case err != nil:
+			return "", fmt.Errorf("failed to get jit config: %w", err)
+		default:
+			jitConfig = c
+		}
+	}
+
+	var token string
+	if jitConfig == "" {
+		token, err = gh.GetRunnerRegistrationToken(ctx, installationID, targetScope)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate runner register token: %w", err)
+		}
+	}
+
 	v := templateCreateLatestRunnerOnceValue{
 		Scope:                   targetScope,
 		GHEDomain:               config.Config.GitHubURL,
@@ -107,18 +157,135 @@ func (s *Starter) getSetupRawScript(ctx context.Context, targetScope, runnerName
 		RunnerServiceJS:         runnerServiceJs,
 		RunnerArg:               runnerTemporaryMode.StringFlag(),
 		AdditionalLabels:        labelsToOneLine(labels),
-		RunnerBaseDirectory:     config.Config.RunnerBaseDirectory,
+		RunnerBaseDirectory:     getRunnerBaseDirectory(runnerOS),
+		RunnerArch:              string(runnerArch),
+		RunnerGroup:             getRunnerGroup(target),
+		RunnerJITConfig:         jitConfig,
+		RunnerDownloadBaseURL:   mirror.DownloadBaseURL(),
+		RunnerMirror:            mirror.Enabled(),
+		TemplateName:            st.Name,
+		Environment:             st.Environment,
 	}
 
-	t, err := template.New("templateCreateLatestRunnerOnce").Parse(templateCreateLatestRunnerOnce)
+	return renderSetupScript(*st, runnerOS, v)
+}
+
+// renderSetupScript render setup script with script template.
+// hooks in script template are rendered before main script, so main script can use it.
+func renderSetupScript(st datastore.ScriptTemplate, runnerOS datastore.RunnerOS, v templateCreateLatestRunnerOnceValue) (string, error) {
+	preInstall, err := renderScriptTemplate("pre_install", st.PreInstall, v)
 	if err != nil {
-		return "", fmt.Errorf("failed to create template")
+		return "", fmt.Errorf("failed to render pre_install: %w", err)
 	}
-	var buff bytes.Buffer
-	if err := t.Execute(&buff, v); err != nil {
-		return "", fmt.Errorf("failed to execute scripts: %w", err)
+	postInstall, err := renderScriptTemplate("post_install", st.PostInstall, v)
+	if err != nil {
+		return "", fmt.Errorf("failed to render post_install: %w", err)
 	}
-	return buff.String(), nil
+	v.PreInstall = preInstall
+	v.PostInstall = postInstall
+
+	text := templateCreateLatestRunnerOnce
+	if runnerOS == datastore.RunnerOSWindows {
+		text = templateCreateLatestRunnerOncePowerShell
+	}
+	if st.Script != "" {
+		text = st.Script
+	}
+	script, err := renderScriptTemplate("templateCreateLatestRunnerOnce", text, v)
+	if err != nil {
+		return "", fmt.Errorf("failed to render scripts: %w", err)
+	}
+	return script, nil
+}
+
+// getRunnerOS return operating system of runner.
+// "windows" or "linux" in runs-on labels has priority over target.
+func getRunnerOS(target datastore.Target, runsOnLabels []string) datastore.RunnerOS {
+	for _, l := range runsOnLabels {
+		if o := datastore.UnmarshalRunnerOS(l); o != "" {
+			return o
+		}
+	}
+
+	return target.GetRunnerOS()
+}
+
+// getRunnerArch return architecture of runner.
+// "x64" or "arm64" in runs-on labels has priority over target.
+func getRunnerArch(target datastore.Target, runsOnLabels []string) datastore.RunnerArch {
+	for _, l := range runsOnLabels {
+		if a := datastore.UnmarshalRunnerArch(l); a != "" {
+			return a
+		}
+	}
+
+	return target.GetRunnerArch()
+}
+
+// getRunnerPlatformOS return operating system name in actions/runner release
+// getLatestRunnerVersion get latest version of runner.
+// a version is got from mirror directory in offline mode, runners can download only cached versions.
+func getLatestRunnerVersion(ctx context.Context, scope string, runnerOS datastore.RunnerOS, runnerArch datastore.RunnerArch) (string, error) {
+	if mirror.Enabled() && config.Config.RunnerMirrorOffline {
+		return mirror.LatestVersion(fmt.Sprintf("%s-%s", getRunnerPlatformOS(runnerOS), runnerArch))
+	}
+	return gh.GetLatestRunnerVersion(ctx, scope, getRunnerPlatformOS(runnerOS), string(runnerArch))
+}
+
+func getRunnerPlatformOS(runnerOS datastore.RunnerOS) string {
+	if runnerOS == datastore.RunnerOSWindows {
+		return "win"
+	}
+	return string(runnerOS)
+}
+
+func getRunnerBaseDirectory(runnerOS datastore.RunnerOS) string {
+	if runnerOS == datastore.RunnerOSWindows {
+		return config.Config.RunnerBaseDirectoryWindows
+	}
+	return config.Config.RunnerBaseDirectory
+}
+
+// getRunnerGroup return runner group name if target is organization scope
+func getRunnerGroup(target datastore.Target) string {
+	if !target.RunnerGroup.Valid || gh.DetectScope(target.Scope) != gh.Organization {
+		return ""
+	}
+	return target.RunnerGroup.String
+}
+
+// getJITConfig generate just-in-time configuration for runner.
+// runner group of target is used if it is set, otherwise use default runner group.
+func getJITConfig(ctx context.Context, installationID int64, target datastore.Target, runnerName string, labels []string) (string, error) {
+	runnerGroupID := gh.DefaultRunnerGroupID
+	if rg := getRunnerGroup(target); rg != "" {
+		client, err := gh.NewClientInstallation(installationID)
+		if err != nil {
+			return "", fmt.Errorf("failed to create a client installation: %w", err)
+		}
+		group, err := gh.ExistRunnerGroup(ctx, client, target.Scope, rg)
+		if err != nil {
+			return "", fmt.Errorf("failed to get runner group (name: %s): %w", rg, err)
+		}
+		runnerGroupID = group.GetID()
+	}
+
+	return gh.GenerateJITConfig(ctx, installationID, target.Scope, runnerName, runnerGroupID, uniqueLabels(labels))
+}
+
+// uniqueLabels remove duplicated labels with keeping order
+func uniqueLabels(labels []string) []string {
+	seen := make(map[string]struct{}, len(labels))
+	var result []string
+	for _, l := range labels {
+		key := strings.ToLower(l)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, l)
+	}
+	return result
 }
 
 func labelsToOneLine(labels []string) string {
@@ -153,6 +320,17 @@ type templateCreateLatestRunnerOnceValue struct {
 	RunnerArg               string
 	AdditionalLabels        string
 	RunnerBaseDirectory     string
+	RunnerArch              string
+	RunnerGroup             string
+	RunnerJITConfig         string
+	RunnerDownloadBaseURL   string
+	RunnerMirror            bool // verify checksum from RunnerDownloadBaseURL if true
+
+	// values from script template
+	TemplateName string
+	Environment  map[string]string
+	PreInstall   string
+	PostInstall  string
 }
 
 // templateCreateLatestRunnerOnce is script template of setup runner.
@@ -169,7 +347,13 @@ RUNNER_TOKEN={{.RunnerRegistrationToken}}
 RUNNER_USER={{.RunnerUser}}
 RUNNER_VERSION={{.RunnerVersion}}
 RUNNER_BASE_DIRECTORY={{.RunnerBaseDirectory}}
-
+RUNNER_ARCH={{.RunnerArch}}
+RUNNER_GROUP="{{.RunnerGroup}}"
+RUNNER_JIT_CONFIG="{{.RunnerJITConfig}}"
+RUNNER_DOWNLOAD_BASE_URL={{.RunnerDownloadBaseURL}}
+{{ range $key, $value := .Environment -}}
+export {{ $key }}={{ quote $value }}
+{{ end }}
 sudo_prefix=""
 if [ $(id -u) -eq 0 ]; then  # if root
 sudo_prefix="sudo -E -u ${RUNNER_USER} "
@@ -233,7 +417,7 @@ function get_runner_file_name()
     trimmed_runner_version=$(echo ${RUNNER_VERSION:1})
 
     if [ "${runner_plat}" = "linux" ]; then
-        echo "actions-runner-${runner_plat}-x64-${trimmed_runner_version}.tar.gz"
+        echo "actions-runner-${runner_plat}-${RUNNER_ARCH}-${trimmed_runner_version}.tar.gz"
     fi
 
     if [ "${runner_plat}" = "osx" ]; then
@@ -248,12 +432,25 @@ function download_runner()
     runner_version=$1
     runner_file=$2
 
-    runner_url="https://github.com/actions/runner/releases/download/${runner_version}/${runner_file}"
+    runner_url="${RUNNER_DOWNLOAD_BASE_URL}/${runner_version}/${runner_file}"
 
     echo "Downloading ${runner_version} for ${runner_plat} ..."
     echo $runner_url
 
-    curl -O -L ${runner_url}
+    curl -f -O -L ${runner_url}
+{{- if .RunnerMirror }}
+
+    expected_checksum=$(curl -f -s -L "${runner_url}.sha256")
+    if which sha256sum; then
+        actual_checksum=$(sha256sum ${runner_file} | cut -d ' ' -f 1)
+    else
+        actual_checksum=$(shasum -a 256 ${runner_file} | cut -d ' ' -f 1)
+    fi
+    if [ "${actual_checksum}" != "${expected_checksum}" ]; then
+        rm -f ${runner_file}
+        fatal "checksum mismatch ${runner_file} (expected: ${expected_checksum}, actual: ${actual_checksum})"
+    fi
+{{- end }}
 
     ls -la *.tar.gz
 }
@@ -284,7 +481,13 @@ configure_environment
 
 cd ${RUNNER_BASE_DIRECTORY}
 ${sudo_prefix}mkdir -p runner
-
+{{ if .PreInstall }}
+#---------------------------------------
+# pre-install hook ({{.TemplateName}})
+#---------------------------------------
+{{.PreInstall}}
+cd ${RUNNER_BASE_DIRECTORY}
+{{ end }}
 #---------------------------------------
 # Download latest released and extract
 #---------------------------------------
@@ -296,10 +499,10 @@ runner_file=$(get_runner_file_name ${RUNNER_VERSION} ${runner_plat})
 if [ -f "${RUNNER_BASE_DIRECTORY}/runner/config.sh" ]; then
     # already extracted
     echo "${RUNNER_BASE_DIRECTORY}/runner/config.sh exists. skipping download and extract."
-elif [ -f "/usr/local/etc/runner-${RUNNER_VERSION}/config.sh" ]; then
-    echo "runner-${RUNNER_VERSION} cache is found. skipping download and extract."
+elif [ -f "/usr/local/etc/runner-${RUNNER_VERSION}-${RUNNER_ARCH}/config.sh" ]; then
+    echo "runner-${RUNNER_VERSION}-${RUNNER_ARCH} cache is found. skipping download and extract."
     rm -r ./runner
-    mv /usr/local/etc/runner-${RUNNER_VERSION} ./runner
+    mv /usr/local/etc/runner-${RUNNER_VERSION}-${RUNNER_ARCH} ./runner
 elif [ -f "${runner_file}" ]; then
     echo "${runner_file} exists. skipping download."
     extract_runner ${runner_file} ${RUNNER_USER}
@@ -322,17 +525,25 @@ if [ -n "${ghe_hostname}" ]; then
     runner_url="${ghe_hostname}/${runner_scope}"
 fi
 
+{{ if .RunnerJITConfig -}}
+echo
+echo "Use just-in-time configuration, skip configuring ${runner_name} @ $runner_url"
+{{ else -}}
 echo
 echo "Configuring ${runner_name} @ $runner_url"
+runner_group_arg=""
+if [ -n "${RUNNER_GROUP}" ]; then
+    runner_group_arg="--runnergroup \"${RUNNER_GROUP}\""
+fi
 {{ if eq .RunnerArg "--once" -}}
-echo "./config.sh --unattended --url $runner_url --token *** --name $runner_name --labels myshoes"
-${sudo_prefix}bash -c "source /etc/environment; ./config.sh --unattended --url $runner_url --token $RUNNER_TOKEN --name $runner_name --labels myshoes{{.AdditionalLabels}}"
+echo "./config.sh --unattended --url $runner_url --token *** --name $runner_name --labels myshoes ${runner_group_arg}"
+${sudo_prefix}bash -c "source /etc/environment; ./config.sh --unattended --url $runner_url --token $RUNNER_TOKEN --name $runner_name --labels myshoes{{.AdditionalLabels}} ${runner_group_arg}"
 {{ else -}}
-echo "./config.sh --unattended --url $runner_url --token *** --name $runner_name --labels myshoes {{.RunnerArg}}"
-${sudo_prefix}bash -c "source /etc/environment; ./config.sh --unattended --url $runner_url --token $RUNNER_TOKEN --name $runner_name --labels myshoes{{.AdditionalLabels}} {{.RunnerArg}}"
+echo "./config.sh --unattended --url $runner_url --token *** --name $runner_name --labels myshoes ${runner_group_arg} {{.RunnerArg}}"
+${sudo_prefix}bash -c "source /etc/environment; ./config.sh --unattended --url $runner_url --token $RUNNER_TOKEN --name $runner_name --labels myshoes{{.AdditionalLabels}} ${runner_group_arg} {{.RunnerArg}}"
+{{ end -}}
 {{ end }}
 
-
 #---------------------------------------
 # patch once commands
 #---------------------------------------
@@ -379,7 +590,13 @@ fi
 if [ -e "/myshoes-actions-runner-hook-job-completed.sh" ]; then
 	export ACTIONS_RUNNER_HOOK_JOB_COMPLETED="/myshoes-actions-runner-hook-job-completed.sh"
 fi
-
+{{ if .PostInstall }}
+#---------------------------------------
+# post-install hook ({{.TemplateName}})
+#---------------------------------------
+{{.PostInstall}}
+cd ${RUNNER_BASE_DIRECTORY}/runner
+{{ end }}
 #---------------------------------------
 # run!
 #---------------------------------------
@@ -387,7 +604,10 @@ fi
 # GitHub-hosted runner load /etc/environment in /opt/runner/provisioner/provisioner.
 # So, we need to load /etc/environment for job on self-hosted runner.
 
-{{ if eq .RunnerArg "--once" -}}
+{{ if .RunnerJITConfig -}}
+echo 'bash -c "source /etc/environment; ./run.sh --jitconfig ***"'
+${sudo_prefix}bash -c "source /etc/environment; ./run.sh --jitconfig ${RUNNER_JIT_CONFIG}"
+{{ else if eq .RunnerArg "--once" -}}
 echo 'bash -c "source /etc/environment; ./bin/runsvc.sh  {{.RunnerArg}}"'
 ${sudo_prefix}bash -c "source /etc/environment; ./bin/runsvc.sh  {{.RunnerArg}}"
 {{ else -}}
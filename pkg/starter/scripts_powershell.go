@@ -0,0 +1,158 @@
+package starter
+
+const templateCompressedScriptPowerShell = `$ErrorActionPreference = "Stop"
+
+# main script compressed base64 and gzip
+$CompressedScript = "{{.CompressedScript}}"
+$RunnerBaseDirectory = "{{.RunnerBaseDirectory}}"
+$MainScriptPath = Join-Path $RunnerBaseDirectory "main.ps1"
+
+New-Item -ItemType Directory -Force -Path $RunnerBaseDirectory | Out-Null
+
+$compressedStream = New-Object System.IO.MemoryStream(, [System.Convert]::FromBase64String($CompressedScript))
+$gzipStream = New-Object System.IO.Compression.GzipStream($compressedStream, [System.IO.Compression.CompressionMode]::Decompress)
+$reader = New-Object System.IO.StreamReader($gzipStream)
+Set-Content -Path $MainScriptPath -Value $reader.ReadToEnd() -Encoding UTF8
+$reader.Close()
+
+& powershell.exe -NoProfile -ExecutionPolicy Bypass -File $MainScriptPath
+exit $LASTEXITCODE`
+
+// templateCreateLatestRunnerOncePowerShell is script template of setup runner for Windows.
+// it uses same values as templateCreateLatestRunnerOnce. RunnerUser and RunnerServiceJS are not used.
+const templateCreateLatestRunnerOncePowerShell = `$ErrorActionPreference = "Stop"
+$ProgressPreference = "SilentlyContinue"
+
+$RunnerScope = "{{.Scope}}"
+$GHEHostname = "{{.GHEDomain}}"
+$RunnerName = "{{.RunnerName}}"
+$RunnerToken = "{{.RunnerRegistrationToken}}"
+$RunnerVersion = "{{.RunnerVersion}}"
+$RunnerBaseDirectory = "{{.RunnerBaseDirectory}}"
+$RunnerArch = "{{.RunnerArch}}"
+$RunnerGroup = "{{.RunnerGroup}}"
+$RunnerJITConfig = "{{.RunnerJITConfig}}"
+$RunnerDownloadBaseURL = "{{.RunnerDownloadBaseURL}}"
+{{ range $key, $value := .Environment -}}
+$env:{{ $key }} = {{ psquote $value }}
+{{ end }}
+Write-Output "Configuring runner @ $RunnerScope"
+
+if ([string]::IsNullOrEmpty($RunnerScope)) {
+    Write-Error "supply scope as argument 1"
+    exit 1
+}
+
+New-Item -ItemType Directory -Force -Path $RunnerBaseDirectory | Out-Null
+Set-Location $RunnerBaseDirectory
+New-Item -ItemType Directory -Force -Path runner | Out-Null
+{{ if .PreInstall }}
+#---------------------------------------
+# pre-install hook ({{.TemplateName}})
+#---------------------------------------
+{{.PreInstall}}
+Set-Location $RunnerBaseDirectory
+{{ end }}
+#---------------------------------------
+# Download latest released and extract
+#---------------------------------------
+Write-Output ""
+Write-Output "Downloading latest runner ..."
+
+$TrimmedRunnerVersion = $RunnerVersion.TrimStart("v")
+$RunnerFile = "actions-runner-win-$RunnerArch-$TrimmedRunnerVersion.zip"
+$RunnerDirectory = Join-Path $RunnerBaseDirectory "runner"
+$RunnerCacheFile = Join-Path $env:ProgramData "myshoes\$RunnerFile"
+
+if (Test-Path (Join-Path $RunnerDirectory "config.cmd")) {
+    # already extracted
+    Write-Output "$RunnerDirectory\config.cmd exists. skipping download and extract."
+} else {
+    if (Test-Path $RunnerFile) {
+        Write-Output "$RunnerFile exists. skipping download."
+    } elseif (Test-Path $RunnerCacheFile) {
+        Write-Output "$RunnerFile cache is found. skipping download."
+        Move-Item -Path $RunnerCacheFile -Destination .
+    } else {
+        $RunnerURL = "$RunnerDownloadBaseURL/$RunnerVersion/$RunnerFile"
+        Write-Output "Downloading $RunnerVersion for win ..."
+        Write-Output $RunnerURL
+        Invoke-WebRequest -Uri $RunnerURL -OutFile $RunnerFile -UseBasicParsing
+{{- if .RunnerMirror }}
+
+        $ExpectedChecksum = (Invoke-WebRequest -Uri "$RunnerURL.sha256" -UseBasicParsing).Content.ToString().Trim()
+        $ActualChecksum = (Get-FileHash -Path $RunnerFile -Algorithm SHA256).Hash.ToLower()
+        if ($ActualChecksum -ne $ExpectedChecksum) {
+            Remove-Item -Path $RunnerFile -Force
+            Write-Error "checksum mismatch $RunnerFile (expected: $ExpectedChecksum, actual: $ActualChecksum)"
+            exit 1
+        }
+{{- end }}
+    }
+
+    Write-Output "Extracting $RunnerFile to .\runner"
+    Expand-Archive -Path $RunnerFile -DestinationPath $RunnerDirectory -Force
+}
+
+Set-Location $RunnerDirectory
+
+#---------------------------------------
+# Unattend config
+#---------------------------------------
+$RunnerURL = "https://github.com/$RunnerScope"
+if (-not [string]::IsNullOrEmpty($GHEHostname)) {
+    $RunnerURL = "$GHEHostname/$RunnerScope"
+}
+
+{{ if .RunnerJITConfig -}}
+Write-Output ""
+Write-Output "Use just-in-time configuration, skip configuring $RunnerName @ $RunnerURL"
+{{ else -}}
+Write-Output ""
+Write-Output "Configuring $RunnerName @ $RunnerURL"
+$ConfigArgs = @("--unattended", "--url", $RunnerURL, "--token", $RunnerToken, "--name", $RunnerName, "--labels", "myshoes{{.AdditionalLabels}}")
+if (-not [string]::IsNullOrEmpty($RunnerGroup)) {
+    $ConfigArgs += @("--runnergroup", $RunnerGroup)
+}
+{{ if and .RunnerArg (ne .RunnerArg "--once") -}}
+$ConfigArgs += "{{.RunnerArg}}"
+{{ end -}}
+Write-Output "./config.cmd --unattended --url $RunnerURL --token *** --name $RunnerName --labels myshoes{{.AdditionalLabels}}"
+& .\config.cmd @ConfigArgs
+if ($LASTEXITCODE -ne 0) {
+    Write-Error "failed to configure runner"
+    exit $LASTEXITCODE
+}
+{{ end }}
+#---------------------------------------
+# Configure run commands
+#---------------------------------------
+
+# Configure job management hooks if script files exist
+if (Test-Path "C:\myshoes-actions-runner-hook-job-started.ps1") {
+    $env:ACTIONS_RUNNER_HOOK_JOB_STARTED = "C:\myshoes-actions-runner-hook-job-started.ps1"
+}
+if (Test-Path "C:\myshoes-actions-runner-hook-job-completed.ps1") {
+    $env:ACTIONS_RUNNER_HOOK_JOB_COMPLETED = "C:\myshoes-actions-runner-hook-job-completed.ps1"
+}
+{{ if .PostInstall }}
+#---------------------------------------
+# post-install hook ({{.TemplateName}})
+#---------------------------------------
+{{.PostInstall}}
+Set-Location $RunnerDirectory
+{{ end }}
+#---------------------------------------
+# run!
+#---------------------------------------
+{{ if .RunnerJITConfig -}}
+Write-Output "./run.cmd --jitconfig ***"
+& .\run.cmd --jitconfig $RunnerJITConfig
+{{ else if eq .RunnerArg "--once" -}}
+Write-Output "./run.cmd {{.RunnerArg}}"
+& .\run.cmd {{.RunnerArg}}
+{{ else -}}
+Write-Output "./run.cmd"
+& .\run.cmd
+{{ end -}}
+exit $LASTEXITCODE`
@@ -0,0 +1,240 @@
+package starter
+
+import (
+	"database/sql"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/gh"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+func testScriptValue() templateCreateLatestRunnerOnceValue {
+	return templateCreateLatestRunnerOnceValue{
+		Scope:                   "octocat/hello-world",
+		GHEDomain:               "https://github.com",
+		RunnerRegistrationToken: "registration-token",
+		RunnerName:              "myshoes-test",
+		RunnerUser:              "runner",
+		RunnerVersion:           "v2.311.0",
+		RunnerServiceJS:         "// RunnerService.js",
+		RunnerArg:               "--ephemeral",
+		AdditionalLabels:        labelsToOneLine([]string{"dependabot"}),
+		RunnerBaseDirectory:     "/tmp",
+		RunnerArch:              string(datastore.RunnerArchX64),
+		RunnerDownloadBaseURL:   gh.RunnerReleaseDownloadURL,
+	}
+}
+
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+
+	p := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(p, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %+v", err)
+		}
+	}
+
+	want, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create): %+v", err)
+	}
+	if diff := cmp.Diff(string(want), got); diff != "" {
+		t.Errorf("mismatch %s (-want +got):\n%s", p, diff)
+	}
+}
+
+func TestRenderSetupScript_Golden(t *testing.T) {
+	linuxOnce := testScriptValue()
+	linuxOnce.RunnerVersion = "v2.275.0"
+	linuxOnce.RunnerArg = "--once"
+
+	windows := testScriptValue()
+	windows.RunnerBaseDirectory = `C:\myshoes`
+
+	windowsOnce := windows
+	windowsOnce.RunnerVersion = "v2.275.0"
+	windowsOnce.RunnerArg = "--once"
+
+	linuxMirror := testScriptValue()
+	linuxMirror.RunnerDownloadBaseURL = "https://myshoes.example.com/runner/mirror"
+	linuxMirror.RunnerMirror = true
+
+	linuxARM64 := testScriptValue()
+	linuxARM64.RunnerArch = string(datastore.RunnerArchARM64)
+
+	windowsARM64 := windows
+	windowsARM64.RunnerArch = string(datastore.RunnerArchARM64)
+
+	windowsMirror := windows
+	windowsMirror.RunnerDownloadBaseURL = linuxMirror.RunnerDownloadBaseURL
+	windowsMirror.RunnerMirror = true
+
+	gpuTemplate := datastore.ScriptTemplate{
+		Name:        "gpu",
+		PreInstall:  "Write-Output 'pre-install {{.RunnerName}}'",
+		PostInstall: "Write-Output 'post-install {{.TemplateName}}'",
+		Environment: datastore.ScriptEnvironment{"GREETING": "it's me"},
+	}
+	windowsJIT := windows
+	windowsJIT.RunnerRegistrationToken = ""
+	windowsJIT.RunnerJITConfig = "encoded-jit-config"
+	windowsJIT.RunnerGroup = "expensive-runners"
+	windowsJIT.TemplateName = gpuTemplate.Name
+	windowsJIT.Environment = gpuTemplate.Environment
+
+	tests := []struct {
+		name     string
+		st       datastore.ScriptTemplate
+		runnerOS datastore.RunnerOS
+		input    templateCreateLatestRunnerOnceValue
+	}{
+		{
+			name:     "linux_ephemeral",
+			runnerOS: datastore.RunnerOSLinux,
+			input:    testScriptValue(),
+		},
+		{
+			name:     "linux_once",
+			runnerOS: datastore.RunnerOSLinux,
+			input:    linuxOnce,
+		},
+		{
+			name:     "windows_ephemeral",
+			runnerOS: datastore.RunnerOSWindows,
+			input:    windows,
+		},
+		{
+			name:     "windows_once",
+			runnerOS: datastore.RunnerOSWindows,
+			input:    windowsOnce,
+		},
+		{
+			name:     "linux_arm64",
+			runnerOS: datastore.RunnerOSLinux,
+			input:    linuxARM64,
+		},
+		{
+			name:     "windows_arm64",
+			runnerOS: datastore.RunnerOSWindows,
+			input:    windowsARM64,
+		},
+		{
+			name:     "linux_mirror",
+			runnerOS: datastore.RunnerOSLinux,
+			input:    linuxMirror,
+		},
+		{
+			name:     "windows_mirror",
+			runnerOS: datastore.RunnerOSWindows,
+			input:    windowsMirror,
+		},
+		{
+			name:     "windows_jit_script_template",
+			st:       gpuTemplate,
+			runnerOS: datastore.RunnerOSWindows,
+			input:    windowsJIT,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := renderSetupScript(test.st, test.runnerOS, test.input)
+			if err != nil {
+				t.Fatalf("failed to render setup script: %+v", err)
+			}
+			assertGolden(t, test.name, got)
+		})
+	}
+}
+
+func TestRenderCompressedScript_Golden(t *testing.T) {
+	for _, runnerOS := range []datastore.RunnerOS{datastore.RunnerOSLinux, datastore.RunnerOSWindows} {
+		t.Run(string(runnerOS), func(t *testing.T) {
+			got, err := renderCompressedScript(runnerOS, "H4sIAAAAAAAA/0pNzshXyM3PS1VIy8lPT1UEBAAA//8=")
+			if err != nil {
+				t.Fatalf("failed to render compressed script: %+v", err)
+			}
+			assertGolden(t, "compressed_"+string(runnerOS), got)
+		})
+	}
+}
+
+func TestGetRunnerOS(t *testing.T) {
+	tests := []struct {
+		target datastore.Target
+		labels []string
+		want   datastore.RunnerOS
+	}{
+		{
+			target: datastore.Target{},
+			labels: []string{"self-hosted", "myshoes"},
+			want:   datastore.RunnerOSLinux,
+		},
+		{
+			target: datastore.Target{RunnerOS: datastore.RunnerOSWindows},
+			labels: []string{"self-hosted", "myshoes"},
+			want:   datastore.RunnerOSWindows,
+		},
+		{
+			target: datastore.Target{},
+			labels: []string{"self-hosted", "Windows"},
+			want:   datastore.RunnerOSWindows,
+		},
+		{
+			target: datastore.Target{RunnerOS: datastore.RunnerOSWindows, ScriptTemplate: sql.NullString{String: "gpu", Valid: true}},
+			labels: []string{"self-hosted", "linux"},
+			want:   datastore.RunnerOSLinux,
+		},
+	}
+
+	for _, test := range tests {
+		got := getRunnerOS(test.target, test.labels)
+		if got != test.want {
+			t.Fatalf("want %s, but got %s (labels: %v)", test.want, got, test.labels)
+		}
+	}
+}
+
+func TestGetRunnerArch(t *testing.T) {
+	tests := []struct {
+		target datastore.Target
+		labels []string
+		want   datastore.RunnerArch
+	}{
+		{
+			target: datastore.Target{},
+			labels: []string{"self-hosted", "myshoes"},
+			want:   datastore.RunnerArchX64,
+		},
+		{
+			target: datastore.Target{RunnerArch: datastore.RunnerArchARM64},
+			labels: []string{"self-hosted", "myshoes"},
+			want:   datastore.RunnerArchARM64,
+		},
+		{
+			target: datastore.Target{},
+			labels: []string{"self-hosted", "ARM64"},
+			want:   datastore.RunnerArchARM64,
+		},
+		{
+			target: datastore.Target{RunnerArch: datastore.RunnerArchARM64},
+			labels: []string{"self-hosted", "X64"},
+			want:   datastore.RunnerArchX64,
+		},
+	}
+
+	for _, test := range tests {
+		got := getRunnerArch(test.target, test.labels)
+		if got != test.want {
+			t.Fatalf("want %s, but got %s (labels: %v)", test.want, got, test.labels)
+		}
+	}
+}
@@ -14,7 +14,6 @@ import (
 	"github.com/google/go-github/v80/github"
 
 	"golang.org/x/sync/errgroup"
-	"golang.org/x/sync/semaphore"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -24,9 +23,11 @@ import (
 	"github.com/whywaita/myshoes/pkg/datastore"
 	"github.com/whywaita/myshoes/pkg/gh"
 	"github.com/whywaita/myshoes/pkg/logger"
+	"github.com/whywaita/myshoes/pkg/notify"
 	"github.com/whywaita/myshoes/pkg/runner"
 	"github.com/whywaita/myshoes/pkg/shoes"
 	"github.com/whywaita/myshoes/pkg/starter/safety"
+	"github.com/whywaita/myshoes/pkg/tracing"
 )
 
 var (
@@ -42,14 +43,67 @@ var (
 
 	// AddInstanceRetryCount is count of retry to add instance
 	AddInstanceRetryCount = sync.Map{}
+
+	// backoffWakeup has channel for waking up job in backoff, key: job.UUID
+	backoffWakeup = sync.Map{}
 )
 
+// IsInProgress return true if job is processing now (include waiting for backoff)
+func IsInProgress(jobID uuid.UUID) bool {
+	_, ok := inProgress.Load(jobID)
+	return ok
+}
+
+// IsProcessing return true if job is processing now, a job waiting for backoff is not processing
+func IsProcessing(jobID uuid.UUID) bool {
+	if !IsInProgress(jobID) {
+		return false
+	}
+	_, inBackoff := backoffWakeup.Load(jobID)
+	return !inBackoff
+}
+
+// GetRetryCount return count of retry to add instance for job
+func GetRetryCount(jobID uuid.UUID) int {
+	c, ok := AddInstanceRetryCount.Load(jobID)
+	if !ok {
+		return 0
+	}
+	count, _ := c.(int)
+	return count
+}
+
+// ResetRetry reset backoff state of job. a job waiting for backoff is processed immediately.
+func ResetRetry(jobID uuid.UUID) {
+	AddInstanceRetryCount.Delete(jobID)
+	if ch, ok := backoffWakeup.LoadAndDelete(jobID); ok {
+		close(ch.(chan struct{}))
+	}
+}
+
+// pruneRetryCount delete retry count of jobs that are already finished or deleted
+func pruneRetryCount(jobs []datastore.Job) {
+	queued := make(map[uuid.UUID]struct{}, len(jobs))
+	for _, j := range jobs {
+		queued[j.UUID] = struct{}{}
+	}
+	AddInstanceRetryCount.Range(func(key, value any) bool {
+		jobID := key.(uuid.UUID)
+		if _, ok := queued[jobID]; !ok && !IsInProgress(jobID) {
+			AddInstanceRetryCount.Delete(jobID)
+		}
+		return true
+	})
+}
+
 // Starter is dispatcher for running job
 type Starter struct {
 	ds              datastore.Datastore
 	safety          safety.Safety
-	runnerVersion   string
 	notifyEnqueueCh <-chan struct{}
+
+	mu            sync.RWMutex
+	runnerVersion string
 }
 
 // New create starter instance
@@ -62,11 +116,31 @@ func New(ds datastore.Datastore, s safety.Safety, runnerVersion string, notifyEn
 	}
 }
 
+// getRunnerVersion return version of runner that will be installed
+func (s *Starter) getRunnerVersion() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.runnerVersion
+}
+
+func (s *Starter) setRunnerVersion(runnerVersion string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runnerVersion = runnerVersion
+}
+
 // Loop is main loop for starter
 func (s *Starter) Loop(ctx context.Context) error {
 	logger.Logf(false, "start starter loop")
 	ch := make(chan datastore.Job)
 
+	config.OnRuntimeChange(func(rc config.Runtime) {
+		if s.getRunnerVersion() != rc.RunnerVersion {
+			logger.Logf(false, "change runner version to %s", rc.RunnerVersion)
+			s.setRunnerVersion(rc.RunnerVersion)
+		}
+	})
+
 	eg, ctx := errgroup.WithContext(ctx)
 
 	eg.Go(func() error {
@@ -118,11 +192,22 @@ func (s *Starter) Loop(ctx context.Context) error {
 
 func (s *Starter) dispatcher(ctx context.Context, ch chan datastore.Job) error {
 	logger.Logf(true, "start to check starter")
+	inMaintenance, err := datastore.IsInMaintenance(ctx, s.ds, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to check maintenance: %w", err)
+	}
+	if inMaintenance {
+		// jobs are kept in queue, will be processed after maintenance
+		logger.Logf(true, "maintenance window is active, skip to dispatch jobs")
+		return nil
+	}
+
 	jobs, err := s.ds.ListJobs(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get jobs: %w", err)
 	}
 
+	pruneRetryCount(jobs)
 	for _, j := range jobs {
 		// send to processor
 		ch <- j
@@ -131,8 +216,16 @@ func (s *Starter) dispatcher(ctx context.Context, ch chan datastore.Job) error {
 	return nil
 }
 
+// isProvisioningPaused return true if maintenance window is active or target of job is paused
+func (s *Starter) isProvisioningPaused(ctx context.Context, job datastore.Job) (bool, error) {
+	return datastore.IsProvisioningPaused(ctx, s.ds, job.TargetID, time.Now().UTC())
+}
+
 func (s *Starter) run(ctx context.Context, ch chan datastore.Job) error {
-	sem := semaphore.NewWeighted(config.Config.MaxConnectionsToBackend)
+	sem := util.NewSemaphore(config.GetRuntime().MaxConnectionsToBackend)
+	config.OnRuntimeChange(func(rc config.Runtime) {
+		sem.Resize(rc.MaxConnectionsToBackend)
+	})
 
 	// Processor
 	for {
@@ -147,14 +240,9 @@ func (s *Starter) run(ctx context.Context, ch chan datastore.Job) error {
 			c, _ := AddInstanceRetryCount.LoadOrStore(job.UUID, 0)
 			count, _ := c.(int)
 
-			runID, jobID, err := extractWorkflowIDs(job)
-			if err != nil {
-				logger.Logf(true, "found new job: %s (repo: %s)", job.UUID, job.Repository)
-			} else {
-				logger.Logf(true, "found new job: %s (gh_run_id: %d, gh_job_id: %d, repo: %s)", job.UUID, runID, jobID, job.Repository)
-			}
+			logger.Debug(withJobFields(ctx, job), "found new job", "repo", job.Repository)
 			CountWaiting.Add(1)
-			if err := sem.Acquire(ctx, 1); err != nil {
+			if err := sem.Acquire(ctx); err != nil {
 				return fmt.Errorf("failed to Acquire: %w", err)
 			}
 			CountWaiting.Add(-1)
@@ -163,25 +251,60 @@ func (s *Starter) run(ctx context.Context, ch chan datastore.Job) error {
 			inProgress.Store(job.UUID, struct{}{})
 
 			sleep := util.CalcRetryTime(count)
-			if count > 0 {
-				AddInstanceRetryTotal.WithLabelValues(job.UUID.String()).Inc()
-				AddInstanceBackoffDuration.WithLabelValues(job.UUID.String()).Observe(sleep.Seconds())
-			}
-			go func(job datastore.Job, sleep time.Duration) {
+			go func(job datastore.Job, sleep time.Duration, count int) {
+				ctx := withJobFields(ctx, job)
 				defer func() {
-					sem.Release(1)
+					sem.Release()
 					inProgress.Delete(job.UUID)
 					CountRunning.Add(-1)
 				}()
+				if count > 0 {
+					scope, resourceType := s.jobMetricLabels(ctx, job)
+					observeBackoff(scope, resourceType, job.UUID, sleep)
+				}
+
+				if sleep > 0 {
+					wakeup := make(chan struct{})
+					backoffWakeup.Store(job.UUID, wakeup)
+					select {
+					case <-time.After(sleep):
+						backoffWakeup.Delete(job.UUID)
+					case <-wakeup:
+						// reset by ResetRetry
+						logger.Info(ctx, "backoff of job is reset, will process now")
+						count = 0
+					}
+
+					// job may be deleted by admin while waiting for backoff
+					if _, err := s.ds.GetJob(ctx, job.UUID); errors.Is(err, datastore.ErrNotFound) {
+						logger.Info(ctx, "job is already deleted, skip")
+						AddInstanceRetryCount.Delete(job.UUID)
+						return
+					}
+				}
+
+				paused, err := s.isProvisioningPaused(ctx, job)
+				if err != nil {
+					logger.Error(ctx, "failed to check provisioning is paused", "error", err)
+				}
+				if paused {
+					// keep job and retry count, job will be processed after resume
+					logger.Debug(ctx, "provisioning is paused, skip job")
+					return
+				}
 
-				time.Sleep(sleep)
 				if err := s.ProcessJob(ctx, job); err != nil {
 					AddInstanceRetryCount.Store(job.UUID, count+1)
-					logger.Logf(false, "failed to process job: %+v\n", err)
+					scope, resourceType := s.jobMetricLabels(ctx, job)
+					observeRetry(scope, resourceType, job.UUID, err)
+					logger.Error(ctx, "failed to process job", "error", err, "error_class", util.ErrorClass(err), "retry_count", count+1)
+					if count+1 == config.Config.Notify.JobRetryThreshold {
+						notifyRetryExceeded(ctx, job, scope, count+1, err)
+					}
 				} else {
 					AddInstanceRetryCount.Delete(job.UUID)
 				}
-			}(job, sleep)
+			}(job, sleep, count)
 
 		case <-ctx.Done():
 			return nil
@@ -189,6 +312,22 @@ func (s *Starter) run(ctx context.Context, ch chan datastore.Job) error {
 	}
 }
 
+// notifyRetryExceeded notify that a job is retried many times
+func notifyRetryExceeded(ctx context.Context, job datastore.Job, scope string, count int, err error) {
+	notify.Notify(ctx, notify.Event{
+		Kind:    notify.KindJobRetryExceeded,
+		Key:     job.UUID.String(),
+		Title:   fmt.Sprintf("job in %s is retried %d times", job.Repository, count),
+		Message: err.Error(),
+		Fields: map[string]string{
+			"job_id":      job.UUID.String(),
+			"target":      scope,
+			"repository":  job.Repository,
+			"error_class": util.ErrorClass(err),
+		},
+	})
+}
+
 // extractWorkflowIDs extracts GitHub workflow run ID and job ID from a datastore.Job
 func extractWorkflowIDs(job datastore.Job) (runID int64, jobID int64, err error) {
 	webhookEvent, err := github.ParseWebHook("workflow_job", []byte(job.CheckEventJSON))
@@ -208,14 +347,39 @@ func extractWorkflowIDs(job datastore.Job) (runID int64, jobID int64, err error)
 	return workflowJob.GetWorkflowJob().GetRunID(), workflowJob.GetWorkflowJob().GetID(), nil
 }
 
+// withJobFields return context that has fields of job for logging
+func withJobFields(ctx context.Context, job datastore.Job) context.Context {
+	ctx = logger.WithFields(ctx,
+		logger.KeyTargetID, job.TargetID.String(),
+		logger.KeyJobID, job.UUID.String(),
+		logger.KeyRunnerName, runner.ToName(job.UUID.String()),
+	)
+	if runID, jobID, err := extractWorkflowIDs(job); err == nil {
+		ctx = logger.WithFields(ctx, logger.KeyGitHubRunID, runID, logger.KeyGitHubJobID, jobID)
+	}
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		ctx = logger.WithFields(ctx, logger.KeyTraceID, traceID)
+	}
+	return ctx
+}
+
 // ProcessJob is process job
 func (s *Starter) ProcessJob(ctx context.Context, job datastore.Job) error {
-	runID, jobID, err := extractWorkflowIDs(job)
-	if err != nil {
-		logger.Logf(false, "start job (job id: %s, repo: %s)\n", job.UUID.String(), job.Repository)
-	} else {
-		logger.Logf(false, "start job (job id: %s, gh_run_id: %d, gh_job_id: %d, repo: %s)\n", job.UUID.String(), runID, jobID, job.Repository)
-	}
+	// span of job is child of span that received webhook
+	ctx, span := tracing.Start(tracing.ContextWithTraceParent(ctx, job.TraceParent), "ProcessJob",
+		tracing.AttributeTargetID.String(job.TargetID.String()),
+		tracing.AttributeJobID.String(job.UUID.String()),
+		tracing.AttributeRunnerName.String(runner.ToName(job.UUID.String())),
+	)
+	defer span.End()
+
+	err := s.processJob(withJobFields(ctx, job), job)
+	tracing.RecordError(span, err)
+	return err
+}
+
+func (s *Starter) processJob(ctx context.Context, job datastore.Job) error {
+	logger.Info(ctx, "start job", "repo", job.Repository)
 
 	isOK, err := s.safety.Check(&job)
 	if err != nil {
@@ -233,26 +397,23 @@ func (s *Starter) ProcessJob(ctx context.Context, job datastore.Job) error {
 	if err != nil {
 		return fmt.Errorf("failed to retrieve relational target: (target ID: %s, job ID: %s): %w", job.TargetID, job.UUID, err)
 	}
+	if c, _ := AddInstanceRetryCount.Load(job.UUID); c == nil || c.(int) == 0 {
+		// observe only first dequeue, retries are observed by backoff metrics
+		if job.ReceivedAt.Valid {
+			JobQueueDuration.WithLabelValues(target.Scope, target.ResourceType.String()).Observe(time.Since(job.ReceivedAt.Time).Seconds())
+		}
+	}
 
-	cctx, cancel := context.WithTimeout(ctx, runner.MustRunningTime)
+	cctx, cancel := context.WithTimeout(ctx, runner.GetMustRunningTime())
 	defer cancel()
 	cloudID, ipAddress, shoesType, resourceType, err := s.bung(cctx, job, *target)
 	if err != nil {
-		runID2, jobID2, extractErr := extractWorkflowIDs(job)
-		if extractErr != nil {
-			logger.Logf(false, "failed to bung (target ID: %s, job ID: %s): %+v", job.TargetID, job.UUID, err)
-		} else {
-			logger.Logf(false, "failed to bung (target ID: %s, job ID: %s, gh_run_id: %d, gh_job_id: %d): %+v", job.TargetID, job.UUID, runID2, jobID2, err)
-		}
+		logger.Error(ctx, "failed to bung", "error", err)
 
 		if errors.Is(err, ErrInvalidLabel) {
-			if extractErr != nil {
-				logger.Logf(false, "invalid argument. so will delete (job ID: %s)", job.UUID)
-			} else {
-				logger.Logf(false, "invalid argument. so will delete (job ID: %s, gh_run_id: %d, gh_job_id: %d)", job.UUID, runID2, jobID2)
-			}
+			logger.Info(ctx, "invalid argument. so will delete job")
 			if err := s.ds.DeleteJob(ctx, job.UUID); err != nil {
-				logger.Logf(false, "failed to delete job: %+v\n", err)
+				logger.Error(ctx, "failed to delete job", "error", err)
 
 				if err := datastore.UpdateTargetStatus(ctx, s.ds, job.TargetID, datastore.TargetStatusErr, fmt.Sprintf("job id: %s", job.UUID)); err != nil {
 					return fmt.Errorf("failed to update target status (target ID: %s, job ID: %s): %w", job.TargetID, job.UUID, err)
@@ -272,17 +433,19 @@ func (s *Starter) ProcessJob(ctx context.Context, job datastore.Job) error {
 
 		return fmt.Errorf("failed to bung (target ID: %s, job ID: %s): %w", job.TargetID, job.UUID, err)
 	}
+	instanceCreatedAt := time.Now().UTC()
 	if resourceType == datastore.ResourceTypeUnknown {
 		resourceType = target.ResourceType
 	}
 
+	var onlineAt sql.NullTime
 	runnerName := runner.ToName(job.UUID.String())
-	if config.Config.Strict {
+	if config.GetRuntime().Strict {
 		if err := s.checkRegisteredRunner(ctx, runnerName, *target); err != nil {
-			logger.Logf(false, "failed to check to register runner (target ID: %s, job ID: %s): %+v\n", job.TargetID, job.UUID, err)
+			logger.Error(ctx, "failed to check to register runner", "error", err)
 
 			if err := deleteInstance(ctx, cloudID, job.CheckEventJSON); err != nil {
-				logger.Logf(false, "failed to delete an instance that not registered instance (target ID: %s, cloud ID: %s): %+v\n", job.TargetID, cloudID, err)
+				logger.Error(ctx, "failed to delete an instance that not registered instance", "cloud_id", cloudID, "error", err)
 				// not return, need to update target status if err.
 			}
 
@@ -292,8 +455,12 @@ func (s *Starter) ProcessJob(ctx context.Context, job datastore.Job) error {
 
 			return fmt.Errorf("failed to check to register runner (target ID: %s, job ID: %s): %w", job.TargetID, job.UUID, err)
 		}
+		onlineAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+		runner.RunnerOnlineDuration.WithLabelValues(target.Scope, resourceType.String()).Observe(onlineAt.Time.Sub(instanceCreatedAt).Seconds())
 	}
 
+	// labels are already validated in bung
+	runsOnLabels, _ := gh.ExtractRunsOnLabels([]byte(job.CheckEventJSON))
 	r := datastore.Runner{
 		UUID:         job.UUID,
 		ShoesType:    shoesType,
@@ -301,6 +468,7 @@ func (s *Starter) ProcessJob(ctx context.Context, job datastore.Job) error {
 		TargetID:     job.TargetID,
 		CloudID:      cloudID,
 		ResourceType: resourceType,
+		RunnerArch:   getRunnerArch(*target, runsOnLabels),
 		RunnerUser: sql.NullString{
 			String: config.Config.RunnerUser,
 			Valid:  true,
@@ -308,9 +476,14 @@ func (s *Starter) ProcessJob(ctx context.Context, job datastore.Job) error {
 		ProviderURL:    target.ProviderURL,
 		RepositoryURL:  job.RepoURL(),
 		RequestWebhook: job.CheckEventJSON,
+		InstanceCreatedAt: sql.NullTime{
+			Time:  instanceCreatedAt,
+			Valid: true,
+		},
+		OnlineAt: onlineAt,
 	}
 	if err := s.ds.CreateRunner(ctx, r); err != nil {
-		logger.Logf(false, "failed to save runner to datastore (target ID: %s, job ID: %s): %+v\n", job.TargetID, job.UUID, err)
+		logger.Error(ctx, "failed to save runner to datastore", "error", err)
 
 		if err := datastore.UpdateTargetStatus(ctx, s.ds, job.TargetID, datastore.TargetStatusErr, fmt.Sprintf("job id: %s", job.UUID)); err != nil {
 			return fmt.Errorf("failed to update target status (target ID: %s, job ID: %s): %w", job.TargetID, job.UUID, err)
@@ -320,7 +493,7 @@ func (s *Starter) ProcessJob(ctx context.Context, job datastore.Job) error {
 	}
 
 	if err := s.ds.DeleteJob(ctx, job.UUID); err != nil {
-		logger.Logf(false, "failed to delete job: %+v\n", err)
+		logger.Error(ctx, "failed to delete job", "error", err)
 
 		if err := datastore.UpdateTargetStatus(ctx, s.ds, job.TargetID, datastore.TargetStatusErr, fmt.Sprintf("job id: %s", job.UUID)); err != nil {
 			return fmt.Errorf("failed to update target status (target ID: %s, job ID: %s): %w", job.TargetID, job.UUID, err)
@@ -334,16 +507,16 @@ func (s *Starter) ProcessJob(ctx context.Context, job datastore.Job) error {
 
 // bung is start runner, like a pistol! :)
 func (s *Starter) bung(ctx context.Context, job datastore.Job, target datastore.Target) (string, string, string, datastore.ResourceType, error) {
-	runID, jobID, extractErr := extractWorkflowIDs(job)
-	if extractErr != nil {
-		logger.Logf(false, "start create instance (job: %s)", job.UUID)
-	} else {
-		logger.Logf(false, "start create instance (job: %s, gh_run_id: %d, gh_job_id: %d)", job.UUID, runID, jobID)
-	}
+	logger.Info(ctx, "start create instance")
 	runnerName := runner.ToName(job.UUID.String())
 
-	targetScope := getTargetScope(target, job)
-	script, err := s.GetSetupScript(ctx, targetScope, runnerName)
+	labels, err := gh.ExtractRunsOnLabels([]byte(job.CheckEventJSON))
+	if err != nil {
+		return "", "", "", datastore.ResourceTypeUnknown, fmt.Errorf("failed to extract labels: %w", err)
+	}
+
+	target.Scope = getTargetScope(target, job)
+	script, err := s.GetSetupScript(ctx, target, runnerName, labels)
 	if err != nil {
 		return "", "", "", datastore.ResourceTypeUnknown, fmt.Errorf("failed to get setup scripts: %w", err)
 	}
@@ -354,11 +527,7 @@ func (s *Starter) bung(ctx context.Context, job datastore.Job, target datastore.
 	}
 	defer teardown()
 
-	labels, err := gh.ExtractRunsOnLabels([]byte(job.CheckEventJSON))
-	if err != nil {
-		return "", "", "", datastore.ResourceTypeUnknown, fmt.Errorf("failed to extract labels: %w", err)
-	}
-
+	addInstanceStart := time.Now()
 	cloudID, ipAddress, shoesType, resourceType, err := client.AddInstance(ctx, runnerName, script, target.ResourceType, labels)
 	if err != nil {
 		if stat, _ := status.FromError(err); stat.Code() == codes.InvalidArgument {
@@ -366,12 +535,13 @@ func (s *Starter) bung(ctx context.Context, job datastore.Job, target datastore.
 		}
 		return "", "", "", datastore.ResourceTypeUnknown, fmt.Errorf("failed to add instance: %w", err)
 	}
-
-	if extractErr != nil {
-		logger.Logf(false, "instance create successfully! (job: %s, cloud ID: %s)", job.UUID, cloudID)
-	} else {
-		logger.Logf(false, "instance create successfully! (job: %s, cloud ID: %s, gh_run_id: %d, gh_job_id: %d)", job.UUID, cloudID, runID, jobID)
+	observedType := resourceType
+	if observedType == datastore.ResourceTypeUnknown {
+		observedType = target.ResourceType
 	}
+	AddInstanceDuration.WithLabelValues(target.Scope, observedType.String()).Observe(time.Since(addInstanceStart).Seconds())
+
+	logger.Info(ctx, "instance create successfully!", "cloud_id", cloudID)
 
 	return cloudID, ipAddress, shoesType, resourceType, nil
 }
@@ -413,7 +583,8 @@ func (s *Starter) checkRegisteredRunner(ctx context.Context, runnerName string,
 	}
 	owner, repo := gh.DivideScope(target.Scope)
 
-	cctx, cancel := context.WithTimeout(ctx, runner.MustRunningTime)
+	mustRunningTime := runner.GetMustRunningTime()
+	cctx, cancel := context.WithTimeout(ctx, mustRunningTime)
 	defer cancel()
 
 	ticker := time.NewTicker(1 * time.Second)
@@ -424,7 +595,7 @@ func (s *Starter) checkRegisteredRunner(ctx context.Context, runnerName string,
 		select {
 		case <-cctx.Done():
 			// timeout
-			return fmt.Errorf("faied to to check existing runner in GitHub: timeout in %s", runner.MustRunningTime)
+			return fmt.Errorf("faied to to check existing runner in GitHub: timeout in %s", mustRunningTime)
 		case <-ticker.C:
 			if _, err := gh.ExistGitHubRunner(cctx, client, owner, repo, runnerName); err == nil {
 				// success to register runner to GitHub
@@ -0,0 +1,108 @@
+package starter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/datastore/memory"
+)
+
+func TestResetRetry(t *testing.T) {
+	jobID := uuid.NewV4()
+	AddInstanceRetryCount.Store(jobID, 3)
+	wakeup := make(chan struct{})
+	backoffWakeup.Store(jobID, wakeup)
+
+	if got := GetRetryCount(jobID); got != 3 {
+		t.Fatalf("want retry count 3, but got %d", got)
+	}
+
+	ResetRetry(jobID)
+	select {
+	case <-wakeup:
+	default:
+		t.Fatalf("job in backoff must be woken up")
+	}
+	if got := GetRetryCount(jobID); got != 0 {
+		t.Fatalf("want retry count 0, but got %d", got)
+	}
+
+	// not in backoff
+	ResetRetry(jobID)
+}
+
+func TestIsProcessing(t *testing.T) {
+	jobID := uuid.NewV4()
+	if IsProcessing(jobID) {
+		t.Fatalf("job not in progress must not be processing")
+	}
+
+	inProgress.Store(jobID, struct{}{})
+	defer inProgress.Delete(jobID)
+	if !IsProcessing(jobID) {
+		t.Fatalf("job in progress must be processing")
+	}
+
+	backoffWakeup.Store(jobID, make(chan struct{}))
+	defer backoffWakeup.Delete(jobID)
+	if IsProcessing(jobID) {
+		t.Fatalf("job waiting for backoff must not be processing")
+	}
+}
+
+func TestStarter_isProvisioningPaused(t *testing.T) {
+	ctx := context.Background()
+	ds, err := memory.New()
+	if err != nil {
+		t.Fatalf("failed to create datastore: %+v", err)
+	}
+	s := &Starter{ds: ds}
+
+	active, suspended := uuid.NewV4(), uuid.NewV4()
+	for id, status := range map[uuid.UUID]datastore.TargetStatus{active: datastore.TargetStatusActive, suspended: datastore.TargetStatusSuspend} {
+		if err := ds.CreateTarget(ctx, datastore.Target{UUID: id, Scope: id.String(), Status: status}); err != nil {
+			t.Fatalf("failed to create target: %+v", err)
+		}
+	}
+
+	check := func(targetID uuid.UUID, want bool) {
+		t.Helper()
+		got, err := s.isProvisioningPaused(ctx, datastore.Job{UUID: uuid.NewV4(), TargetID: targetID})
+		if err != nil {
+			t.Fatalf("failed to check provisioning is paused: %+v", err)
+		}
+		if got != want {
+			t.Errorf("want paused %t (target: %s), but got %t", want, targetID, got)
+		}
+	}
+	check(active, false)
+	check(suspended, true)
+
+	// scheduled window in future does not pause
+	now := time.Now().UTC()
+	if err := ds.CreateMaintenanceWindow(ctx, datastore.MaintenanceWindow{UUID: uuid.NewV4(), StartAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("failed to create maintenance window: %+v", err)
+	}
+	check(active, false)
+
+	if err := ds.CreateMaintenanceWindow(ctx, datastore.MaintenanceWindow{UUID: uuid.NewV4(), StartAt: now.Add(-time.Minute)}); err != nil {
+		t.Fatalf("failed to create maintenance window: %+v", err)
+	}
+	check(active, true)
+
+	// jobs are kept in queue while maintenance
+	if err := ds.EnqueueJob(ctx, datastore.Job{UUID: uuid.NewV4(), TargetID: active}); err != nil {
+		t.Fatalf("failed to enqueue job: %+v", err)
+	}
+	ch := make(chan datastore.Job, 1)
+	if err := s.dispatcher(ctx, ch); err != nil {
+		t.Fatalf("failed to dispatch: %+v", err)
+	}
+	if len(ch) != 0 {
+		t.Fatalf("job must not be dispatched in maintenance")
+	}
+}
@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is name of tracer in myshoes
+const TracerName = "github.com/whywaita/myshoes"
+
+// ServiceName is service.name of resource
+const ServiceName = "myshoes"
+
+// Attribute keys of spans
+const (
+	AttributeTargetID   = attribute.Key("myshoes.target_id")
+	AttributeJobID      = attribute.Key("myshoes.job_id")
+	AttributeRunnerName = attribute.Key("myshoes.runner_name")
+	AttributeDeliveryID = attribute.Key("github.delivery_id")
+	AttributeEventType  = attribute.Key("github.event_type")
+	AttributeRunID      = attribute.Key("github.run_id")
+	AttributeGitHubJob  = attribute.Key("github.job_id")
+)
+
+var propagator = propagation.TraceContext{}
+
+// Init configure tracer provider that export spans to endpoint (e.g. http://localhost:4318) by OTLP over HTTP.
+// spans are not exported if endpoint is empty. returned function flush and stop exporting.
+func Init(ctx context.Context, endpoint string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagator)
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attribute.String("service.name", ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Start start span that is child of span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// RecordError record err in span and mark span as failed, do nothing if err is nil
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// TraceParent return W3C traceparent of span in ctx, return empty if ctx doesn't have valid span
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// ContextWithTraceParent return context that has span of traceparent as remote parent
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier{"traceparent": traceParent})
+}
+
+// TraceID return trace ID of span in ctx, return empty if ctx doesn't have valid span
+func TraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTraceParent(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	if got := TraceParent(context.Background()); got != "" {
+		t.Errorf("traceparent must be empty without span, but got %s", got)
+	}
+
+	ctx, span := Start(context.Background(), "HandleGitHubEvent")
+	traceParent := TraceParent(ctx)
+	span.End()
+	if traceParent == "" {
+		t.Fatalf("traceparent must not be empty in span")
+	}
+
+	// job is processed in other goroutine after enqueued
+	ctx, span = Start(ContextWithTraceParent(context.Background(), traceParent), "ProcessJob")
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("2 spans must be recorded, but got %d", len(spans))
+	}
+	if spans[1].Parent().SpanID() != spans[0].SpanContext().SpanID() {
+		t.Errorf("span of job must be child of span of webhook")
+	}
+	if got, want := TraceID(ctx), spans[0].SpanContext().TraceID().String(); got != want {
+		t.Errorf("trace ID must be %s, but got %s", want, got)
+	}
+}
@@ -0,0 +1,161 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/auth"
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/logger"
+
+	"goji.io/pat"
+)
+
+// APITokenCreateParam is parameter for create API token
+type APITokenCreateParam struct {
+	Name        string `json:"name"`
+	Role        string `json:"role"`         // admin or read
+	ScopePrefix string `json:"scope_prefix"` // owner of scope (e.g. "octocat", "team-a-*")
+}
+
+// UserAPIToken is format of API token for user
+type UserAPIToken struct {
+	UUID        uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Role        string     `json:"role"`
+	ScopePrefix string     `json:"scope_prefix"`
+	CreatedBy   string     `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+
+	// Token is only returned when created
+	Token string `json:"token,omitempty"`
+}
+
+// same as characters of GitHub owner, "*" suffix matches prefix
+var scopePrefixRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*\*?$`)
+
+func handleAPITokenCreate(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	ctx := r.Context()
+	input := APITokenCreateParam{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Logf(false, "failed to decode request body: %+v", err)
+		outputErrorMsg(w, http.StatusBadRequest, "json decode error")
+		return
+	}
+	if input.Name == "" {
+		outputErrorMsg(w, http.StatusBadRequest, "name must be set")
+		return
+	}
+	if _, err := auth.ParseRole(input.Role); err != nil {
+		outputErrorMsg(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !scopePrefixRegexp.MatchString(input.ScopePrefix) {
+		outputErrorMsg(w, http.StatusBadRequest, fmt.Sprintf("scope_prefix %q is invalid, must be owner name or prefix of owner name with \"*\"", input.ScopePrefix))
+		return
+	}
+
+	token, err := auth.GenerateToken()
+	if err != nil {
+		logger.Logf(false, "failed to generate API token: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "failed to generate token")
+		return
+	}
+	t := datastore.APIToken{
+		UUID:        uuid.NewV4(),
+		Name:        input.Name,
+		TokenHash:   auth.HashToken(token),
+		Role:        input.Role,
+		ScopePrefix: input.ScopePrefix,
+		CreatedBy:   principalName(r),
+	}
+	if err := ds.CreateAPIToken(ctx, t); err != nil {
+		logger.Logf(false, "failed to create API token: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore create error")
+		return
+	}
+	recordAuditLog(r, ds, auditActionAPITokenCreate, t.UUID.String(), datastore.AuditChanges{
+		{Field: "name", Old: "", New: t.Name},
+		{Field: "role", Old: "", New: t.Role},
+		{Field: "scope_prefix", Old: "", New: t.ScopePrefix},
+	})
+
+	created, err := ds.GetAPITokenByHash(ctx, t.TokenHash)
+	if err != nil {
+		logger.Logf(false, "failed to get recently API token: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore get error")
+		return
+	}
+	ut := sanitizeAPIToken(*created)
+	ut.Token = token
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(ut)
+}
+
+func handleAPITokenList(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	ts, err := ds.ListAPITokens(r.Context())
+	if err != nil {
+		logger.Logf(false, "failed to retrieve list of API token: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+
+	uts := []UserAPIToken{}
+	for _, t := range ts {
+		uts = append(uts, sanitizeAPIToken(t))
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(uts)
+}
+
+func handleAPITokenRevoke(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	tokenID, err := uuid.FromString(pat.Param(r, "id"))
+	if err != nil {
+		logger.Logf(false, "failed to parse API token id: %+v", err)
+		outputErrorMsg(w, http.StatusBadRequest, "incorrect API token id")
+		return
+	}
+
+	err = ds.RevokeAPIToken(r.Context(), tokenID, time.Now().UTC())
+	switch {
+	case errors.Is(err, datastore.ErrNotFound):
+		outputErrorMsg(w, http.StatusNotFound, "API token is not found or already revoked")
+		return
+	case err != nil:
+		logger.Logf(false, "failed to revoke API token: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore update error")
+		return
+	}
+	recordAuditLog(r, ds, auditActionAPITokenRevoke, tokenID.String(), datastore.AuditChanges{
+		{Field: "revoked", Old: false, New: true},
+	})
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func sanitizeAPIToken(t datastore.APIToken) UserAPIToken {
+	ut := UserAPIToken{
+		UUID:        t.UUID,
+		Name:        t.Name,
+		Role:        t.Role,
+		ScopePrefix: t.ScopePrefix,
+		CreatedBy:   t.CreatedBy,
+		CreatedAt:   t.CreatedAt,
+	}
+	if t.RevokedAt.Valid {
+		ut.RevokedAt = &t.RevokedAt.Time
+	}
+	return ut
+}
@@ -0,0 +1,133 @@
+package web_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/internal/testutils"
+	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/web"
+)
+
+func Test_scopedAPIToken(t *testing.T) {
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	oldAuth := config.Config.APIAuth
+	defer func() { config.Config.APIAuth = oldAuth }()
+	config.Config.APIAuth = config.APIAuth{
+		Tokens: []config.APICredential{
+			{Name: "ops", Role: "admin", Secret: "admin-token"},
+		},
+	}
+
+	ts := httptest.NewServer(web.NewMux(testDatastore))
+	defer ts.Close()
+
+	inScope, outOfScope := uuid.NewV4(), uuid.NewV4()
+	for id, scope := range map[uuid.UUID]string{inScope: "team-a-backend/api", outOfScope: "team-b"} {
+		if err := testDatastore.CreateTarget(context.Background(), datastore.Target{
+			UUID:           id,
+			Scope:          scope,
+			TokenExpiredAt: testTime,
+			ResourceType:   datastore.ResourceTypeNano,
+		}); err != nil {
+			t.Fatalf("failed to create target: %+v", err)
+		}
+	}
+
+	do := func(method, path, token, body string) ([]byte, int) {
+		t.Helper()
+		req, err := http.NewRequest(method, ts.URL+path, bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatalf("failed to create request: %+v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to request: %+v", err)
+		}
+		return parseResponse(resp)
+	}
+
+	content, code := do(http.MethodPost, "/api_token", "admin-token", `{"name": "team-a", "role": "admin", "scope_prefix": "team-a-*"}`)
+	if code != http.StatusCreated {
+		t.Fatalf("must be response statuscode is 201, but got %d: %s", code, string(content))
+	}
+	var created web.UserAPIToken
+	if err := json.Unmarshal(content, &created); err != nil {
+		t.Fatalf("failed to unmarshal response: %+v", err)
+	}
+	if created.Token == "" || created.CreatedBy != "token:ops" {
+		t.Fatalf("token and created_by must be returned, but got %+v", created)
+	}
+	scoped := created.Token
+
+	content, code = do(http.MethodGet, "/target", scoped, "")
+	if code != http.StatusOK {
+		t.Fatalf("must be response statuscode is 200, but got %d: %s", code, string(content))
+	}
+	var targets []web.UserTarget
+	if err := json.Unmarshal(content, &targets); err != nil {
+		t.Fatalf("failed to unmarshal response: %+v", err)
+	}
+	if len(targets) != 1 || targets[0].UUID != inScope {
+		t.Fatalf("list must contain only target in scope, but got %+v", targets)
+	}
+
+	for _, id := range []uuid.UUID{inScope, outOfScope} {
+		if err := testDatastore.CreateRunner(context.Background(), datastore.Runner{
+			UUID:           uuid.NewV4(),
+			ShoesType:      "shoes-test",
+			TargetID:       id,
+			CloudID:        "mycloud-uuid",
+			ResourceType:   datastore.ResourceTypeNano,
+			RequestWebhook: "{}",
+		}); err != nil {
+			t.Fatalf("failed to create runner: %+v", err)
+		}
+	}
+	// wait for runners to be running in period
+	time.Sleep(1 * time.Second)
+	content, code = do(http.MethodGet, "/usage", scoped, "")
+	if code != http.StatusOK {
+		t.Fatalf("must be response statuscode is 200, but got %d: %s", code, string(content))
+	}
+	var usages []datastore.Usage
+	if err := json.Unmarshal(content, &usages); err != nil {
+		t.Fatalf("failed to unmarshal response: %+v", err)
+	}
+	if len(usages) != 1 || usages[0].Target != "team-a-backend/api" {
+		t.Fatalf("usage must contain only target in scope, but got %+v", usages)
+	}
+
+	tests := []struct {
+		method   string
+		path     string
+		token    string
+		wantCode int
+	}{
+		{method: http.MethodGet, path: "/target/" + inScope.String(), token: scoped, wantCode: http.StatusOK},
+		{method: http.MethodGet, path: "/target/" + outOfScope.String(), token: scoped, wantCode: http.StatusForbidden},
+		{method: http.MethodDelete, path: "/target/" + outOfScope.String(), token: scoped, wantCode: http.StatusForbidden},
+		{method: http.MethodGet, path: "/script_template", token: scoped, wantCode: http.StatusForbidden},
+		{method: http.MethodGet, path: "/api_token", token: scoped, wantCode: http.StatusForbidden},
+		{method: http.MethodDelete, path: "/api_token/" + created.UUID.String(), token: "admin-token", wantCode: http.StatusNoContent},
+		{method: http.MethodDelete, path: "/api_token/" + created.UUID.String(), token: "admin-token", wantCode: http.StatusNotFound},
+		{method: http.MethodGet, path: "/target", token: scoped, wantCode: http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		content, code := do(test.method, test.path, test.token, "")
+		if code != test.wantCode {
+			t.Errorf("must be response statuscode is %d, but got %d (%s %s): %s", test.wantCode, code, test.method, test.path, string(content))
+		}
+	}
+}
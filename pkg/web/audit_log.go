@@ -0,0 +1,160 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/r3labs/diff/v2"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/logger"
+)
+
+// actions of audit log
+const (
+	auditActionTargetCreate      = "target.create"
+	auditActionTargetUpdate      = "target.update"
+	auditActionTargetDelete      = "target.delete"
+	auditActionTargetPause       = "target.pause"
+	auditActionTargetResume      = "target.resume"
+	auditActionConfigDebug       = "config.debug"
+	auditActionConfigStrict      = "config.strict"
+	auditActionConfigUpdate      = "config.update"
+	auditActionRunnerDelete      = "runner.delete"
+	auditActionJobDelete         = "job.delete"
+	auditActionJobRetry          = "job.retry"
+	auditActionAPITokenCreate    = "api_token.create"
+	auditActionAPITokenRevoke    = "api_token.revoke"
+	auditActionMaintenanceCreate = "maintenance.create"
+	auditActionMaintenanceDelete = "maintenance.delete"
+)
+
+// recordAuditLog store an audit log of request.
+// failure of storing is only logged, an operation that already done is not rolled back.
+func recordAuditLog(r *http.Request, ds datastore.Datastore, action, resourceID string, changes datastore.AuditChanges) {
+	resourceType, _, _ := strings.Cut(action, ".")
+	if changes == nil {
+		changes = datastore.AuditChanges{}
+	}
+	l := datastore.AuditLog{
+		UUID:         uuid.NewV4(),
+		Actor:        principalName(r),
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Changes:      changes,
+		CreatedAt:    time.Now().UTC(),
+	}
+	logger.Logf(false, "audit: %s (id: %s, by: %s, changes: %+v)", l.Action, l.ResourceID, l.Actor, l.Changes)
+
+	if err := ds.CreateAuditLog(r.Context(), l); err != nil {
+		logger.Logf(false, "failed to store audit log: %+v", err)
+	}
+}
+
+// recordTargetAuditLog store an audit log with changed fields of target
+func recordTargetAuditLog(r *http.Request, ds datastore.Datastore, action string, oldTarget, newTarget *UserTarget) {
+	changes, err := targetChanges(oldTarget, newTarget)
+	if err != nil {
+		logger.Logf(false, "failed to get changes of target: %+v", err)
+	}
+
+	var targetID uuid.UUID
+	switch {
+	case newTarget != nil:
+		targetID = newTarget.UUID
+	case oldTarget != nil:
+		targetID = oldTarget.UUID
+	}
+	recordAuditLog(r, ds, action, targetID.String(), changes)
+}
+
+// targetChanges return changed fields between old and new target, a nil target is treated as empty.
+// id and timestamps are ignored because these are not changed by user.
+func targetChanges(oldTarget, newTarget *UserTarget) (datastore.AuditChanges, error) {
+	if oldTarget == nil {
+		oldTarget = &UserTarget{}
+	}
+	if newTarget == nil {
+		newTarget = &UserTarget{}
+	}
+
+	return diffChanges(*oldTarget, *newTarget, "id", "created_at", "updated_at")
+}
+
+// diffChanges return changed fields between structs, field name is json tag
+func diffChanges(a, b interface{}, ignoredFields ...string) (datastore.AuditChanges, error) {
+	ignored := map[string]struct{}{}
+	for _, f := range ignoredFields {
+		ignored[f] = struct{}{}
+	}
+	changelog, err := diff.Diff(a, b, diff.TagName("json"), diff.Filter(func(path []string, parent reflect.Type, field reflect.StructField) bool {
+		_, ok := ignored[path[0]]
+		return !ok
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check diff: %w", err)
+	}
+
+	changes := datastore.AuditChanges{}
+	for _, cl := range changelog {
+		changes = append(changes, datastore.AuditChange{
+			Field: strings.Join(cl.Path, "."),
+			Old:   cl.From,
+			New:   cl.To,
+		})
+	}
+	return changes, nil
+}
+
+func handleAuditLogList(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	lq, err := parseListQuery(r)
+	if err != nil {
+		outputErrorMsg(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	q := r.URL.Query()
+	format := q.Get("format")
+	if format != "" && format != "json" && format != "jsonl" {
+		outputErrorMsg(w, http.StatusBadRequest, "format must be json or jsonl")
+		return
+	}
+
+	logs, err := ds.ListAuditLogs(r.Context(), datastore.AuditLogFilter{
+		Actor:        q.Get("actor"),
+		Action:       q.Get("action"),
+		ResourceType: q.Get("resource_type"),
+		ResourceID:   q.Get("resource_id"),
+		Since:        lq.Since,
+		Until:        lq.Until,
+		Limit:        lq.Limit,
+		Offset:       lq.Offset,
+	})
+	if err != nil {
+		logger.Logf(false, "failed to retrieve list of audit log: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+
+	if format == "jsonl" {
+		w.Header().Set("Content-Type", "application/x-ndjson;charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		enc := json.NewEncoder(w)
+		for _, l := range logs {
+			enc.Encode(l)
+		}
+		return
+	}
+
+	if logs == nil {
+		logs = []datastore.AuditLog{}
+	}
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(logs)
+}
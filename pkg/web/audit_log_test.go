@@ -0,0 +1,98 @@
+package web_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/internal/testutils"
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+func Test_handleAuditLogList(t *testing.T) {
+	testURL := testutils.GetTestURL()
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	targetID := uuid.NewV4()
+	if err := testDatastore.CreateTarget(context.Background(), datastore.Target{
+		UUID:           targetID,
+		Scope:          "octocat",
+		TokenExpiredAt: testTime,
+		ResourceType:   datastore.ResourceTypeNano,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+
+	for _, req := range []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{method: http.MethodPost, path: "/target/" + targetID.String(), body: `{"scope": "octocat", "resource_type": "micro"}`},
+		{method: http.MethodDelete, path: "/target/" + targetID.String()},
+		{method: http.MethodPost, path: "/config/debug", body: `{"debug": true}`},
+		{method: http.MethodPost, path: "/config/debug", body: `{"debug": false}`},
+	} {
+		r, err := http.NewRequest(req.method, testURL+req.path, bytes.NewBufferString(req.body))
+		if err != nil {
+			t.Fatalf("failed to create request: %+v", err)
+		}
+		resp, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatalf("failed to request: %+v", err)
+		}
+		if content, code := parseResponse(resp); code >= http.StatusBadRequest {
+			t.Fatalf("failed to %s %s (status: %d): %s", req.method, req.path, code, string(content))
+		}
+	}
+
+	resp, err := http.Get(testURL + "/audit_log?resource_type=target&resource_id=" + targetID.String())
+	if err != nil {
+		t.Fatalf("failed to get audit logs: %+v", err)
+	}
+	content, code := parseResponse(resp)
+	if code != http.StatusOK {
+		t.Fatalf("must be response statuscode is 200, but got %d: %s", code, string(content))
+	}
+	var logs []datastore.AuditLog
+	if err := json.Unmarshal(content, &logs); err != nil {
+		t.Fatalf("failed to unmarshal response: %+v", err)
+	}
+	if len(logs) != 2 || logs[0].Action != "target.delete" || logs[1].Action != "target.update" {
+		t.Fatalf("want target.delete and target.update, but got %+v", logs)
+	}
+	want := datastore.AuditChange{Field: "resource_type", Old: "nano", New: "micro"}
+	if len(logs[1].Changes) != 1 || logs[1].Changes[0] != want {
+		t.Errorf("want changes %+v, but got %+v", want, logs[1].Changes)
+	}
+	if logs[1].Actor != "anonymous" {
+		t.Errorf("want actor anonymous, but got %s", logs[1].Actor)
+	}
+
+	resp, err = http.Get(testURL + "/audit_log?action=config.debug&format=jsonl")
+	if err != nil {
+		t.Fatalf("failed to get audit logs: %+v", err)
+	}
+	content, code = parseResponse(resp)
+	if code != http.StatusOK {
+		t.Fatalf("must be response statuscode is 200, but got %d: %s", code, string(content))
+	}
+	var lines int
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		var l datastore.AuditLog
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			t.Fatalf("each line must be JSON: %+v", err)
+		}
+		lines++
+	}
+	if lines != 2 {
+		t.Errorf("want 2 lines, but got %d: %s", lines, string(content))
+	}
+}
@@ -0,0 +1,167 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/whywaita/myshoes/pkg/auth"
+	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/logger"
+	"github.com/whywaita/myshoes/pkg/mirror"
+)
+
+// newAuthenticator create authenticator from config, return nil if authentication is disabled
+func newAuthenticator(c config.APIAuth, ds datastore.Datastore) (auth.Authenticator, error) {
+	if !c.Enabled() {
+		return nil, nil
+	}
+
+	var chain auth.Chain
+	if len(c.Tokens) != 0 {
+		tokens, err := toAuthCredentials(c.Tokens)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API token: %w", err)
+		}
+		chain = append(chain, auth.NewTokenAuthenticator(tokens))
+	}
+	chain = append(chain, auth.NewScopedTokenAuthenticator(lookupScopedToken(ds)))
+	if len(c.HMACKeys) != 0 {
+		keys, err := toAuthCredentials(c.HMACKeys)
+		if err != nil {
+			return nil, fmt.Errorf("invalid HMAC key: %w", err)
+		}
+		chain = append(chain, auth.NewHMACAuthenticator(keys))
+	}
+	if c.OIDCJWKSFile != "" {
+		a, err := auth.NewOIDCAuthenticator(auth.OIDCConfig{
+			JWKSFile:  c.OIDCJWKSFile,
+			Issuer:    c.OIDCIssuer,
+			Audience:  c.OIDCAudience,
+			RoleClaim: c.OIDCRoleClaim,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OIDC authenticator: %w", err)
+		}
+		chain = append(chain, a)
+	}
+
+	return chain, nil
+}
+
+func lookupScopedToken(ds datastore.Datastore) auth.ScopedTokenLookup {
+	return func(ctx context.Context, tokenHash string) (*auth.Principal, error) {
+		t, err := ds.GetAPITokenByHash(ctx, tokenHash)
+		switch {
+		case errors.Is(err, datastore.ErrNotFound):
+			return nil, auth.ErrTokenNotFound
+		case err != nil:
+			return nil, fmt.Errorf("failed to get API token: %w", err)
+		}
+
+		role, err := auth.ParseRole(t.Role)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse role of API token (id: %s): %w", t.UUID, err)
+		}
+		return &auth.Principal{
+			Name:          t.Name,
+			Role:          role,
+			Method:        "scoped_token",
+			ScopePrefixes: []string{t.ScopePrefix},
+		}, nil
+	}
+}
+
+func toAuthCredentials(in []config.APICredential) ([]auth.Credential, error) {
+	var credentials []auth.Credential
+	for _, c := range in {
+		role, err := auth.ParseRole(c.Role)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse role (name: %s): %w", c.Name, err)
+		}
+		credentials = append(credentials, auth.Credential{
+			Name:   c.Name,
+			Secret: c.Secret,
+			Role:   role,
+		})
+	}
+	return credentials, nil
+}
+
+// requiredRole return role that need to access the request, return false if the request is public.
+func requiredRole(r *http.Request) (auth.Role, bool) {
+	switch {
+	case r.URL.Path == "/healthz",
+		r.URL.Path == "/metrics",
+		r.URL.Path == "/github/events",                 // verified by signature of GitHub
+		strings.HasPrefix(r.URL.Path, mirror.Path+"/"): // runners download without credentials
+		return "", false
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		return auth.RoleRead, true
+	}
+	return auth.RoleAdmin, true
+}
+
+// isScopedPath return true if path can be accessed by scoped principal, handlers of these filter by scope
+func isScopedPath(p string) bool {
+	return p == "/target" || strings.HasPrefix(p, "/target/") || p == "/usage"
+}
+
+// canAccessTarget return false if principal of request is restricted to other scopes
+func canAccessTarget(r *http.Request, scope string) bool {
+	p := auth.PrincipalFromContext(r.Context())
+	return p == nil || p.CanAccessScope(scope)
+}
+
+// principalName return name of principal for audit log
+func principalName(r *http.Request) string {
+	p := auth.PrincipalFromContext(r.Context())
+	if p == nil {
+		return "anonymous"
+	}
+	return fmt.Sprintf("%s:%s", p.Method, p.Name)
+}
+
+// authMiddleware authenticate and authorize a request to REST API
+func authMiddleware(a auth.Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			required, ok := requiredRole(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			p, err := a.Authenticate(r)
+			switch {
+			case errors.Is(err, auth.ErrNoCredentials):
+				w.Header().Set("WWW-Authenticate", `Bearer realm="myshoes"`)
+				outputErrorMsg(w, http.StatusUnauthorized, "authentication required")
+				return
+			case err != nil:
+				logger.Logf(false, "failed to authenticate (%s %s): %+v", r.Method, r.URL.Path, err)
+				w.Header().Set("WWW-Authenticate", `Bearer realm="myshoes", error="invalid_token"`)
+				outputErrorMsg(w, http.StatusUnauthorized, "invalid credentials")
+				return
+			}
+			if p.IsScoped() && !isScopedPath(r.URL.Path) {
+				logger.Logf(false, "permission denied, scoped token can access only targets and usage (name: %s, %s %s)", p.Name, r.Method, r.URL.Path)
+				outputErrorMsg(w, http.StatusForbidden, "scoped token can access only /target and /usage")
+				return
+			}
+			if !p.Role.Allows(required) {
+				logger.Logf(false, "permission denied (name: %s, role: %s, %s %s)", p.Name, p.Role, r.Method, r.URL.Path)
+				outputErrorMsg(w, http.StatusForbidden, fmt.Sprintf("role %s is not allowed to %s %s", p.Role, r.Method, r.URL.Path))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), p)))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package web_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/whywaita/myshoes/internal/testutils"
+	"github.com/whywaita/myshoes/pkg/auth"
+	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/web"
+)
+
+func Test_authMiddleware(t *testing.T) {
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	oldAuth := config.Config.APIAuth
+	defer func() { config.Config.APIAuth = oldAuth }()
+	config.Config.APIAuth = config.APIAuth{
+		Tokens: []config.APICredential{
+			{Name: "viewer", Role: "read", Secret: "read-token"},
+			{Name: "ops", Role: "admin", Secret: "admin-token"},
+		},
+		HMACKeys: []config.APICredential{
+			{Name: "key1", Role: "admin", Secret: "hmac-secret"},
+		},
+	}
+
+	ts := httptest.NewServer(web.NewMux(testDatastore))
+	defer ts.Close()
+
+	tests := []struct {
+		method   string
+		path     string
+		token    string
+		hmac     bool
+		wantCode int
+	}{
+		{method: http.MethodGet, path: "/healthz", wantCode: http.StatusOK},
+		{method: http.MethodGet, path: "/target", wantCode: http.StatusUnauthorized},
+		{method: http.MethodGet, path: "/target", token: "invalid", wantCode: http.StatusUnauthorized},
+		{method: http.MethodGet, path: "/target", token: "read-token", wantCode: http.StatusOK},
+		{method: http.MethodPost, path: "/config/debug", token: "read-token", wantCode: http.StatusForbidden},
+		{method: http.MethodPost, path: "/config/debug", token: "admin-token", wantCode: http.StatusNoContent},
+		{method: http.MethodPost, path: "/config/debug", hmac: true, wantCode: http.StatusNoContent},
+	}
+
+	for _, test := range tests {
+		req, err := http.NewRequest(test.method, ts.URL+test.path, bytes.NewBufferString(`{"debug": false}`))
+		if err != nil {
+			t.Fatalf("failed to create request: %+v", err)
+		}
+		if test.token != "" {
+			req.Header.Set("Authorization", "Bearer "+test.token)
+		}
+		if test.hmac {
+			if err := auth.SignRequest(req, "key1", "hmac-secret", time.Now()); err != nil {
+				t.Fatalf("failed to sign request: %+v", err)
+			}
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to request: %+v", err)
+		}
+		content, code := parseResponse(resp)
+		if code != test.wantCode {
+			t.Errorf("must be response statuscode is %d, but got %d (%s %s): %s", test.wantCode, code, test.method, test.path, string(content))
+		}
+	}
+}
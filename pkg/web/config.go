@@ -2,9 +2,11 @@ package web
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/datastore"
 	"github.com/whywaita/myshoes/pkg/logger"
 )
 
@@ -16,7 +18,16 @@ type inputConfigStrict struct {
 	Strict bool `json:"strict"`
 }
 
-func handleConfigDebug(w http.ResponseWriter, r *http.Request) {
+// ConfigPatchParam is parameter for update runtime config
+type ConfigPatchParam struct {
+	config.RuntimePatch
+
+	// Version is version of config that client read, update is rejected if config is already updated by other.
+	// not checked if zero.
+	Version int64 `json:"version,omitempty"`
+}
+
+func handleConfigDebug(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
 	i := inputConfigDebug{}
 
 	if err := json.NewDecoder(r.Body).Decode(&i); err != nil {
@@ -25,12 +36,22 @@ func handleConfigDebug(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	config.Config.Debug = i.Debug
+	logLevel := config.LogLevelInfo
+	if i.Debug {
+		logLevel = config.LogLevelDebug
+	}
+	old := config.GetRuntime().IsDebug()
+	if _, ok := updateRuntimeConfig(w, r, ds, config.RuntimePatch{LogLevel: &logLevel}, 0); !ok {
+		return
+	}
 	logger.Logf(false, "switch debug mode to %t", i.Debug)
+	recordAuditLog(r, ds, auditActionConfigDebug, "debug", datastore.AuditChanges{
+		{Field: "debug", Old: old, New: i.Debug},
+	})
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func handleConfigStrict(w http.ResponseWriter, r *http.Request) {
+func handleConfigStrict(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
 	i := inputConfigStrict{}
 
 	if err := json.NewDecoder(r.Body).Decode(&i); err != nil {
@@ -39,7 +60,68 @@ func handleConfigStrict(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	config.Config.Strict = i.Strict
+	old := config.GetRuntime().Strict
+	if _, ok := updateRuntimeConfig(w, r, ds, config.RuntimePatch{Strict: &i.Strict}, 0); !ok {
+		return
+	}
 	logger.Logf(false, "switch strict mode to %t", i.Strict)
+	recordAuditLog(r, ds, auditActionConfigStrict, "strict", datastore.AuditChanges{
+		{Field: "strict", Old: old, New: i.Strict},
+	})
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func handleConfigGet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(config.GetRuntime())
+}
+
+func handleConfigPatch(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	var input ConfigPatchParam
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&input); err != nil {
+		logger.Logf(false, "failed to decode request body: %+v", err)
+		outputErrorMsg(w, http.StatusBadRequest, "json decode error")
+		return
+	}
+
+	old := config.GetRuntime()
+	updated, ok := updateRuntimeConfig(w, r, ds, input.RuntimePatch, input.Version)
+	if !ok {
+		return
+	}
+
+	changes, err := diffChanges(old, updated, "version")
+	if err != nil {
+		logger.Logf(false, "failed to get changes of runtime config: %+v", err)
+	}
+	logger.Logf(false, "runtime config is updated (version: %d)", updated.Version)
+	recordAuditLog(r, ds, auditActionConfigUpdate, "runtime", changes)
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(updated)
+}
+
+// updateRuntimeConfig apply patch to runtime config and save it, write error response and return false if failed
+func updateRuntimeConfig(w http.ResponseWriter, r *http.Request, ds datastore.Datastore, patch config.RuntimePatch, expectVersion int64) (config.Runtime, bool) {
+	updated, err := config.UpdateRuntime(patch, expectVersion, func(rc config.Runtime) error {
+		return datastore.SaveRuntimeConfig(r.Context(), ds, rc, principalName(r))
+	})
+	switch {
+	case errors.Is(err, config.ErrInvalidRuntime):
+		outputErrorMsg(w, http.StatusBadRequest, err.Error())
+		return updated, false
+	case errors.Is(err, config.ErrRuntimeVersionConflict):
+		outputErrorMsg(w, http.StatusConflict, err.Error())
+		return updated, false
+	case err != nil:
+		logger.Logf(false, "failed to update runtime config: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore update error")
+		return updated, false
+	}
+
+	return updated, true
+}
@@ -0,0 +1,78 @@
+package web_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/whywaita/myshoes/internal/testutils"
+	"github.com/whywaita/myshoes/pkg/config"
+)
+
+func Test_handleConfigPatch(t *testing.T) {
+	testURL := testutils.GetTestURL()
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	original := config.GetRuntime()
+	defer config.SetRuntime(original)
+
+	tests := []struct {
+		body     string
+		wantCode int
+	}{
+		{body: `{"max_connections_to_backend": 10, "must_running_time": "10m"}`, wantCode: http.StatusOK},
+		{body: `{"max_connections_to_backend": 0}`, wantCode: http.StatusBadRequest},
+		{body: `{"must_running_time": "ten minutes"}`, wantCode: http.StatusBadRequest},
+		{body: `{"unknown_field": 1}`, wantCode: http.StatusBadRequest},
+		{body: `{"log_level": "debug", "version": 999}`, wantCode: http.StatusConflict},
+	}
+	for _, test := range tests {
+		req, err := http.NewRequest(http.MethodPatch, testURL+"/config", bytes.NewBufferString(test.body))
+		if err != nil {
+			t.Fatalf("failed to create request: %+v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to request: %+v", err)
+		}
+		content, code := parseResponse(resp)
+		if code != test.wantCode {
+			t.Fatalf("must be response statuscode is %d, but got %d (%s): %s", test.wantCode, code, test.body, string(content))
+		}
+	}
+
+	resp, err := http.Get(testURL + "/config")
+	if err != nil {
+		t.Fatalf("failed to request: %+v", err)
+	}
+	content, code := parseResponse(resp)
+	if code != http.StatusOK {
+		t.Fatalf("must be response statuscode is 200, but got %d: %s", code, string(content))
+	}
+	var got config.Runtime
+	if err := json.Unmarshal(content, &got); err != nil {
+		t.Fatalf("failed to unmarshal response: %+v", err)
+	}
+	if got.Version != original.Version+1 || got.MaxConnectionsToBackend != 10 || time.Duration(got.MustRunningTime) != 10*time.Minute {
+		t.Errorf("runtime config is not updated: %+v", got)
+	}
+	if got.LogLevel != original.LogLevel {
+		t.Errorf("log_level must not be changed by conflicted request, but got %s", got.LogLevel)
+	}
+
+	saved, err := testDatastore.GetRuntimeConfig(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get saved runtime config: %+v", err)
+	}
+	var savedRuntime config.Runtime
+	if err := json.Unmarshal([]byte(saved.Config), &savedRuntime); err != nil {
+		t.Fatalf("failed to unmarshal saved runtime config: %+v", err)
+	}
+	if savedRuntime != got {
+		t.Errorf("saved runtime config must be same as current, saved: %+v, current: %+v", savedRuntime, got)
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"time"
 
 	"github.com/whywaita/myshoes/pkg/config"
 	"github.com/whywaita/myshoes/pkg/datastore"
 	"github.com/whywaita/myshoes/pkg/logger"
+	"github.com/whywaita/myshoes/pkg/mirror"
 
 	goji "goji.io"
 	"goji.io/pat"
@@ -19,6 +21,14 @@ import (
 func NewMux(ds datastore.Datastore) *goji.Mux {
 	mux := goji.NewMux()
 
+	authenticator, err := newAuthenticator(config.Config.APIAuth, ds)
+	if err != nil {
+		log.Panicf("failed to create authenticator for REST API: %+v", err)
+	}
+	if authenticator != nil {
+		mux.Use(authMiddleware(authenticator))
+	}
+
 	mux.HandleFunc(pat.Get("/healthz"), func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json;charset=utf-8")
 		w.WriteHeader(http.StatusOK)
@@ -58,15 +68,139 @@ func NewMux(ds datastore.Datastore) *goji.Mux {
 		apacheLogging(r)
 		handleTargetDelete(w, r, ds)
 	})
+	mux.HandleFunc(pat.Get("/target/:id/runners"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleTargetRunnerList(w, r, ds)
+	})
+	mux.HandleFunc(pat.Get("/target/:id/status-history"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleTargetStatusHistoryList(w, r, ds)
+	})
+	mux.HandleFunc(pat.Post("/target/:id/pause"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleTargetPause(w, r, ds)
+	})
+	mux.HandleFunc(pat.Post("/target/:id/resume"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleTargetResume(w, r, ds)
+	})
+
+	// REST API for script templates
+	mux.HandleFunc(pat.Post("/script_template"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleScriptTemplateCreate(w, r, ds)
+	})
+	mux.HandleFunc(pat.Get("/script_template"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleScriptTemplateList(w, r, ds)
+	})
+	mux.HandleFunc(pat.Get("/script_template/:name"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleScriptTemplateRead(w, r, ds)
+	})
+	mux.HandleFunc(pat.Post("/script_template/:name"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleScriptTemplateUpdate(w, r, ds)
+	})
+	mux.HandleFunc(pat.Delete("/script_template/:name"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleScriptTemplateDelete(w, r, ds)
+	})
+
+	// REST API for runners
+	mux.HandleFunc(pat.Get("/runner"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleRunnerList(w, r, ds)
+	})
+	mux.HandleFunc(pat.Get("/runner/:id"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleRunnerRead(w, r, ds)
+	})
+	mux.HandleFunc(pat.Delete("/runner/:id"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleRunnerDelete(w, r, ds)
+	})
+
+	// REST API for jobs
+	mux.HandleFunc(pat.Get("/job"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleJobList(w, r, ds)
+	})
+	mux.HandleFunc(pat.Get("/job/:id"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleJobRead(w, r, ds)
+	})
+	mux.HandleFunc(pat.Delete("/job/:id"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleJobDelete(w, r, ds)
+	})
+	mux.HandleFunc(pat.Post("/job/:id/retry"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleJobRetry(w, r, ds)
+	})
+
+	// REST API for scoped API tokens
+	mux.HandleFunc(pat.Post("/api_token"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleAPITokenCreate(w, r, ds)
+	})
+	mux.HandleFunc(pat.Get("/api_token"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleAPITokenList(w, r, ds)
+	})
+	mux.HandleFunc(pat.Delete("/api_token/:id"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleAPITokenRevoke(w, r, ds)
+	})
+
+	// REST API for maintenance windows
+	mux.HandleFunc(pat.Get("/maintenance"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleMaintenanceRead(w, r, ds)
+	})
+	mux.HandleFunc(pat.Post("/maintenance"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleMaintenanceCreate(w, r, ds)
+	})
+	mux.HandleFunc(pat.Delete("/maintenance/:id"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleMaintenanceDelete(w, r, ds)
+	})
+
+	// REST API for audit logs
+	mux.HandleFunc(pat.Get("/audit_log"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleAuditLogList(w, r, ds)
+	})
+
+	// REST API for usage of runners
+	mux.HandleFunc(pat.Get("/usage"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleUsage(w, r, ds)
+	})
+
+	// runner binary mirror
+	mux.HandleFunc(pat.Get(mirror.Path+"/:version/:file"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleRunnerMirror(w, r)
+	})
 
 	// Config endpoints
+	mux.HandleFunc(pat.Get("/config"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleConfigGet(w, r)
+	})
+	mux.HandleFunc(pat.Patch("/config"), func(w http.ResponseWriter, r *http.Request) {
+		apacheLogging(r)
+		handleConfigPatch(w, r, ds)
+	})
 	mux.HandleFunc(pat.Post("/config/debug"), func(w http.ResponseWriter, r *http.Request) {
 		apacheLogging(r)
-		handleConfigDebug(w, r)
+		handleConfigDebug(w, r, ds)
 	})
 	mux.HandleFunc(pat.Post("/config/strict"), func(w http.ResponseWriter, r *http.Request) {
 		apacheLogging(r)
-		handleConfigStrict(w, r)
+		handleConfigStrict(w, r, ds)
 	})
 
 	// metrics endpoint
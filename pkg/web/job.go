@@ -0,0 +1,187 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/gh"
+	"github.com/whywaita/myshoes/pkg/logger"
+	"github.com/whywaita/myshoes/pkg/starter"
+
+	"goji.io/pat"
+)
+
+// UserJob is format of job for user
+type UserJob struct {
+	UUID       uuid.UUID `json:"id"`
+	GHEDomain  string    `json:"ghe_domain"`
+	Repository string    `json:"repository"`
+	TargetID   uuid.UUID `json:"target_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// UserJobDetail is format of job detail for user
+type UserJobDetail struct {
+	UserJob
+	Workflow   *gh.WorkflowJobInfo `json:"workflow"` // null if webhook is unsupported format
+	RetryCount int                 `json:"retry_count"`
+	InProgress bool                `json:"in_progress"`
+}
+
+func handleJobList(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	ctx := r.Context()
+	lq, err := parseListQuery(r)
+	if err != nil {
+		outputErrorMsg(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	jobs, err := ds.ListJobsWithFilter(ctx, datastore.JobFilter{
+		TargetID:   lq.TargetID,
+		Repository: lq.Repository,
+		Since:      lq.Since,
+		Until:      lq.Until,
+		Limit:      lq.Limit,
+		Offset:     lq.Offset,
+	})
+	if err != nil {
+		logger.Logf(false, "failed to retrieve list of job: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+
+	ujs := []UserJob{}
+	for _, j := range jobs {
+		ujs = append(ujs, sanitizeJob(j))
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ujs)
+}
+
+func sanitizeJob(j datastore.Job) UserJob {
+	return UserJob{
+		UUID:       j.UUID,
+		GHEDomain:  j.GHEDomain.String,
+		Repository: j.Repository,
+		TargetID:   j.TargetID,
+		CreatedAt:  j.CreatedAt,
+		UpdatedAt:  j.UpdatedAt,
+	}
+}
+
+func handleJobRead(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	j, ok := getReqJob(w, r, ds)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toUserJobDetail(*j))
+}
+
+// StarterIsProcessingFunc is function of check a job is processing by starter (for testing)
+var StarterIsProcessingFunc = starter.IsProcessing
+
+func handleJobDelete(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	j, ok := getReqJob(w, r, ds)
+	if !ok {
+		return
+	}
+
+	if StarterIsProcessingFunc(j.UUID) {
+		outputErrorMsg(w, http.StatusConflict, "job is processing now, please retry later")
+		return
+	}
+
+	logger.Logf(false, "delete job manually (job: %s)", j.UUID)
+	if err := ds.DeleteJob(r.Context(), j.UUID); err != nil {
+		logger.Logf(false, "failed to delete job in datastore: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore delete error")
+		return
+	}
+	// wake up a job in backoff, it will be skipped because it is deleted
+	starter.ResetRetry(j.UUID)
+	recordAuditLog(r, ds, auditActionJobDelete, j.UUID.String(), nil)
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func handleJobRetry(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	j, ok := getReqJob(w, r, ds)
+	if !ok {
+		return
+	}
+
+	if StarterIsProcessingFunc(j.UUID) {
+		outputErrorMsg(w, http.StatusConflict, "job is processing now, please retry later")
+		return
+	}
+
+	count := starter.GetRetryCount(j.UUID)
+	logger.Logf(false, "reset backoff of job manually (job: %s, retry count: %d)", j.UUID, count)
+	starter.ResetRetry(j.UUID)
+	recordAuditLog(r, ds, auditActionJobRetry, j.UUID.String(), datastore.AuditChanges{
+		{Field: "retry_count", Old: count, New: 0},
+	})
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toUserJobDetail(*j))
+}
+
+// getReqJob get a job from request, write error response if failed
+func getReqJob(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) (*datastore.Job, bool) {
+	jobID, err := parseReqJobID(r)
+	if err != nil {
+		logger.Logf(false, "failed to parse job id: %+v", err)
+		outputErrorMsg(w, http.StatusBadRequest, "incorrect job id")
+		return nil, false
+	}
+
+	j, err := ds.GetJob(r.Context(), jobID)
+	switch {
+	case errors.Is(err, datastore.ErrNotFound):
+		outputErrorMsg(w, http.StatusNotFound, "job is not found")
+		return nil, false
+	case err != nil:
+		logger.Logf(false, "failed to retrieve job from datastore: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return nil, false
+	}
+
+	return j, true
+}
+
+func parseReqJobID(r *http.Request) (uuid.UUID, error) {
+	jobID, err := uuid.FromString(pat.Param(r, "id"))
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("failed to parse job id: %w", err)
+	}
+
+	return jobID, nil
+}
+
+func toUserJobDetail(j datastore.Job) UserJobDetail {
+	info, err := gh.ExtractWorkflowJobInfo([]byte(j.CheckEventJSON))
+	if err != nil {
+		logger.Logf(true, "failed to extract workflow info from job (job: %s): %+v", j.UUID, err)
+	}
+
+	return UserJobDetail{
+		UserJob:    sanitizeJob(j),
+		Workflow:   info,
+		RetryCount: starter.GetRetryCount(j.UUID),
+		InProgress: starter.IsInProgress(j.UUID),
+	}
+}
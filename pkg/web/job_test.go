@@ -0,0 +1,257 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/internal/testutils"
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/gh"
+	"github.com/whywaita/myshoes/pkg/starter"
+	"github.com/whywaita/myshoes/pkg/web"
+)
+
+func Test_handleJobList(t *testing.T) {
+	testURL := testutils.GetTestURL()
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	targetID := uuid.NewV4()
+	if err := testDatastore.CreateTarget(context.Background(), datastore.Target{
+		UUID:           targetID,
+		Scope:          "octocat",
+		TokenExpiredAt: testTime,
+		ResourceType:   datastore.ResourceTypeNano,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+
+	jobIDs := []uuid.UUID{uuid.NewV4(), uuid.NewV4()}
+	for i, jobID := range jobIDs {
+		if err := testDatastore.EnqueueJob(context.Background(), datastore.Job{
+			UUID:           jobID,
+			Repository:     fmt.Sprintf("octocat/job-%d", i),
+			CheckEventJSON: "{}",
+			TargetID:       targetID,
+		}); err != nil {
+			t.Fatalf("failed to enqueue job: %+v", err)
+		}
+	}
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantIDs  []uuid.UUID
+	}{
+		{
+			path:     "/job",
+			wantCode: http.StatusOK,
+			wantIDs:  jobIDs,
+		},
+		{
+			path:     "/job?repository=octocat/job-1",
+			wantCode: http.StatusOK,
+			wantIDs:  jobIDs[1:],
+		},
+		{
+			path:     fmt.Sprintf("/job?target_id=%s", uuid.NewV4()),
+			wantCode: http.StatusOK,
+			wantIDs:  []uuid.UUID{},
+		},
+		{
+			path:     "/job?target_id=invalid",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			path:     "/job?offset=-1",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		resp, err := http.Get(testURL + test.path)
+		if err != nil {
+			t.Fatalf("failed to GET request: %+v", err)
+		}
+		content, code := parseResponse(resp)
+		if code != test.wantCode {
+			t.Fatalf("must be response statuscode is %d, but got %d (path: %s): %s", test.wantCode, code, test.path, string(content))
+		}
+		if test.wantIDs == nil {
+			continue
+		}
+
+		var got []web.UserJob
+		if err := json.Unmarshal(content, &got); err != nil {
+			t.Fatalf("failed to unmarshal response content: %+v", err)
+		}
+		gotIDs := map[uuid.UUID]struct{}{}
+		for _, j := range got {
+			gotIDs[j.UUID] = struct{}{}
+		}
+		wantIDs := map[uuid.UUID]struct{}{}
+		for _, id := range test.wantIDs {
+			wantIDs[id] = struct{}{}
+		}
+		if diff := cmp.Diff(wantIDs, gotIDs); diff != "" {
+			t.Errorf("mismatch (path: %s) (-want +got):\n%s", test.path, diff)
+		}
+	}
+}
+
+func Test_handleJobRead(t *testing.T) {
+	testURL := testutils.GetTestURL()
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	targetID := uuid.NewV4()
+	if err := testDatastore.CreateTarget(context.Background(), datastore.Target{
+		UUID:           targetID,
+		Scope:          "octocat/Hello-World",
+		TokenExpiredAt: testTime,
+		ResourceType:   datastore.ResourceTypeNano,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+	jobID := uuid.NewV4()
+	if err := testDatastore.EnqueueJob(context.Background(), datastore.Job{
+		UUID:           jobID,
+		Repository:     "octocat/Hello-World",
+		CheckEventJSON: `{"action": "queued", "workflow_job": {"id": 100, "run_id": 10, "workflow_name": "CI", "name": "test", "labels": ["myshoes"]}}`,
+		TargetID:       targetID,
+	}); err != nil {
+		t.Fatalf("failed to enqueue job: %+v", err)
+	}
+
+	tests := []struct {
+		input    string
+		wantCode int
+		want     *gh.WorkflowJobInfo
+	}{
+		{
+			input:    jobID.String(),
+			wantCode: http.StatusOK,
+			want: &gh.WorkflowJobInfo{
+				RunID:        10,
+				JobID:        100,
+				WorkflowName: "CI",
+				JobName:      "test",
+				Labels:       []string{"myshoes"},
+			},
+		},
+		{
+			input:    uuid.NewV4().String(),
+			wantCode: http.StatusNotFound,
+		},
+		{
+			input:    "invalid",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		resp, err := http.Get(fmt.Sprintf("%s/job/%s", testURL, test.input))
+		if err != nil {
+			t.Fatalf("failed to GET request: %+v", err)
+		}
+		content, code := parseResponse(resp)
+		if code != test.wantCode {
+			t.Fatalf("must be response statuscode is %d, but got %d (input: %s): %s", test.wantCode, code, test.input, string(content))
+		}
+		if test.want == nil {
+			continue
+		}
+
+		var got web.UserJobDetail
+		if err := json.Unmarshal(content, &got); err != nil {
+			t.Fatalf("failed to unmarshal response content: %+v", err)
+		}
+		if diff := cmp.Diff(test.want, got.Workflow); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func Test_handleJobDeleteAndRetry(t *testing.T) {
+	testURL := testutils.GetTestURL()
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	targetID := uuid.NewV4()
+	if err := testDatastore.CreateTarget(context.Background(), datastore.Target{
+		UUID:           targetID,
+		Scope:          "octocat/Hello-World",
+		TokenExpiredAt: testTime,
+		ResourceType:   datastore.ResourceTypeNano,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+	jobID := uuid.NewV4()
+	if err := testDatastore.EnqueueJob(context.Background(), datastore.Job{
+		UUID:           jobID,
+		Repository:     "octocat/Hello-World",
+		CheckEventJSON: "{}",
+		TargetID:       targetID,
+	}); err != nil {
+		t.Fatalf("failed to enqueue job: %+v", err)
+	}
+	starter.AddInstanceRetryCount.Store(jobID, 5)
+
+	// job is processing by starter now
+	web.StarterIsProcessingFunc = func(jobID uuid.UUID) bool { return true }
+	for _, method := range []string{http.MethodDelete, http.MethodPost} {
+		path := fmt.Sprintf("%s/job/%s", testURL, jobID)
+		if method == http.MethodPost {
+			path += "/retry"
+		}
+		req, err := http.NewRequest(method, path, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %+v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to request: %+v", err)
+		}
+		if content, code := parseResponse(resp); code != http.StatusConflict {
+			t.Fatalf("must be response statuscode is 409 (%s %s), but got %d: %s", method, path, code, string(content))
+		}
+	}
+	web.StarterIsProcessingFunc = starter.IsProcessing
+
+	resp, err := http.Post(fmt.Sprintf("%s/job/%s/retry", testURL, jobID), "application/json", nil)
+	if err != nil {
+		t.Fatalf("failed to POST request: %+v", err)
+	}
+	content, code := parseResponse(resp)
+	if code != http.StatusOK {
+		t.Fatalf("must be response statuscode is 200, but got %d: %s", code, string(content))
+	}
+	if got := starter.GetRetryCount(jobID); got != 0 {
+		t.Fatalf("retry count must be reset, but got %d", got)
+	}
+
+	for _, wantCode := range []int{http.StatusNoContent, http.StatusNotFound} {
+		req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/job/%s", testURL, jobID), nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %+v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to DELETE request: %+v", err)
+		}
+		content, code := parseResponse(resp)
+		if code != wantCode {
+			t.Fatalf("must be response statuscode is %d, but got %d: %s", wantCode, code, string(content))
+		}
+	}
+	if _, err := testDatastore.GetJob(context.Background(), jobID); !errors.Is(err, datastore.ErrNotFound) {
+		t.Fatalf("job must be deleted, but got %+v", err)
+	}
+}
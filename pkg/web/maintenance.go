@@ -0,0 +1,165 @@
+package web
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/logger"
+
+	"goji.io/pat"
+)
+
+// MaintenanceWindowCreateParam is parameter for create maintenance window
+type MaintenanceWindowCreateParam struct {
+	Reason  string     `json:"reason"`
+	StartAt *time.Time `json:"start_at"` // nullable, start now if null
+	EndAt   *time.Time `json:"end_at"`   // nullable, continue until deleted if null
+}
+
+// UserMaintenanceWindow is format of maintenance window for user
+type UserMaintenanceWindow struct {
+	UUID      uuid.UUID  `json:"id"`
+	Reason    string     `json:"reason"`
+	StartAt   time.Time  `json:"start_at"`
+	EndAt     *time.Time `json:"end_at"`
+	Active    bool       `json:"active"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// MaintenanceStatus is status of maintenance mode
+type MaintenanceStatus struct {
+	// Active is true if provisioning of new runners is stopped now
+	Active  bool                    `json:"active"`
+	Windows []UserMaintenanceWindow `json:"windows"`
+	// RunningRunners is number of runners that are not deleted yet
+	RunningRunners int `json:"running_runners"`
+	// Drained is true if maintenance is active and all runners are deleted
+	Drained bool `json:"drained"`
+}
+
+func handleMaintenanceRead(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	ctx := r.Context()
+	now := time.Now().UTC()
+
+	windows, err := ds.ListMaintenanceWindows(ctx, now)
+	if err != nil {
+		logger.Logf(false, "failed to retrieve list of maintenance window: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+	runners, err := ds.ListRunners(ctx)
+	if err != nil {
+		logger.Logf(false, "failed to retrieve list of runner: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+
+	status := MaintenanceStatus{
+		Windows:        []UserMaintenanceWindow{},
+		RunningRunners: len(runners),
+	}
+	for _, mw := range windows {
+		uw := sanitizeMaintenanceWindow(mw, now)
+		status.Active = status.Active || uw.Active
+		status.Windows = append(status.Windows, uw)
+	}
+	status.Drained = status.Active && status.RunningRunners == 0
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(status)
+}
+
+func handleMaintenanceCreate(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	ctx := r.Context()
+	now := time.Now().UTC()
+
+	input := MaintenanceWindowCreateParam{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logger.Logf(false, "failed to decode request body: %+v", err)
+		outputErrorMsg(w, http.StatusBadRequest, "json decode error")
+		return
+	}
+
+	mw := datastore.MaintenanceWindow{
+		UUID:      uuid.NewV4(),
+		Reason:    input.Reason,
+		StartAt:   now,
+		CreatedBy: principalName(r),
+	}
+	if input.StartAt != nil {
+		mw.StartAt = input.StartAt.UTC()
+	}
+	if input.EndAt != nil {
+		if !input.EndAt.After(mw.StartAt) || !input.EndAt.After(now) {
+			outputErrorMsg(w, http.StatusBadRequest, "end_at must be after start_at and now")
+			return
+		}
+		mw.EndAt = sql.NullTime{Time: input.EndAt.UTC(), Valid: true}
+	}
+
+	if err := ds.CreateMaintenanceWindow(ctx, mw); err != nil {
+		logger.Logf(false, "failed to create maintenance window: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore create error")
+		return
+	}
+	changes := datastore.AuditChanges{
+		{Field: "reason", Old: "", New: mw.Reason},
+		{Field: "start_at", Old: nil, New: mw.StartAt},
+	}
+	if mw.EndAt.Valid {
+		changes = append(changes, datastore.AuditChange{Field: "end_at", Old: nil, New: mw.EndAt.Time})
+	}
+	recordAuditLog(r, ds, auditActionMaintenanceCreate, mw.UUID.String(), changes)
+
+	mw.CreatedAt = now
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sanitizeMaintenanceWindow(mw, now))
+}
+
+func handleMaintenanceDelete(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	windowID, err := uuid.FromString(pat.Param(r, "id"))
+	if err != nil {
+		logger.Logf(false, "failed to parse maintenance window id: %+v", err)
+		outputErrorMsg(w, http.StatusBadRequest, "incorrect maintenance window id")
+		return
+	}
+
+	err = ds.DeleteMaintenanceWindow(r.Context(), windowID)
+	switch {
+	case errors.Is(err, datastore.ErrNotFound):
+		outputErrorMsg(w, http.StatusNotFound, "maintenance window is not found")
+		return
+	case err != nil:
+		logger.Logf(false, "failed to delete maintenance window: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore delete error")
+		return
+	}
+	recordAuditLog(r, ds, auditActionMaintenanceDelete, windowID.String(), nil)
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func sanitizeMaintenanceWindow(mw datastore.MaintenanceWindow, now time.Time) UserMaintenanceWindow {
+	uw := UserMaintenanceWindow{
+		UUID:      mw.UUID,
+		Reason:    mw.Reason,
+		StartAt:   mw.StartAt,
+		Active:    mw.IsActive(now),
+		CreatedBy: mw.CreatedBy,
+		CreatedAt: mw.CreatedAt,
+	}
+	if mw.EndAt.Valid {
+		uw.EndAt = &mw.EndAt.Time
+	}
+	return uw
+}
@@ -0,0 +1,165 @@
+package web_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/internal/testutils"
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/web"
+)
+
+func Test_handleTargetPause(t *testing.T) {
+	testURL := testutils.GetTestURL()
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	targetID := uuid.NewV4()
+	if err := testDatastore.CreateTarget(context.Background(), datastore.Target{
+		UUID:           targetID,
+		Scope:          "octocat",
+		TokenExpiredAt: testTime,
+		ResourceType:   datastore.ResourceTypeNano,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+
+	tests := []struct {
+		path       string
+		body       string
+		wantCode   int
+		wantStatus datastore.TargetStatus
+	}{
+		{path: "pause", body: `{"reason": "migrate to new cloud"}`, wantCode: http.StatusOK, wantStatus: datastore.TargetStatusSuspend},
+		{path: "pause", wantCode: http.StatusBadRequest},
+		{path: "resume", wantCode: http.StatusOK, wantStatus: datastore.TargetStatusActive},
+		{path: "resume", wantCode: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		resp, err := http.Post(fmt.Sprintf("%s/target/%s/%s", testURL, targetID, test.path), "application/json", bytes.NewBufferString(test.body))
+		if err != nil {
+			t.Fatalf("failed to request: %+v", err)
+		}
+		content, code := parseResponse(resp)
+		if code != test.wantCode {
+			t.Fatalf("must be response statuscode is %d, but got %d (%s): %s", test.wantCode, code, test.path, string(content))
+		}
+		if code != http.StatusOK {
+			continue
+		}
+
+		var got web.UserTarget
+		if err := json.Unmarshal(content, &got); err != nil {
+			t.Fatalf("failed to unmarshal response: %+v", err)
+		}
+		if got.Status != test.wantStatus {
+			t.Errorf("want status %s, but got %s", test.wantStatus, got.Status)
+		}
+	}
+
+	// paused target is listed
+	if _, err := http.Post(fmt.Sprintf("%s/target/%s/pause", testURL, targetID), "application/json", nil); err != nil {
+		t.Fatalf("failed to request: %+v", err)
+	}
+	resp, err := http.Get(testURL + "/target")
+	if err != nil {
+		t.Fatalf("failed to request: %+v", err)
+	}
+	content, _ := parseResponse(resp)
+	var targets []web.UserTarget
+	if err := json.Unmarshal(content, &targets); err != nil {
+		t.Fatalf("failed to unmarshal response: %+v", err)
+	}
+	if len(targets) != 1 || targets[0].Status != datastore.TargetStatusSuspend {
+		t.Errorf("paused target must be listed, but got %+v", targets)
+	}
+}
+
+func Test_handleMaintenance(t *testing.T) {
+	testURL := testutils.GetTestURL()
+	_, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	getStatus := func() web.MaintenanceStatus {
+		t.Helper()
+		resp, err := http.Get(testURL + "/maintenance")
+		if err != nil {
+			t.Fatalf("failed to request: %+v", err)
+		}
+		content, code := parseResponse(resp)
+		if code != http.StatusOK {
+			t.Fatalf("must be response statuscode is 200, but got %d: %s", code, string(content))
+		}
+		var status web.MaintenanceStatus
+		if err := json.Unmarshal(content, &status); err != nil {
+			t.Fatalf("failed to unmarshal response: %+v", err)
+		}
+		return status
+	}
+
+	if status := getStatus(); status.Active || len(status.Windows) != 0 {
+		t.Fatalf("must not be in maintenance, but got %+v", status)
+	}
+
+	// scheduled window
+	start := time.Now().Add(time.Hour).UTC()
+	body := fmt.Sprintf(`{"reason": "weekly", "start_at": %q, "end_at": %q}`, start.Format(time.RFC3339), start.Add(time.Hour).Format(time.RFC3339))
+	resp, err := http.Post(testURL+"/maintenance", "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("failed to request: %+v", err)
+	}
+	if content, code := parseResponse(resp); code != http.StatusCreated {
+		t.Fatalf("must be response statuscode is 201, but got %d: %s", code, string(content))
+	}
+	if status := getStatus(); status.Active || len(status.Windows) != 1 {
+		t.Fatalf("scheduled window must not be active, but got %+v", status)
+	}
+
+	// maintenance now
+	resp, err = http.Post(testURL+"/maintenance", "application/json", bytes.NewBufferString(`{"reason": "incident"}`))
+	if err != nil {
+		t.Fatalf("failed to request: %+v", err)
+	}
+	content, code := parseResponse(resp)
+	if code != http.StatusCreated {
+		t.Fatalf("must be response statuscode is 201, but got %d: %s", code, string(content))
+	}
+	var created web.UserMaintenanceWindow
+	if err := json.Unmarshal(content, &created); err != nil {
+		t.Fatalf("failed to unmarshal response: %+v", err)
+	}
+	if status := getStatus(); !status.Active || !status.Drained {
+		t.Fatalf("must be active and drained (no runner), but got %+v", status)
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, testURL+"/maintenance/"+created.UUID.String(), nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %+v", err)
+	}
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to request: %+v", err)
+	}
+	if content, code := parseResponse(resp); code != http.StatusNoContent {
+		t.Fatalf("must be response statuscode is 204, but got %d: %s", code, string(content))
+	}
+	if status := getStatus(); status.Active {
+		t.Fatalf("must not be in maintenance after delete, but got %+v", status)
+	}
+
+	// invalid period
+	resp, err = http.Post(testURL+"/maintenance", "application/json", bytes.NewBufferString(`{"end_at": "2000-01-01T00:00:00Z"}`))
+	if err != nil {
+		t.Fatalf("failed to request: %+v", err)
+	}
+	if content, code := parseResponse(resp); code != http.StatusBadRequest {
+		t.Fatalf("must be response statuscode is 400, but got %d: %s", code, string(content))
+	}
+}
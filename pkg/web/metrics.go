@@ -22,6 +22,7 @@ func HandleMetrics(w http.ResponseWriter, r *http.Request, ds datastore.Datastor
 		prometheus.DefaultGatherer,
 		registry,
 	}
-	h := promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{})
+	// OpenMetrics is needed for exemplars (job_id, runner_name) of retry metrics
+	h := promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{EnableOpenMetrics: true})
 	h.ServeHTTP(w, r)
 }
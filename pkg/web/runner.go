@@ -0,0 +1,322 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/logger"
+	"github.com/whywaita/myshoes/pkg/runner"
+
+	"goji.io/pat"
+)
+
+// UserRunner is format of runner for user
+type UserRunner struct {
+	UUID           uuid.UUID `json:"id"`
+	Name           string    `json:"name"`
+	ShoesType      string    `json:"shoes_type"`
+	IPAddress      string    `json:"ip_address"`
+	TargetID       uuid.UUID `json:"target_id"`
+	CloudID        string    `json:"cloud_id"`
+	ResourceType   string    `json:"resource_type"`
+	RunnerArch     string    `json:"runner_arch"`
+	RepositoryURL  string    `json:"repository_url"`
+	RequestedJobID int64     `json:"requested_job_id"` // 0 if unknown
+
+	ExecutedRepository string     `json:"executed_repository"`
+	ExecutedRunID      int64      `json:"executed_run_id"`
+	ExecutedJobID      int64      `json:"executed_job_id"`
+	ExecutedAt         *time.Time `json:"executed_at"`
+	JobMismatched      bool       `json:"job_mismatched"` // true if runner executed other job than requested job
+
+	Status        string     `json:"status"`         // running or deleted
+	DeletedReason string     `json:"deleted_reason"` // only deleted runner
+	DeletedAt     *time.Time `json:"deleted_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+func handleRunnerList(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	filter, err := parseRunnerFilter(r)
+	if err != nil {
+		outputErrorMsg(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	outputRunners(w, r, ds, filter)
+}
+
+func handleTargetRunnerList(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	ctx := r.Context()
+	targetID, err := parseReqTargetID(r)
+	if err != nil {
+		logger.Logf(false, "failed to parse target id: %+v", err)
+		outputErrorMsg(w, http.StatusBadRequest, "incorrect target id")
+		return
+	}
+	target, err := ds.GetTarget(ctx, targetID)
+	if err != nil {
+		if errors.Is(err, datastore.ErrNotFound) {
+			outputErrorMsg(w, http.StatusNotFound, "target is not found")
+			return
+		}
+		logger.Logf(false, "failed to retrieve target from datastore: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+	if !canAccessTarget(r, target.Scope) {
+		outputErrorMsg(w, http.StatusForbidden, "target is out of scope of token")
+		return
+	}
+
+	filter, err := parseRunnerFilter(r)
+	if err != nil {
+		outputErrorMsg(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter.TargetID = targetID
+
+	outputRunners(w, r, ds, filter)
+}
+
+func outputRunners(w http.ResponseWriter, r *http.Request, ds datastore.Datastore, filter datastore.RunnerFilter) {
+	runners, err := ds.ListRunnersWithFilter(r.Context(), filter)
+	if err != nil {
+		logger.Logf(false, "failed to retrieve list of runner: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+
+	urs := []UserRunner{}
+	for _, rn := range runners {
+		urs = append(urs, sanitizeRunner(rn))
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(urs)
+}
+
+// parseRunnerFilter parse query parameters.
+// status, target_id, repository, since, until (RFC 3339), limit, offset
+func parseRunnerFilter(r *http.Request) (datastore.RunnerFilter, error) {
+	q := r.URL.Query()
+
+	var filter datastore.RunnerFilter
+	switch status := datastore.RunnerFilterStatus(q.Get("status")); status {
+	case datastore.RunnerFilterStatusAll, datastore.RunnerFilterStatusRunning, datastore.RunnerFilterStatusDeleted:
+		filter.Status = status
+	default:
+		return datastore.RunnerFilter{}, fmt.Errorf("status must be %s or %s", datastore.RunnerFilterStatusRunning, datastore.RunnerFilterStatusDeleted)
+	}
+
+	lq, err := parseListQuery(r)
+	if err != nil {
+		return datastore.RunnerFilter{}, err
+	}
+	filter.TargetID = lq.TargetID
+	filter.Repository = lq.Repository
+	filter.Since = lq.Since
+	filter.Until = lq.Until
+	filter.Limit = lq.Limit
+	filter.Offset = lq.Offset
+
+	return filter, nil
+}
+
+// listQuery is common query parameters for list endpoints
+type listQuery struct {
+	TargetID   uuid.UUID
+	Repository string
+	Since      time.Time
+	Until      time.Time
+	Limit      int
+	Offset     int
+}
+
+func parseListQuery(r *http.Request) (*listQuery, error) {
+	q := r.URL.Query()
+	lq := listQuery{
+		Repository: q.Get("repository"),
+		Limit:      defaultListLimit,
+	}
+
+	if s := q.Get("target_id"); s != "" {
+		targetID, err := uuid.FromString(s)
+		if err != nil {
+			return nil, fmt.Errorf("target_id is invalid: %w", err)
+		}
+		lq.TargetID = targetID
+	}
+	for _, t := range []struct {
+		key string
+		out *time.Time
+	}{
+		{key: "since", out: &lq.Since},
+		{key: "until", out: &lq.Until},
+	} {
+		s := q.Get(t.key)
+		if s == "" {
+			continue
+		}
+		v, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be RFC 3339 format: %w", t.key, err)
+		}
+		*t.out = v
+	}
+	for _, t := range []struct {
+		key string
+		out *int
+	}{
+		{key: "limit", out: &lq.Limit},
+		{key: "offset", out: &lq.Offset},
+	} {
+		s := q.Get(t.key)
+		if s == "" {
+			continue
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("%s must be non-negative integer", t.key)
+		}
+		*t.out = v
+	}
+	if lq.Limit == 0 || lq.Limit > maxListLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxListLimit)
+	}
+
+	return &lq, nil
+}
+
+func handleRunnerRead(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	ctx := r.Context()
+	runnerID, err := parseReqRunnerID(r)
+	if err != nil {
+		logger.Logf(false, "failed to parse runner id: %+v", err)
+		outputErrorMsg(w, http.StatusBadRequest, "incorrect runner id")
+		return
+	}
+
+	rn, err := ds.GetRunner(ctx, runnerID)
+	switch {
+	case errors.Is(err, datastore.ErrNotFound):
+		outputErrorMsg(w, http.StatusNotFound, "runner is not found")
+		return
+	case err != nil:
+		logger.Logf(false, "failed to retrieve runner from datastore: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(sanitizeRunner(*rn))
+}
+
+// RunnerDeleteManuallyFunc is function of delete a runner by admin (for testing)
+var RunnerDeleteManuallyFunc = func(ctx context.Context, ds datastore.Datastore, r datastore.Runner) error {
+	return runner.New(ds, config.GetRuntime().RunnerVersion).DeleteRunnerManually(ctx, r)
+}
+
+func handleRunnerDelete(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	ctx := r.Context()
+	runnerID, err := parseReqRunnerID(r)
+	if err != nil {
+		logger.Logf(false, "failed to parse runner id: %+v", err)
+		outputErrorMsg(w, http.StatusBadRequest, "incorrect runner id")
+		return
+	}
+
+	rn, err := ds.GetRunner(ctx, runnerID)
+	switch {
+	case errors.Is(err, datastore.ErrNotFound):
+		outputErrorMsg(w, http.StatusNotFound, "runner is not found")
+		return
+	case err != nil:
+		logger.Logf(false, "failed to retrieve runner from datastore: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+	if rn.Deleted {
+		outputErrorMsg(w, http.StatusBadRequest, "runner is already deleted")
+		return
+	}
+
+	logger.Logf(false, "delete runner manually (runner: %s)", rn.UUID)
+	if err := RunnerDeleteManuallyFunc(ctx, ds, *rn); err != nil {
+		logger.Logf(false, "failed to delete runner manually: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "failed to delete runner")
+		return
+	}
+	recordAuditLog(r, ds, auditActionRunnerDelete, rn.UUID.String(), datastore.AuditChanges{
+		{Field: "deleted", Old: false, New: true},
+	})
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseReqRunnerID(r *http.Request) (uuid.UUID, error) {
+	runnerID, err := uuid.FromString(pat.Param(r, "id"))
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("failed to parse runner id: %w", err)
+	}
+
+	return runnerID, nil
+}
+
+func sanitizeRunner(r datastore.Runner) UserRunner {
+	runnerArch := r.RunnerArch
+	if runnerArch == "" {
+		runnerArch = datastore.RunnerArchX64
+	}
+
+	ur := UserRunner{
+		UUID:               r.UUID,
+		Name:               runner.ToName(r.UUID.String()),
+		ShoesType:          r.ShoesType,
+		IPAddress:          r.IPAddress,
+		TargetID:           r.TargetID,
+		CloudID:            r.CloudID,
+		ResourceType:       r.ResourceType.String(),
+		RunnerArch:         string(runnerArch),
+		RepositoryURL:      r.RepositoryURL,
+		RequestedJobID:     r.RequestedJobID(),
+		ExecutedRepository: r.ExecutedRepository.String,
+		ExecutedRunID:      r.ExecutedRunID.Int64,
+		ExecutedJobID:      r.ExecutedJobID.Int64,
+		JobMismatched:      r.IsJobMismatched(),
+		CreatedAt:          r.CreatedAt,
+		UpdatedAt:          r.UpdatedAt,
+	}
+	if r.ExecutedAt.Valid {
+		ur.ExecutedAt = &r.ExecutedAt.Time
+	}
+
+	ur.Status = string(datastore.RunnerFilterStatusRunning)
+	if r.Deleted {
+		ur.Status = string(datastore.RunnerFilterStatusDeleted)
+		ur.DeletedReason = string(r.Status)
+		if r.DeletedAt.Valid {
+			ur.DeletedAt = &r.DeletedAt.Time
+		}
+	}
+
+	return ur
+}
@@ -0,0 +1,64 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/whywaita/myshoes/pkg/logger"
+	"github.com/whywaita/myshoes/pkg/mirror"
+
+	"goji.io/pat"
+)
+
+// handleRunnerMirror serve a runner file or checksum of it from mirror directory
+func handleRunnerMirror(w http.ResponseWriter, r *http.Request) {
+	if !mirror.Enabled() {
+		outputErrorMsg(w, http.StatusNotFound, "runner mirror is disabled")
+		return
+	}
+
+	version := pat.Param(r, "version")
+	file := pat.Param(r, "file")
+	isChecksum := strings.HasSuffix(file, mirror.ChecksumSuffix)
+	file = strings.TrimSuffix(file, mirror.ChecksumSuffix)
+
+	p, checksum, err := mirror.Get(r.Context(), version, file)
+	switch {
+	case errors.Is(err, mirror.ErrInvalidFile):
+		outputErrorMsg(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, mirror.ErrNotCached):
+		outputErrorMsg(w, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		logger.Logf(false, "failed to get runner file from mirror: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "runner mirror error")
+		return
+	}
+
+	if isChecksum {
+		w.Header().Set("Content-Type", "text/plain;charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(checksum + "\n"))
+		return
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		logger.Logf(false, "failed to open runner file: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "runner mirror error")
+		return
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		logger.Logf(false, "failed to stat runner file: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "runner mirror error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(w, r, file, stat.ModTime(), f)
+}
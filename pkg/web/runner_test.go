@@ -0,0 +1,383 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/internal/testutils"
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/web"
+)
+
+func Test_handleRunnerRead(t *testing.T) {
+	testURL := testutils.GetTestURL()
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	targetID := uuid.NewV4()
+	if err := testDatastore.CreateTarget(context.Background(), datastore.Target{
+		UUID:           targetID,
+		Scope:          "octocat/Hello-World",
+		TokenExpiredAt: testTime,
+		ResourceType:   datastore.ResourceTypeNano,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+
+	matchedID := uuid.NewV4()
+	mismatchedID := uuid.NewV4()
+	for _, id := range []uuid.UUID{matchedID, mismatchedID} {
+		if err := testDatastore.CreateRunner(context.Background(), datastore.Runner{
+			UUID:           id,
+			ShoesType:      "shoes-test",
+			TargetID:       targetID,
+			CloudID:        "mycloud-uuid",
+			ResourceType:   datastore.ResourceTypeNano,
+			RepositoryURL:  "https://github.com/octocat/Hello-World",
+			RequestWebhook: `{"action": "queued", "workflow_job": {"id": 100, "run_id": 10, "labels": ["myshoes"]}}`,
+		}); err != nil {
+			t.Fatalf("failed to create runner: %+v", err)
+		}
+	}
+	if err := testDatastore.SetRunnerExecutedJob(context.Background(), matchedID, datastore.ExecutedJob{
+		Repository: "octocat/Hello-World",
+		RunID:      10,
+		JobID:      100,
+		ExecutedAt: testTime,
+	}); err != nil {
+		t.Fatalf("failed to set executed job: %+v", err)
+	}
+	if err := testDatastore.SetRunnerExecutedJob(context.Background(), mismatchedID, datastore.ExecutedJob{
+		Repository: "octocat/Spoon-Knife",
+		RunID:      20,
+		JobID:      200,
+		ExecutedAt: testTime,
+	}); err != nil {
+		t.Fatalf("failed to set executed job: %+v", err)
+	}
+
+	tests := []struct {
+		input    string
+		wantCode int
+		want     *web.UserRunner
+	}{
+		{
+			input:    matchedID.String(),
+			wantCode: http.StatusOK,
+			want: &web.UserRunner{
+				UUID:               matchedID,
+				Name:               fmt.Sprintf("myshoes-%s", matchedID),
+				ShoesType:          "shoes-test",
+				TargetID:           targetID,
+				CloudID:            "mycloud-uuid",
+				ResourceType:       datastore.ResourceTypeNano.String(),
+				RunnerArch:         "x64",
+				RepositoryURL:      "https://github.com/octocat/Hello-World",
+				RequestedJobID:     100,
+				ExecutedRepository: "octocat/Hello-World",
+				ExecutedRunID:      10,
+				ExecutedJobID:      100,
+				ExecutedAt:         &testTime,
+				JobMismatched:      false,
+				Status:             "running",
+			},
+		},
+		{
+			input:    mismatchedID.String(),
+			wantCode: http.StatusOK,
+			want: &web.UserRunner{
+				UUID:               mismatchedID,
+				Name:               fmt.Sprintf("myshoes-%s", mismatchedID),
+				ShoesType:          "shoes-test",
+				TargetID:           targetID,
+				CloudID:            "mycloud-uuid",
+				ResourceType:       datastore.ResourceTypeNano.String(),
+				RunnerArch:         "x64",
+				RepositoryURL:      "https://github.com/octocat/Hello-World",
+				RequestedJobID:     100,
+				ExecutedRepository: "octocat/Spoon-Knife",
+				ExecutedRunID:      20,
+				ExecutedJobID:      200,
+				ExecutedAt:         &testTime,
+				JobMismatched:      true,
+				Status:             "running",
+			},
+		},
+		{ // not found
+			input:    uuid.NewV4().String(),
+			wantCode: http.StatusNotFound,
+		},
+		{ // invalid id
+			input:    "invalid",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		resp, err := http.Get(fmt.Sprintf("%s/runner/%s", testURL, test.input))
+		if err != nil {
+			t.Fatalf("failed to GET request: %+v", err)
+		}
+		content, code := parseResponse(resp)
+		if code != test.wantCode {
+			t.Fatalf("must be response statuscode is %d, but got %d: %s", test.wantCode, code, string(content))
+		}
+		if test.want == nil {
+			continue
+		}
+
+		var got web.UserRunner
+		if err := json.Unmarshal(content, &got); err != nil {
+			t.Fatalf("failed to unmarshal response content: %+v", err)
+		}
+		got.CreatedAt = time.Time{}
+		got.UpdatedAt = time.Time{}
+
+		if diff := cmp.Diff(test.want, &got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func Test_handleRunnerList(t *testing.T) {
+	testURL := testutils.GetTestURL()
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	targetIDs := []uuid.UUID{uuid.NewV4(), uuid.NewV4()}
+	for i, targetID := range targetIDs {
+		if err := testDatastore.CreateTarget(context.Background(), datastore.Target{
+			UUID:           targetID,
+			Scope:          fmt.Sprintf("octocat/list-%d", i),
+			TokenExpiredAt: testTime,
+			ResourceType:   datastore.ResourceTypeNano,
+		}); err != nil {
+			t.Fatalf("failed to create target: %+v", err)
+		}
+	}
+
+	runnerIDs := []uuid.UUID{uuid.NewV4(), uuid.NewV4(), uuid.NewV4()}
+	for i, runnerID := range runnerIDs {
+		targetIndex := i % 2
+		if err := testDatastore.CreateRunner(context.Background(), datastore.Runner{
+			UUID:           runnerID,
+			ShoesType:      "shoes-test",
+			TargetID:       targetIDs[targetIndex],
+			CloudID:        "mycloud-uuid",
+			ResourceType:   datastore.ResourceTypeNano,
+			RepositoryURL:  fmt.Sprintf("https://github.com/octocat/list-%d", targetIndex),
+			RequestWebhook: "{}",
+		}); err != nil {
+			t.Fatalf("failed to create runner: %+v", err)
+		}
+	}
+	if err := testDatastore.DeleteRunner(context.Background(), runnerIDs[2], testTime, datastore.RunnerStatusCompleted); err != nil {
+		t.Fatalf("failed to delete runner: %+v", err)
+	}
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantIDs  []uuid.UUID
+	}{
+		{
+			path:     "/runner",
+			wantCode: http.StatusOK,
+			wantIDs:  runnerIDs,
+		},
+		{
+			path:     "/runner?status=running",
+			wantCode: http.StatusOK,
+			wantIDs:  runnerIDs[:2],
+		},
+		{
+			path:     "/runner?status=deleted",
+			wantCode: http.StatusOK,
+			wantIDs:  runnerIDs[2:],
+		},
+		{
+			path:     "/runner?repository=octocat/list-0",
+			wantCode: http.StatusOK,
+			wantIDs:  []uuid.UUID{runnerIDs[0], runnerIDs[2]},
+		},
+		{
+			path:     fmt.Sprintf("/runner?target_id=%s", targetIDs[1]),
+			wantCode: http.StatusOK,
+			wantIDs:  runnerIDs[1:2],
+		},
+		{
+			path:     fmt.Sprintf("/target/%s/runners?status=running", targetIDs[0]),
+			wantCode: http.StatusOK,
+			wantIDs:  runnerIDs[:1],
+		},
+		{
+			path:     "/runner?until=2000-01-01T00:00:00Z",
+			wantCode: http.StatusOK,
+			wantIDs:  []uuid.UUID{},
+		},
+		{
+			path:     "/runner?status=unknown",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			path:     "/runner?since=yesterday",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			path:     "/runner?limit=0",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			path:     fmt.Sprintf("/target/%s/runners", uuid.NewV4()),
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		resp, err := http.Get(testURL + test.path)
+		if err != nil {
+			t.Fatalf("failed to GET request: %+v", err)
+		}
+		content, code := parseResponse(resp)
+		if code != test.wantCode {
+			t.Fatalf("must be response statuscode is %d, but got %d (path: %s): %s", test.wantCode, code, test.path, string(content))
+		}
+		if test.wantIDs == nil {
+			continue
+		}
+
+		var got []web.UserRunner
+		if err := json.Unmarshal(content, &got); err != nil {
+			t.Fatalf("failed to unmarshal response content: %+v", err)
+		}
+		gotIDs := map[uuid.UUID]struct{}{}
+		for _, r := range got {
+			gotIDs[r.UUID] = struct{}{}
+			if r.UUID == runnerIDs[2] && (r.Status != "deleted" || r.DeletedReason != string(datastore.RunnerStatusCompleted)) {
+				t.Errorf("runner must be deleted by completed, but got %s (%s)", r.Status, r.DeletedReason)
+			}
+		}
+		wantIDs := map[uuid.UUID]struct{}{}
+		for _, id := range test.wantIDs {
+			wantIDs[id] = struct{}{}
+		}
+		if diff := cmp.Diff(wantIDs, gotIDs); diff != "" {
+			t.Errorf("mismatch (path: %s) (-want +got):\n%s", test.path, diff)
+		}
+	}
+
+	// pagination
+	paged := map[uuid.UUID]struct{}{}
+	for offset, wantLen := range map[int]int{0: 2, 2: 1} {
+		resp, err := http.Get(fmt.Sprintf("%s/runner?limit=2&offset=%d", testURL, offset))
+		if err != nil {
+			t.Fatalf("failed to GET request: %+v", err)
+		}
+		content, code := parseResponse(resp)
+		if code != http.StatusOK {
+			t.Fatalf("must be response statuscode is 200, but got %d: %s", code, string(content))
+		}
+		var got []web.UserRunner
+		if err := json.Unmarshal(content, &got); err != nil {
+			t.Fatalf("failed to unmarshal response content: %+v", err)
+		}
+		if len(got) != wantLen {
+			t.Fatalf("must be %d runners (offset: %d), but got %d", wantLen, offset, len(got))
+		}
+		for _, r := range got {
+			paged[r.UUID] = struct{}{}
+		}
+	}
+	if len(paged) != len(runnerIDs) {
+		t.Fatalf("pages must contain all runners, but got %d runners", len(paged))
+	}
+}
+
+func Test_handleRunnerDelete(t *testing.T) {
+	testURL := testutils.GetTestURL()
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	var deleted []uuid.UUID
+	web.RunnerDeleteManuallyFunc = func(ctx context.Context, ds datastore.Datastore, r datastore.Runner) error {
+		deleted = append(deleted, r.UUID)
+		return ds.DeleteRunner(ctx, r.UUID, time.Now().UTC(), datastore.RunnerStatusManual)
+	}
+
+	targetID := uuid.NewV4()
+	if err := testDatastore.CreateTarget(context.Background(), datastore.Target{
+		UUID:           targetID,
+		Scope:          "octocat/Hello-World",
+		TokenExpiredAt: testTime,
+		ResourceType:   datastore.ResourceTypeNano,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+	runnerID := uuid.NewV4()
+	if err := testDatastore.CreateRunner(context.Background(), datastore.Runner{
+		UUID:           runnerID,
+		ShoesType:      "shoes-test",
+		TargetID:       targetID,
+		CloudID:        "mycloud-uuid",
+		ResourceType:   datastore.ResourceTypeNano,
+		RepositoryURL:  "https://github.com/octocat/Hello-World",
+		RequestWebhook: "{}",
+	}); err != nil {
+		t.Fatalf("failed to create runner: %+v", err)
+	}
+
+	tests := []struct {
+		input    string
+		wantCode int
+	}{
+		{
+			input:    runnerID.String(),
+			wantCode: http.StatusNoContent,
+		},
+		{
+			input:    runnerID.String(), // already deleted
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			input:    uuid.NewV4().String(),
+			wantCode: http.StatusNotFound,
+		},
+		{
+			input:    "invalid",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/runner/%s", testURL, test.input), nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %+v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to DELETE request: %+v", err)
+		}
+		content, code := parseResponse(resp)
+		if code != test.wantCode {
+			t.Fatalf("must be response statuscode is %d, but got %d (input: %s): %s", test.wantCode, code, test.input, string(content))
+		}
+	}
+
+	if diff := cmp.Diff([]uuid.UUID{runnerID}, deleted); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+	got, err := testDatastore.GetRunner(context.Background(), runnerID)
+	if err != nil {
+		t.Fatalf("failed to get runner: %+v", err)
+	}
+	if !got.Deleted || got.Status != datastore.RunnerStatusManual {
+		t.Errorf("runner must be deleted by manual, but got deleted: %t, status: %s", got.Deleted, got.Status)
+	}
+}
@@ -0,0 +1,153 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/logger"
+	"github.com/whywaita/myshoes/pkg/starter"
+
+	"goji.io/pat"
+)
+
+func handleScriptTemplateCreate(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	ctx := r.Context()
+	st, err := starter.DecodeScriptTemplate(r.Body)
+	if err != nil {
+		logger.Logf(false, "failed to decode request body: %+v", err)
+		outputErrorMsg(w, http.StatusBadRequest, "json decode error")
+		return
+	}
+	if err := starter.ValidateScriptTemplate(*st); err != nil {
+		outputErrorMsg(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	_, err = starter.GetScriptTemplate(ctx, ds, st.Name)
+	switch {
+	case err == nil:
+		outputErrorMsg(w, http.StatusBadRequest, "script template is already registered")
+		return
+	case !errors.Is(err, datastore.ErrNotFound):
+		logger.Logf(false, "failed to get script template: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+
+	if err := ds.CreateScriptTemplate(ctx, *st); err != nil {
+		logger.Logf(false, "failed to create script template: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore create error")
+		return
+	}
+
+	outputScriptTemplate(w, r, ds, st.Name, http.StatusCreated)
+}
+
+func handleScriptTemplateList(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	ctx := r.Context()
+
+	sts, err := starter.ListScriptTemplates(ctx, ds)
+	if err != nil {
+		logger.Logf(false, "failed to retrieve list of script template: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(sts)
+}
+
+func handleScriptTemplateRead(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	outputScriptTemplate(w, r, ds, pat.Param(r, "name"), http.StatusOK)
+}
+
+func handleScriptTemplateUpdate(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	ctx := r.Context()
+	name := pat.Param(r, "name")
+
+	st, err := starter.DecodeScriptTemplate(r.Body)
+	if err != nil {
+		logger.Logf(false, "failed to decode request body: %+v", err)
+		outputErrorMsg(w, http.StatusBadRequest, "json decode error")
+		return
+	}
+	if st.Name != "" && st.Name != name {
+		outputErrorMsg(w, http.StatusBadRequest, "invalid input: can't updatable fields (Name)")
+		return
+	}
+	st.Name = name
+	if err := starter.ValidateScriptTemplate(*st); err != nil {
+		outputErrorMsg(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, err := ds.GetScriptTemplate(ctx, name); err != nil {
+		logger.Logf(false, "failed to get script template: %+v", err)
+		outputErrorMsg(w, http.StatusBadRequest, "incorrect script template name (not found in datastore)")
+		return
+	}
+	if err := ds.UpdateScriptTemplate(ctx, *st); err != nil {
+		logger.Logf(false, "failed to update script template: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore update error")
+		return
+	}
+
+	outputScriptTemplate(w, r, ds, name, http.StatusOK)
+}
+
+func handleScriptTemplateDelete(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	ctx := r.Context()
+	name := pat.Param(r, "name")
+
+	if _, err := ds.GetScriptTemplate(ctx, name); err != nil {
+		logger.Logf(false, "failed to get script template: %+v", err)
+		outputErrorMsg(w, http.StatusBadRequest, "incorrect script template name (not found in datastore)")
+		return
+	}
+
+	// paused targets also use template after resume
+	targets, err := ds.ListTargets(ctx)
+	if err != nil {
+		logger.Logf(false, "failed to retrieve list of target: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+	for _, t := range targets {
+		if t.Status == datastore.TargetStatusDeleted {
+			continue
+		}
+		if t.ScriptTemplate.Valid && t.ScriptTemplate.String == name {
+			outputErrorMsg(w, http.StatusBadRequest, "script template is used by target, please change script_template of target")
+			return
+		}
+	}
+
+	if err := ds.DeleteScriptTemplate(ctx, name); err != nil {
+		logger.Logf(false, "failed to delete script template: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore delete error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func outputScriptTemplate(w http.ResponseWriter, r *http.Request, ds datastore.Datastore, name string, status int) {
+	st, err := starter.GetScriptTemplate(r.Context(), ds, name)
+	if err != nil {
+		logger.Logf(false, "failed to retrieve script template: %+v", err)
+		if errors.Is(err, datastore.ErrNotFound) {
+			outputErrorMsg(w, http.StatusNotFound, "script template is not found")
+			return
+		}
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(st)
+}
@@ -0,0 +1,159 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/whywaita/myshoes/internal/testutils"
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/web"
+)
+
+func Test_handleScriptTemplateCreate(t *testing.T) {
+	testURL := testutils.GetTestURL()
+	_, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	tests := []struct {
+		input    string
+		wantCode int
+		want     *datastore.ScriptTemplate
+	}{
+		{
+			input:    `{"name": "gpu", "pre_install": "echo {{.RunnerName}}", "environment": {"CUDA_VISIBLE_DEVICES": "0"}, "labels": ["gpu"]}`,
+			wantCode: http.StatusCreated,
+			want: &datastore.ScriptTemplate{
+				Name:        "gpu",
+				PreInstall:  "echo {{.RunnerName}}",
+				Environment: datastore.ScriptEnvironment{"CUDA_VISIBLE_DEVICES": "0"},
+				Labels:      datastore.ScriptLabels{"gpu"},
+			},
+		},
+		{ // already registered
+			input:    `{"name": "gpu"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{ // unknown value in template
+			input:    `{"name": "invalid-value", "post_install": "echo {{.Unknown}}"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{ // invalid syntax in template
+			input:    `{"name": "invalid-syntax", "script": "echo {{.RunnerName"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{ // invalid environment variable name
+			input:    `{"name": "invalid-env", "environment": {"1INVALID": "value"}}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{ // invalid label
+			input:    `{"name": "invalid-label", "labels": ["a,b"]}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		resp, err := http.Post(testURL+"/script_template", "application/json", bytes.NewBufferString(test.input))
+		if err != nil {
+			t.Fatalf("failed to POST request: %+v", err)
+		}
+		content, code := parseResponse(resp)
+		if code != test.wantCode {
+			t.Fatalf("must be response statuscode is %d, but got %d: %s", test.wantCode, code, string(content))
+		}
+		if test.want == nil {
+			continue
+		}
+
+		var got datastore.ScriptTemplate
+		if err := json.Unmarshal(content, &got); err != nil {
+			t.Fatalf("failed to unmarshal response content: %+v", err)
+		}
+		got.CreatedAt = time.Time{}
+		got.UpdatedAt = time.Time{}
+
+		if diff := cmp.Diff(test.want, &got); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func Test_handleScriptTemplateDelete(t *testing.T) {
+	testURL := testutils.GetTestURL()
+	_, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	setStubFunctions()
+
+	resp, err := http.Post(testURL+"/script_template", "application/json", bytes.NewBufferString(`{"name": "gpu"}`))
+	if err != nil {
+		t.Fatalf("failed to POST request: %+v", err)
+	}
+	if content, code := parseResponse(resp); code != http.StatusCreated {
+		t.Fatalf("must be response statuscode is 201, but got %d: %s", code, string(content))
+	}
+	resp, err = http.Post(testURL+"/target", "application/json", bytes.NewBufferString(`{"scope": "repo", "resource_type": "micro", "script_template": "gpu"}`))
+	if err != nil {
+		t.Fatalf("failed to POST request: %+v", err)
+	}
+	if content, code := parseResponse(resp); code != http.StatusCreated {
+		t.Fatalf("must be response statuscode is 201, but got %d: %s", code, string(content))
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/script_template/%s", testURL, "gpu"), nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %+v", err)
+	}
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to DELETE request: %+v", err)
+	}
+	if content, code := parseResponse(resp); code != http.StatusBadRequest {
+		t.Fatalf("must be response statuscode is 400 (template is used by target), but got %d: %s", code, string(content))
+	}
+
+	// template is used by paused target
+	resp, err = http.Post(testURL+"/script_template", "application/json", bytes.NewBufferString(`{"name": "cuda"}`))
+	if err != nil {
+		t.Fatalf("failed to POST request: %+v", err)
+	}
+	if content, code := parseResponse(resp); code != http.StatusCreated {
+		t.Fatalf("must be response statuscode is 201, but got %d: %s", code, string(content))
+	}
+	resp, err = http.Post(testURL+"/target", "application/json", bytes.NewBufferString(`{"scope": "octocat", "resource_type": "micro", "script_template": "cuda"}`))
+	if err != nil {
+		t.Fatalf("failed to POST request: %+v", err)
+	}
+	content, code := parseResponse(resp)
+	if code != http.StatusCreated {
+		t.Fatalf("must be response statuscode is 201, but got %d: %s", code, string(content))
+	}
+	var paused web.UserTarget
+	if err := json.Unmarshal(content, &paused); err != nil {
+		t.Fatalf("failed to unmarshal response: %+v", err)
+	}
+	resp, err = http.Post(fmt.Sprintf("%s/target/%s/pause", testURL, paused.UUID), "application/json", nil)
+	if err != nil {
+		t.Fatalf("failed to POST request: %+v", err)
+	}
+	if content, code := parseResponse(resp); code != http.StatusOK {
+		t.Fatalf("must be response statuscode is 200, but got %d: %s", code, string(content))
+	}
+
+	req, err = http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/script_template/%s", testURL, "cuda"), nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %+v", err)
+	}
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to DELETE request: %+v", err)
+	}
+	if content, code := parseResponse(resp); code != http.StatusBadRequest {
+		t.Fatalf("must be response statuscode is 400 (template is used by paused target), but got %d: %s", code, string(content))
+	}
+}
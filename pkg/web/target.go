@@ -15,6 +15,8 @@ import (
 	"github.com/whywaita/myshoes/pkg/datastore"
 	"github.com/whywaita/myshoes/pkg/gh"
 	"github.com/whywaita/myshoes/pkg/logger"
+	"github.com/whywaita/myshoes/pkg/runner"
+	"github.com/whywaita/myshoes/pkg/starter"
 
 	"goji.io/pat"
 )
@@ -23,9 +25,14 @@ import (
 type TargetCreateParam struct {
 	datastore.Target
 
-	GHEDomain   *string `json:"ghe_domain"`   // ignore
-	RunnerUser  *string `json:"runner_user"`  // nullable
-	ProviderURL *string `json:"provider_url"` // nullable
+	GHEDomain      *string `json:"ghe_domain"`      // ignore
+	RunnerUser     *string `json:"runner_user"`     // nullable
+	ProviderURL    *string `json:"provider_url"`    // nullable
+	RunnerGroup    *string `json:"runner_group"`    // nullable, only organization scope
+	UseJITConfig   *bool   `json:"use_jit_config"`  // nullable
+	ScriptTemplate *string `json:"script_template"` // nullable
+	RunnerOS       *string `json:"runner_os"`       // nullable, linux or windows
+	RunnerArch     *string `json:"runner_arch"`     // nullable, x64 or arm64
 }
 
 // UserTarget is format for user
@@ -35,6 +42,11 @@ type UserTarget struct {
 	TokenExpiredAt    time.Time              `json:"token_expired_at"`
 	ResourceType      string                 `json:"resource_type"`
 	ProviderURL       string                 `json:"provider_url"`
+	RunnerGroup       string                 `json:"runner_group"`
+	UseJITConfig      bool                   `json:"use_jit_config"`
+	ScriptTemplate    string                 `json:"script_template"`
+	RunnerOS          string                 `json:"runner_os"`
+	RunnerArch        string                 `json:"runner_arch"`
 	Status            datastore.TargetStatus `json:"status"`
 	StatusDescription string                 `json:"status_description"`
 	CreatedAt         time.Time              `json:"created_at"`
@@ -65,20 +77,26 @@ var (
 	GHGenerateGitHubAppsToken   = gh.GenerateGitHubAppsToken
 	GHNewClientApps             = gh.NewClientGitHubApps
 	GHPurgeInstallationCache    = gh.PurgeInstallationCache
+	GHExistRunnerGroupFunc      = gh.ExistRunnerGroup
+	RunnerIssueTokenFunc        = runner.IssueToken
 )
 
 func handleTargetList(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
 	ctx := r.Context()
 
-	ts, err := datastore.ListTargets(ctx, ds)
+	ts, err := ds.ListTargets(ctx)
 	if err != nil {
 		logger.Logf(false, "failed to retrieve list of target: %+v", err)
 		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
 	}
 
-	fmt.Println(ts)
 	var targets []UserTarget
 	for _, t := range ts {
+		// paused target is listed
+		if t.Status == datastore.TargetStatusDeleted || !canAccessTarget(r, t.Scope) {
+			continue
+		}
 		ut := sanitizeTarget(t)
 		targets = append(targets, ut)
 	}
@@ -105,6 +123,10 @@ func handleTargetRead(w http.ResponseWriter, r *http.Request, ds datastore.Datas
 		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
 		return
 	}
+	if !canAccessTarget(r, target.Scope) {
+		outputErrorMsg(w, http.StatusForbidden, "target is out of scope of token")
+		return
+	}
 
 	ut := sanitizeTarget(*target)
 
@@ -120,6 +142,11 @@ func sanitizeTarget(t datastore.Target) UserTarget {
 		TokenExpiredAt:    t.TokenExpiredAt,
 		ResourceType:      t.ResourceType.String(),
 		ProviderURL:       t.ProviderURL.String,
+		RunnerGroup:       t.RunnerGroup.String,
+		UseJITConfig:      t.UseJITConfig,
+		ScriptTemplate:    t.ScriptTemplate.String,
+		RunnerOS:          string(t.GetRunnerOS()),
+		RunnerArch:        string(t.GetRunnerArch()),
 		Status:            t.Status,
 		StatusDescription: t.StatusDescription.String,
 		CreatedAt:         t.CreatedAt,
@@ -144,6 +171,14 @@ func handleTargetUpdate(w http.ResponseWriter, r *http.Request, ds datastore.Dat
 		outputErrorMsg(w, http.StatusBadRequest, "json decode error")
 		return
 	}
+	if err := isValidRunnerOS(inputTarget.RunnerOS); err != nil {
+		outputErrorMsg(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := isValidRunnerArch(inputTarget.RunnerArch); err != nil {
+		outputErrorMsg(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	newTarget := inputTarget.ToDS("", time.Time{})
 
 	oldTarget, err := ds.GetTarget(ctx, targetID)
@@ -152,20 +187,37 @@ func handleTargetUpdate(w http.ResponseWriter, r *http.Request, ds datastore.Dat
 		outputErrorMsg(w, http.StatusBadRequest, "incorrect target id (not found)")
 		return
 	}
+	if !canAccessTarget(r, oldTarget.Scope) {
+		outputErrorMsg(w, http.StatusForbidden, "target is out of scope of token")
+		return
+	}
 	if err := validateUpdateTarget(*oldTarget, newTarget); err != nil {
 		logger.Logf(false, "input error in validateUpdateTarget: %+v", err)
 		outputErrorMsg(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	resourceType, providerURL := getWillUpdateTargetVariable(getWillUpdateTargetVariableOld{
-		resourceType: oldTarget.ResourceType,
-		providerURL:  oldTarget.ProviderURL,
-	}, getWillUpdateTargetVariableNew{
-		resourceType: inputTarget.ResourceType,
-		providerURL:  inputTarget.ProviderURL,
-	})
-	if err := ds.UpdateTargetParam(ctx, targetID, resourceType, providerURL); err != nil {
+	param := getWillUpdateTargetVariable(*oldTarget, inputTarget)
+	if param.RunnerGroup.Valid && param.RunnerGroup != oldTarget.RunnerGroup {
+		// stored token may be expired, issue a fresh token to validate
+		token, err := RunnerIssueTokenFunc(ctx, ds, *oldTarget)
+		if err != nil {
+			logger.Logf(false, "failed to issue token (target: %s): %+v", oldTarget.UUID, err)
+			outputErrorMsg(w, http.StatusInternalServerError, "failed to generate GitHub Apps token")
+			return
+		}
+		if err := isValidRunnerGroup(ctx, oldTarget.Scope, param.RunnerGroup.String, token); err != nil {
+			outputErrorMsg(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if param.ScriptTemplate.Valid && param.ScriptTemplate != oldTarget.ScriptTemplate {
+		if err := isValidScriptTemplate(ctx, ds, param.ScriptTemplate.String); err != nil {
+			outputErrorMsg(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if err := ds.UpdateTargetParam(ctx, targetID, param); err != nil {
 		logger.Logf(false, "failed to ds.UpdateTargetParam: %+v", err)
 		outputErrorMsg(w, http.StatusInternalServerError, "datastore update error")
 		return
@@ -178,6 +230,8 @@ func handleTargetUpdate(w http.ResponseWriter, r *http.Request, ds datastore.Dat
 		return
 	}
 	ut := sanitizeTarget(*updatedTarget)
+	before := sanitizeTarget(*oldTarget)
+	recordTargetAuditLog(r, ds, auditActionTargetUpdate, &before, &ut)
 
 	w.Header().Set("Content-Type", "application/json;charset=utf-8")
 	w.WriteHeader(http.StatusOK)
@@ -199,6 +253,10 @@ func handleTargetDelete(w http.ResponseWriter, r *http.Request, ds datastore.Dat
 		outputErrorMsg(w, http.StatusBadRequest, "incorrect target id (not found)")
 		return
 	}
+	if !canAccessTarget(r, target.Scope) {
+		outputErrorMsg(w, http.StatusForbidden, "target is out of scope of token")
+		return
+	}
 	switch target.Status {
 	case datastore.TargetStatusRunning:
 		logger.Logf(true, "%s is running now", targetID)
@@ -214,6 +272,10 @@ func handleTargetDelete(w http.ResponseWriter, r *http.Request, ds datastore.Dat
 		outputErrorMsg(w, http.StatusInternalServerError, "datastore delete error")
 		return
 	}
+	before := sanitizeTarget(*target)
+	recordTargetAuditLog(r, ds, auditActionTargetDelete, &before, nil)
+	starter.DeleteTargetMetrics(target.Scope)
+	runner.DeleteTargetMetrics(target.Scope)
 
 	w.Header().Set("Content-Type", "application/json;charset=utf-8")
 	w.WriteHeader(http.StatusNoContent)
@@ -253,6 +315,11 @@ func validateUpdateTarget(old, new datastore.Target) error {
 		// can update variables
 		t.ResourceType = datastore.ResourceTypeUnknown
 		t.ProviderURL = sql.NullString{}
+		t.RunnerGroup = sql.NullString{}
+		t.UseJITConfig = false
+		t.ScriptTemplate = sql.NullString{}
+		t.RunnerOS = ""
+		t.RunnerArch = ""
 
 		// time
 		t.TokenExpiredAt = time.Time{}
@@ -293,10 +360,52 @@ func isValidTargetCreateParam(input TargetCreateParam) error {
 	if input.Scope == "" || input.ResourceType == datastore.ResourceTypeUnknown {
 		return fmt.Errorf("scope, resource_type must be set")
 	}
+	if err := isValidRunnerOS(input.RunnerOS); err != nil {
+		return err
+	}
+	if err := isValidRunnerArch(input.RunnerArch); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func isValidRunnerOS(input *string) error {
+	if input == nil || *input == "" {
+		return nil
+	}
+	if datastore.UnmarshalRunnerOS(*input) == "" {
+		return fmt.Errorf("runner_os must be %s or %s", datastore.RunnerOSLinux, datastore.RunnerOSWindows)
+	}
 
 	return nil
 }
 
+func toRunnerOS(input *string) datastore.RunnerOS {
+	if input == nil {
+		return ""
+	}
+	return datastore.UnmarshalRunnerOS(*input)
+}
+
+func isValidRunnerArch(input *string) error {
+	if input == nil || *input == "" {
+		return nil
+	}
+	if datastore.UnmarshalRunnerArch(*input) == "" {
+		return fmt.Errorf("runner_arch must be %s or %s", datastore.RunnerArchX64, datastore.RunnerArchARM64)
+	}
+
+	return nil
+}
+
+func toRunnerArch(input *string) datastore.RunnerArch {
+	if input == nil {
+		return ""
+	}
+	return datastore.UnmarshalRunnerArch(*input)
+}
+
 func toNullString(input *string) sql.NullString {
 	if input == nil || strings.EqualFold(*input, "") {
 		return sql.NullString{
@@ -313,6 +422,9 @@ func toNullString(input *string) sql.NullString {
 // ToDS convert to datastore.Target
 func (t *TargetCreateParam) ToDS(appToken string, tokenExpired time.Time) datastore.Target {
 	providerURL := toNullString(t.ProviderURL)
+	runnerGroup := toNullString(t.RunnerGroup)
+	useJITConfig := t.UseJITConfig != nil && *t.UseJITConfig
+	scriptTemplate := toNullString(t.ScriptTemplate)
 
 	return datastore.Target{
 		UUID:           t.UUID,
@@ -321,28 +433,46 @@ func (t *TargetCreateParam) ToDS(appToken string, tokenExpired time.Time) datast
 		TokenExpiredAt: tokenExpired,
 		ResourceType:   t.ResourceType,
 		ProviderURL:    providerURL,
+		RunnerGroup:    runnerGroup,
+		UseJITConfig:   useJITConfig,
+		ScriptTemplate: scriptTemplate,
+		RunnerOS:       toRunnerOS(t.RunnerOS),
+		RunnerArch:     toRunnerArch(t.RunnerArch),
 	}
 }
 
-type getWillUpdateTargetVariableOld struct {
-	resourceType datastore.ResourceType
-	providerURL  sql.NullString
-}
+// getWillUpdateTargetVariable return parameters that will update.
+// a parameter that is not set in input is not changed from old target.
+func getWillUpdateTargetVariable(old datastore.Target, input TargetCreateParam) datastore.TargetParam {
+	rt := old.ResourceType
+	if input.ResourceType != datastore.ResourceTypeUnknown {
+		rt = input.ResourceType
+	}
 
-type getWillUpdateTargetVariableNew struct {
-	resourceType datastore.ResourceType
-	providerURL  *string
-}
+	useJITConfig := old.UseJITConfig
+	if input.UseJITConfig != nil {
+		useJITConfig = *input.UseJITConfig
+	}
 
-func getWillUpdateTargetVariable(oldParam getWillUpdateTargetVariableOld, newParam getWillUpdateTargetVariableNew) (datastore.ResourceType, sql.NullString) {
-	rt := oldParam.resourceType
-	if newParam.resourceType != datastore.ResourceTypeUnknown {
-		rt = newParam.resourceType
+	runnerOS := old.RunnerOS
+	if input.RunnerOS != nil {
+		runnerOS = toRunnerOS(input.RunnerOS)
 	}
 
-	providerURL := getWillUpdateTargetVariableString(oldParam.providerURL, newParam.providerURL)
+	runnerArch := old.RunnerArch
+	if input.RunnerArch != nil {
+		runnerArch = toRunnerArch(input.RunnerArch)
+	}
 
-	return rt, providerURL
+	return datastore.TargetParam{
+		ResourceType:   rt,
+		ProviderURL:    getWillUpdateTargetVariableString(old.ProviderURL, input.ProviderURL),
+		RunnerGroup:    getWillUpdateTargetVariableString(old.RunnerGroup, input.RunnerGroup),
+		UseJITConfig:   useJITConfig,
+		ScriptTemplate: getWillUpdateTargetVariableString(old.ScriptTemplate, input.ScriptTemplate),
+		RunnerOS:       runnerOS,
+		RunnerArch:     runnerArch,
+	}
 }
 
 func getWillUpdateTargetVariableString(old sql.NullString, new *string) sql.NullString {
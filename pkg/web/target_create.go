@@ -15,6 +15,7 @@ import (
 	"github.com/whywaita/myshoes/pkg/datastore"
 	"github.com/whywaita/myshoes/pkg/gh"
 	"github.com/whywaita/myshoes/pkg/logger"
+	"github.com/whywaita/myshoes/pkg/starter"
 )
 
 func handleTargetCreate(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
@@ -32,6 +33,11 @@ func handleTargetCreate(w http.ResponseWriter, r *http.Request, ds datastore.Dat
 		outputErrorMsg(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if !canAccessTarget(r, inputTarget.Scope) {
+		logger.Logf(false, "%s can not create target of %s", principalName(r), inputTarget.Scope)
+		outputErrorMsg(w, http.StatusForbidden, "target is out of scope of token")
+		return
+	}
 	if err := GHPurgeInstallationCache(ctx); err != nil {
 		logger.Logf(false, "failed to purge installation cache: %+v", err)
 		outputErrorMsg(w, http.StatusInternalServerError, "failed to purge installation cache")
@@ -63,6 +69,18 @@ func handleTargetCreate(w http.ResponseWriter, r *http.Request, ds datastore.Dat
 		outputErrorMsg(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if t.RunnerGroup.Valid {
+		if err := isValidRunnerGroup(ctx, t.Scope, t.RunnerGroup.String, token); err != nil {
+			outputErrorMsg(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if t.ScriptTemplate.Valid {
+		if err := isValidScriptTemplate(ctx, ds, t.ScriptTemplate.String); err != nil {
+			outputErrorMsg(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
 
 	target, err := ds.GetTargetByScope(ctx, t.Scope)
 	var targetUUID uuid.UUID
@@ -88,20 +106,29 @@ func handleTargetCreate(w http.ResponseWriter, r *http.Request, ds datastore.Dat
 		return
 	case target.Status == datastore.TargetStatusDeleted:
 		// deleted, need to recreate
+		param := getWillUpdateTargetVariable(*target, inputTarget)
+		if param.RunnerGroup.Valid && !t.RunnerGroup.Valid {
+			// runner group is inherited from deleted target, need to check it again
+			if err := isValidRunnerGroup(ctx, t.Scope, param.RunnerGroup.String, token); err != nil {
+				outputErrorMsg(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if param.ScriptTemplate.Valid && !t.ScriptTemplate.Valid {
+			// script template is inherited from deleted target, need to check it again
+			if err := isValidScriptTemplate(ctx, ds, param.ScriptTemplate.String); err != nil {
+				outputErrorMsg(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		// reactivate after all checks, a target is kept deleted if input is invalid
 		//lint:ignore SA1019 ds.UpdateTargetStatus only use under.
 		if err := ds.UpdateTargetStatus(ctx, target.UUID, datastore.TargetStatusActive, ""); err != nil {
 			logger.Logf(false, "failed to recreate target: %+v", err)
 			outputErrorMsg(w, http.StatusInternalServerError, "datastore recreate error")
 			return
 		}
-		resourceType, providerURL := getWillUpdateTargetVariable(getWillUpdateTargetVariableOld{
-			resourceType: target.ResourceType,
-			providerURL:  target.ProviderURL,
-		}, getWillUpdateTargetVariableNew{
-			resourceType: inputTarget.ResourceType,
-			providerURL:  inputTarget.ProviderURL,
-		})
-		if err := ds.UpdateTargetParam(ctx, target.UUID, resourceType, providerURL); err != nil {
+		if err := ds.UpdateTargetParam(ctx, target.UUID, param); err != nil {
 			logger.Logf(false, "failed to update resource type in recreating target: %+v", err)
 			outputErrorMsg(w, http.StatusInternalServerError, "update resource type error")
 			return
@@ -117,6 +144,7 @@ func handleTargetCreate(w http.ResponseWriter, r *http.Request, ds datastore.Dat
 		return
 	}
 	ut := sanitizeTarget(*createdTarget)
+	recordTargetAuditLog(r, ds, auditActionTargetCreate, nil, &ut)
 
 	w.Header().Set("Content-Type", "application/json;charset=utf-8")
 	w.WriteHeader(http.StatusCreated)
@@ -143,6 +171,40 @@ func isValidScopeAndToken(ctx context.Context, scope, githubPersonalToken string
 	return nil
 }
 
+// isValidRunnerGroup check that runner group is exist in organization
+func isValidRunnerGroup(ctx context.Context, scope, runnerGroup, githubToken string) error {
+	if gh.DetectScope(scope) != gh.Organization {
+		return fmt.Errorf("runner_group can be set only organization scope")
+	}
+
+	client, err := gh.NewClient(githubToken)
+	if err != nil {
+		logger.Logf(false, "failed to create GitHub client: %+v", err)
+		return fmt.Errorf("invalid github token in input scope")
+	}
+	if _, err := GHExistRunnerGroupFunc(ctx, client, scope, runnerGroup); err != nil {
+		logger.Logf(false, "failed to get runner group (scope: %s, runner_group: %s): %+v", scope, runnerGroup, err)
+		if errors.Is(err, gh.ErrNotFound) {
+			return fmt.Errorf("runner_group %s is not found in %s", runnerGroup, scope)
+		}
+		return fmt.Errorf("failed to get runner group (maybe, invalid scope or token?)")
+	}
+
+	return nil
+}
+
+func isValidScriptTemplate(ctx context.Context, ds datastore.Datastore, name string) error {
+	if _, err := starter.GetScriptTemplate(ctx, ds, name); err != nil {
+		logger.Logf(false, "failed to get script template (name: %s): %+v", name, err)
+		if errors.Is(err, datastore.ErrNotFound) {
+			return fmt.Errorf("script_template %s is not found", name)
+		}
+		return fmt.Errorf("failed to get script template")
+	}
+
+	return nil
+}
+
 func createNewTarget(ctx context.Context, input datastore.Target, ds datastore.Datastore) (*uuid.UUID, error) {
 	input.UUID = uuid.NewV4()
 	now := time.Now().UTC()
@@ -0,0 +1,94 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/logger"
+)
+
+// TargetPauseParam is parameter for pause target
+type TargetPauseParam struct {
+	Reason string `json:"reason"`
+}
+
+func handleTargetPause(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	input := TargetPauseParam{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		logger.Logf(false, "failed to decode request body: %+v", err)
+		outputErrorMsg(w, http.StatusBadRequest, "json decode error")
+		return
+	}
+
+	updateTargetPause(w, r, ds, true, input.Reason)
+}
+
+func handleTargetResume(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	updateTargetPause(w, r, ds, false, "")
+}
+
+// updateTargetPause switch status of target to suspend (pause) or active (resume)
+func updateTargetPause(w http.ResponseWriter, r *http.Request, ds datastore.Datastore, pause bool, reason string) {
+	ctx := r.Context()
+	targetID, err := parseReqTargetID(r)
+	if err != nil {
+		logger.Logf(false, "failed to parse target id: %+v", err)
+		outputErrorMsg(w, http.StatusBadRequest, "incorrect target id")
+		return
+	}
+
+	target, err := ds.GetTarget(ctx, targetID)
+	switch {
+	case errors.Is(err, datastore.ErrNotFound):
+		outputErrorMsg(w, http.StatusNotFound, "target is not found")
+		return
+	case err != nil:
+		logger.Logf(false, "failed to retrieve target from datastore: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+	if !canAccessTarget(r, target.Scope) {
+		outputErrorMsg(w, http.StatusForbidden, "target is out of scope of token")
+		return
+	}
+
+	newStatus, action := datastore.TargetStatus(datastore.TargetStatusSuspend), auditActionTargetPause
+	switch {
+	case target.Status == datastore.TargetStatusDeleted:
+		outputErrorMsg(w, http.StatusBadRequest, "target is already deleted")
+		return
+	case pause && target.Status == datastore.TargetStatusSuspend:
+		outputErrorMsg(w, http.StatusBadRequest, "target is already paused")
+		return
+	case !pause && target.Status != datastore.TargetStatusSuspend:
+		outputErrorMsg(w, http.StatusBadRequest, "target is not paused")
+		return
+	case !pause:
+		newStatus, action = datastore.TargetStatusActive, auditActionTargetResume
+	}
+
+	// datastore.UpdateTargetStatus can not change status of suspended target
+	//lint:ignore SA1019 need to change status from suspend
+	if err := ds.UpdateTargetStatus(ctx, targetID, newStatus, reason); err != nil {
+		logger.Logf(false, "failed to update target status: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore update error")
+		return
+	}
+
+	updatedTarget, err := ds.GetTarget(ctx, targetID)
+	if err != nil {
+		logger.Logf(false, "failed to get recently target in datastore: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore get error")
+		return
+	}
+	ut := sanitizeTarget(*updatedTarget)
+	before := sanitizeTarget(*target)
+	recordTargetAuditLog(r, ds, action, &before, &ut)
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ut)
+}
@@ -0,0 +1,68 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/logger"
+)
+
+// UserTargetStatusHistory is type for API response of status history
+type UserTargetStatusHistory struct {
+	Status      datastore.TargetStatus `json:"status"`
+	Description string                 `json:"description"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+func handleTargetStatusHistoryList(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	ctx := r.Context()
+	targetID, err := parseReqTargetID(r)
+	if err != nil {
+		logger.Logf(false, "failed to parse target id: %+v", err)
+		outputErrorMsg(w, http.StatusBadRequest, "incorrect target id")
+		return
+	}
+	lq, err := parseListQuery(r)
+	if err != nil {
+		outputErrorMsg(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	target, err := ds.GetTarget(ctx, targetID)
+	switch {
+	case errors.Is(err, datastore.ErrNotFound):
+		outputErrorMsg(w, http.StatusNotFound, "target is not found")
+		return
+	case err != nil:
+		logger.Logf(false, "failed to retrieve target from datastore: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+	if !canAccessTarget(r, target.Scope) {
+		outputErrorMsg(w, http.StatusForbidden, "target is out of scope of token")
+		return
+	}
+
+	histories, err := ds.ListTargetStatusHistory(ctx, targetID, lq.Limit, lq.Offset)
+	if err != nil {
+		logger.Logf(false, "failed to retrieve status history of target: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+
+	var userHistories []UserTargetStatusHistory
+	for _, h := range histories {
+		userHistories = append(userHistories, UserTargetStatusHistory{
+			Status:      h.Status,
+			Description: h.Description,
+			CreatedAt:   h.CreatedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(userHistories)
+}
@@ -0,0 +1,75 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/internal/testutils"
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/web"
+)
+
+func Test_handleTargetStatusHistoryList(t *testing.T) {
+	testURL := testutils.GetTestURL()
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	ctx := context.Background()
+	targetID := uuid.NewV4()
+	if err := testDatastore.CreateTarget(ctx, datastore.Target{
+		UUID:           targetID,
+		Scope:          "octocat",
+		TokenExpiredAt: testTime,
+		ResourceType:   datastore.ResourceTypeNano,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+	if err := datastore.UpdateTargetStatus(ctx, testDatastore, targetID, datastore.TargetStatusErr, "failed to create an instance"); err != nil {
+		t.Fatalf("failed to update target status: %+v", err)
+	}
+
+	tests := []struct {
+		path     string
+		wantCode int
+		want     []datastore.TargetStatus
+	}{
+		{path: fmt.Sprintf("/target/%s/status-history", targetID), wantCode: http.StatusOK, want: []datastore.TargetStatus{datastore.TargetStatusErr, datastore.TargetStatusActive}},
+		{path: fmt.Sprintf("/target/%s/status-history?limit=1", targetID), wantCode: http.StatusOK, want: []datastore.TargetStatus{datastore.TargetStatusErr}},
+		{path: fmt.Sprintf("/target/%s/status-history", uuid.NewV4()), wantCode: http.StatusNotFound},
+		{path: "/target/invalid/status-history", wantCode: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		resp, err := http.Get(testURL + test.path)
+		if err != nil {
+			t.Fatalf("failed to request: %+v", err)
+		}
+		content, code := parseResponse(resp)
+		if code != test.wantCode {
+			t.Fatalf("must be response statuscode is %d, but got %d (%s): %s", test.wantCode, code, test.path, string(content))
+		}
+		if code != http.StatusOK {
+			continue
+		}
+
+		var got []web.UserTargetStatusHistory
+		if err := json.Unmarshal(content, &got); err != nil {
+			t.Fatalf("failed to unmarshal response: %+v", err)
+		}
+		if len(got) != len(test.want) {
+			t.Fatalf("must be %d histories, but got %d", len(test.want), len(got))
+		}
+		for i, h := range got {
+			if h.Status != test.want[i] {
+				t.Errorf("must be status %s, but got %s", test.want[i], h.Status)
+			}
+		}
+		if got[0].Description != "failed to create an instance" {
+			t.Errorf("must be latest description, but got %s", got[0].Description)
+		}
+	}
+}
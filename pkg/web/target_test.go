@@ -62,6 +62,14 @@ func setStubFunctions() {
 	web.GHPurgeInstallationCache = func(ctx context.Context) error {
 		return nil
 	}
+
+	web.RunnerIssueTokenFunc = func(ctx context.Context, ds datastore.Datastore, target datastore.Target) (string, error) {
+		return testGitHubAppToken, nil
+	}
+
+	web.GHExistRunnerGroupFunc = func(ctx context.Context, client *github.Client, org, runnerGroupName string) (*github.RunnerGroup, error) {
+		return &github.RunnerGroup{Name: &runnerGroupName}, nil
+	}
 }
 
 func Test_handleTargetCreate(t *testing.T) {
@@ -83,6 +91,8 @@ func Test_handleTargetCreate(t *testing.T) {
 				Scope:          "octocat",
 				TokenExpiredAt: testTime,
 				ResourceType:   datastore.ResourceTypeMicro.String(),
+				RunnerOS:       "linux",
+				RunnerArch:     "x64",
 				Status:         datastore.TargetStatusActive,
 			},
 			err: false,
@@ -93,6 +103,8 @@ func Test_handleTargetCreate(t *testing.T) {
 				Scope:          "whywaita/whywaita",
 				TokenExpiredAt: testTime,
 				ResourceType:   datastore.ResourceTypeNano.String(),
+				RunnerOS:       "linux",
+				RunnerArch:     "x64",
 				Status:         datastore.TargetStatusActive,
 			},
 		},
@@ -102,6 +114,43 @@ func Test_handleTargetCreate(t *testing.T) {
 				Scope:          "whywaita/whywaita2",
 				TokenExpiredAt: testTime,
 				ResourceType:   datastore.ResourceTypeNano.String(),
+				RunnerOS:       "linux",
+				RunnerArch:     "x64",
+				Status:         datastore.TargetStatusActive,
+			},
+		},
+		{ // Set runner group in organization scope
+			input: `{"scope": "whywaita", "resource_type": "nano", "runner_user": "runner", "runner_group": "expensive-runners"}`,
+			want: &web.UserTarget{
+				Scope:          "whywaita",
+				TokenExpiredAt: testTime,
+				ResourceType:   datastore.ResourceTypeNano.String(),
+				RunnerOS:       "linux",
+				RunnerArch:     "x64",
+				RunnerGroup:    "expensive-runners",
+				Status:         datastore.TargetStatusActive,
+			},
+		},
+		{ // Windows runner
+			input: `{"scope": "whywaita/whywaita4", "resource_type": "nano", "runner_user": "runner", "runner_os": "windows", "runner_arch": "arm64"}`,
+			want: &web.UserTarget{
+				Scope:          "whywaita/whywaita4",
+				TokenExpiredAt: testTime,
+				ResourceType:   datastore.ResourceTypeNano.String(),
+				RunnerOS:       "windows",
+				RunnerArch:     "arm64",
+				Status:         datastore.TargetStatusActive,
+			},
+		},
+		{ // Use just-in-time configuration
+			input: `{"scope": "whywaita/whywaita3", "resource_type": "nano", "runner_user": "runner", "use_jit_config": true}`,
+			want: &web.UserTarget{
+				Scope:          "whywaita/whywaita3",
+				TokenExpiredAt: testTime,
+				ResourceType:   datastore.ResourceTypeNano.String(),
+				RunnerOS:       "linux",
+				RunnerArch:     "x64",
+				UseJITConfig:   true,
 				Status:         datastore.TargetStatusActive,
 			},
 		},
@@ -286,6 +335,51 @@ func Test_handleTargetCreate_recreated_update(t *testing.T) {
 	}
 }
 
+func Test_handleTargetCreate_recreated_invalid(t *testing.T) {
+	testURL := testutils.GetTestURL()
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	setStubFunctions()
+
+	do := func(method, path, body string, wantCode int) []byte {
+		t.Helper()
+		req, err := http.NewRequest(method, testURL+path, bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatalf("failed to create request: %+v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to request: %+v", err)
+		}
+		content, code := parseResponse(resp)
+		if code != wantCode {
+			t.Fatalf("must be response statuscode is %d, but got %d (%s %s): %s", wantCode, code, method, path, string(content))
+		}
+		return content
+	}
+
+	do(http.MethodPost, "/script_template", `{"name": "gpu"}`, http.StatusCreated)
+	content := do(http.MethodPost, "/target", `{"scope": "octocat", "resource_type": "micro", "script_template": "gpu"}`, http.StatusCreated)
+	var created web.UserTarget
+	if err := json.Unmarshal(content, &created); err != nil {
+		t.Fatalf("failed to unmarshal resoponse content: %+v", err)
+	}
+	do(http.MethodDelete, "/target/"+created.UUID.String(), "", http.StatusNoContent)
+	do(http.MethodDelete, "/script_template/gpu", "", http.StatusNoContent)
+
+	// inherited script template is not found
+	do(http.MethodPost, "/target", `{"scope": "octocat", "resource_type": "micro"}`, http.StatusBadRequest)
+
+	got, err := testDatastore.GetTarget(context.Background(), created.UUID)
+	if err != nil {
+		t.Fatalf("failed to get target: %+v", err)
+	}
+	if got.Status != datastore.TargetStatusDeleted {
+		t.Fatalf("target must be kept deleted if recreate is failed, but got %s", got.Status)
+	}
+}
+
 func Test_handleTargetList(t *testing.T) {
 	testURL := testutils.GetTestURL()
 	_, teardown := testutils.GetTestDatastore()
@@ -317,6 +411,8 @@ func Test_handleTargetList(t *testing.T) {
 					Scope:          "reponano",
 					TokenExpiredAt: testTime,
 					ResourceType:   datastore.ResourceTypeNano.String(),
+					RunnerOS:       "linux",
+					RunnerArch:     "x64",
 					Status:         datastore.TargetStatusActive,
 				},
 				{
@@ -391,6 +487,8 @@ func Test_handleTargetRead(t *testing.T) {
 				Scope:          "repo",
 				TokenExpiredAt: testTime,
 				ResourceType:   datastore.ResourceTypeMicro.String(),
+				RunnerOS:       "linux",
+				RunnerArch:     "x64",
 				Status:         datastore.TargetStatusActive,
 			},
 		},
@@ -439,6 +537,8 @@ func Test_handleTargetUpdate(t *testing.T) {
 				Scope:          "repo",
 				TokenExpiredAt: testTime,
 				ResourceType:   datastore.ResourceTypeNano.String(),
+				RunnerOS:       "linux",
+				RunnerArch:     "x64",
 				ProviderURL:    "https://example.com/default-shoes",
 				Status:         datastore.TargetStatusActive,
 			},
@@ -450,6 +550,8 @@ func Test_handleTargetUpdate(t *testing.T) {
 				Scope:          "repo",
 				TokenExpiredAt: testTime,
 				ResourceType:   datastore.ResourceTypeNano.String(),
+				RunnerOS:       "linux",
+				RunnerArch:     "x64",
 				ProviderURL:    "https://example.com/default-shoes",
 				Status:         datastore.TargetStatusActive,
 			},
@@ -461,6 +563,8 @@ func Test_handleTargetUpdate(t *testing.T) {
 				Scope:          "repo",
 				TokenExpiredAt: testTime,
 				ResourceType:   datastore.ResourceTypeMicro.String(),
+				RunnerOS:       "linux",
+				RunnerArch:     "x64",
 				ProviderURL:    "https://example.com/shoes-provider",
 				Status:         datastore.TargetStatusActive,
 			},
@@ -472,6 +576,8 @@ func Test_handleTargetUpdate(t *testing.T) {
 				Scope:          "repo",
 				TokenExpiredAt: testTime,
 				ResourceType:   datastore.ResourceTypeNano.String(),
+				RunnerOS:       "linux",
+				RunnerArch:     "x64",
 				ProviderURL:    "https://example.com/default-shoes",
 				Status:         datastore.TargetStatusActive,
 			},
@@ -483,6 +589,8 @@ func Test_handleTargetUpdate(t *testing.T) {
 				Scope:          "repo",
 				TokenExpiredAt: testTime,
 				ResourceType:   datastore.ResourceTypeNano.String(),
+				RunnerOS:       "linux",
+				RunnerArch:     "x64",
 				ProviderURL:    "",
 				Status:         datastore.TargetStatusActive,
 			},
@@ -0,0 +1,157 @@
+package web
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/config"
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/logger"
+)
+
+// defaultUsagePeriod is period of usage if from is not specified
+const defaultUsagePeriod = 30 * 24 * time.Hour
+
+func handleUsage(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
+	q := r.URL.Query()
+	from, to, err := parseUsagePeriod(q.Get("from"), q.Get("to"), time.Now().UTC())
+	if err != nil {
+		outputErrorMsg(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	groupBy, err := datastore.ParseUsageGroupBy(q.Get("group_by"))
+	if err != nil {
+		outputErrorMsg(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	format := q.Get("format")
+	if format == "" && strings.Contains(r.Header.Get("Accept"), "text/csv") {
+		format = "csv"
+	}
+	if format != "" && format != "json" && format != "csv" {
+		outputErrorMsg(w, http.StatusBadRequest, "format must be json or csv")
+		return
+	}
+
+	targets, err := ds.ListTargets(r.Context())
+	if err != nil {
+		logger.Logf(false, "failed to retrieve list of target: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+	scopes := map[uuid.UUID]string{}
+	for _, t := range targets {
+		scopes[t.UUID] = t.Scope
+	}
+
+	runners, err := ds.ListRunnersByPeriod(r.Context(), from, to)
+	if err != nil {
+		logger.Logf(false, "failed to retrieve list of runner: %+v", err)
+		outputErrorMsg(w, http.StatusInternalServerError, "datastore read error")
+		return
+	}
+	var accessible []datastore.Runner
+	for _, runner := range runners {
+		scope, ok := scopes[runner.TargetID]
+		if !ok {
+			scope = runner.TargetID.String()
+		}
+		if canAccessTarget(r, scope) {
+			accessible = append(accessible, runner)
+		}
+	}
+
+	usages := datastore.CalculateUsage(accessible, scopes, from, to, groupBy, config.Config.PriceTable)
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv;charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="usage_%s_%s.csv"`, from.Format("20060102"), to.Format("20060102")))
+		w.WriteHeader(http.StatusOK)
+		if err := writeUsageCSV(w, usages, groupBy); err != nil {
+			logger.Logf(false, "failed to write usage as csv: %+v", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(usages)
+}
+
+// parseUsagePeriod parse from and to as RFC3339, default is last 30 days until now
+func parseUsagePeriod(fromStr, toStr string, now time.Time) (time.Time, time.Time, error) {
+	to := now
+	if toStr != "" {
+		t, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("to must be RFC3339 format: %w", err)
+		}
+		to = t
+	}
+	// running runners are counted until now
+	if to.After(now) {
+		to = now
+	}
+
+	from := to.Add(-defaultUsagePeriod)
+	if fromStr != "" {
+		f, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("from must be RFC3339 format: %w", err)
+		}
+		from = f
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from must be before to")
+	}
+	return from, to, nil
+}
+
+// writeUsageCSV write usages as CSV, columns are grouped keys and values
+func writeUsageCSV(w http.ResponseWriter, usages []datastore.Usage, groupBy []datastore.UsageGroupBy) error {
+	cw := csv.NewWriter(w)
+
+	var header []string
+	for _, g := range groupBy {
+		header = append(header, string(g))
+	}
+	header = append(header, "runners", "runner_minutes", "estimated_cost")
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	for _, u := range usages {
+		var record []string
+		for _, g := range groupBy {
+			switch g {
+			case datastore.UsageGroupByTarget:
+				record = append(record, u.Target)
+			case datastore.UsageGroupByRepository:
+				record = append(record, u.Repository)
+			case datastore.UsageGroupByResourceType:
+				record = append(record, u.ResourceType)
+			case datastore.UsageGroupByProvider:
+				record = append(record, u.Provider)
+			}
+		}
+		record = append(record,
+			strconv.Itoa(u.Runners),
+			strconv.FormatFloat(u.RunnerMinutes, 'f', 2, 64),
+			strconv.FormatFloat(u.EstimatedCost, 'f', 4, 64),
+		)
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,106 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/internal/testutils"
+	"github.com/whywaita/myshoes/pkg/datastore"
+)
+
+func Test_handleUsage(t *testing.T) {
+	testURL := testutils.GetTestURL()
+	testDatastore, teardown := testutils.GetTestDatastore()
+	defer teardown()
+
+	ctx := context.Background()
+	targetID := uuid.NewV4()
+	if err := testDatastore.CreateTarget(ctx, datastore.Target{
+		UUID:           targetID,
+		Scope:          "octocat",
+		TokenExpiredAt: testTime,
+		ResourceType:   datastore.ResourceTypeNano,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+	for _, resourceType := range []datastore.ResourceType{datastore.ResourceTypeNano, datastore.ResourceTypeNano, datastore.ResourceTypeLarge} {
+		runnerID := uuid.NewV4()
+		if err := testDatastore.CreateRunner(ctx, datastore.Runner{
+			UUID:           runnerID,
+			ShoesType:      "shoes-test",
+			TargetID:       targetID,
+			CloudID:        "mycloud-uuid",
+			ResourceType:   resourceType,
+			RepositoryURL:  "https://github.com/octocat/Hello-World",
+			RequestWebhook: "{}",
+		}); err != nil {
+			t.Fatalf("failed to create runner: %+v", err)
+		}
+	}
+	// wait for runners to be running in period
+	time.Sleep(1 * time.Second)
+
+	tests := []struct {
+		path     string
+		wantCode int
+		want     map[string]int
+	}{
+		{path: "/usage", wantCode: http.StatusOK, want: map[string]int{"octocat": 3}},
+		{path: "/usage?group_by=resource_type", wantCode: http.StatusOK, want: map[string]int{"nano": 2, "large": 1}},
+		{path: "/usage?group_by=unknown", wantCode: http.StatusBadRequest},
+		{path: "/usage?from=invalid", wantCode: http.StatusBadRequest},
+		{path: "/usage?from=2030-01-01T00:00:00Z&to=2029-01-01T00:00:00Z", wantCode: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		resp, err := http.Get(testURL + test.path)
+		if err != nil {
+			t.Fatalf("failed to request: %+v", err)
+		}
+		content, code := parseResponse(resp)
+		if code != test.wantCode {
+			t.Fatalf("must be response statuscode is %d, but got %d (%s): %s", test.wantCode, code, test.path, string(content))
+		}
+		if code != http.StatusOK {
+			continue
+		}
+
+		var got []datastore.Usage
+		if err := json.Unmarshal(content, &got); err != nil {
+			t.Fatalf("failed to unmarshal response: %+v", err)
+		}
+		gotRunners := map[string]int{}
+		for _, u := range got {
+			gotRunners[u.Target+u.ResourceType] = u.Runners
+		}
+		if len(gotRunners) != len(test.want) {
+			t.Fatalf("must be %d groups, but got %+v", len(test.want), got)
+		}
+		for key, want := range test.want {
+			if gotRunners[key] != want {
+				t.Errorf("must be %d runners in %s, but got %d", want, key, gotRunners[key])
+			}
+		}
+	}
+
+	resp, err := http.Get(testURL + "/usage?group_by=target,resource_type&format=csv")
+	if err != nil {
+		t.Fatalf("failed to request: %+v", err)
+	}
+	content, code := parseResponse(resp)
+	if code != http.StatusOK {
+		t.Fatalf("must be response statuscode is %d, but got %d: %s", http.StatusOK, code, string(content))
+	}
+	if got := resp.Header.Get("Content-Type"); got != "text/csv;charset=utf-8" {
+		t.Errorf("must be csv, but got %s", got)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 3 || lines[0] != "target,resource_type,runners,runner_minutes,estimated_cost" {
+		t.Errorf("invalid csv: %s", string(content))
+	}
+}
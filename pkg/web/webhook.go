@@ -1,10 +1,13 @@
 package web
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -18,24 +21,60 @@ import (
 	"github.com/whywaita/myshoes/pkg/gh"
 	"github.com/whywaita/myshoes/pkg/logger"
 	"github.com/whywaita/myshoes/pkg/metric"
+	"github.com/whywaita/myshoes/pkg/runner"
+	"github.com/whywaita/myshoes/pkg/tracing"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
+// validateWebhookPayload validate payload by secrets of webhook.
+// previous secret is also accepted during grace period of rotation.
+func validateWebhookPayload(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	var payload []byte
+	for i, secret := range config.WebhookSecrets(time.Now()) {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		payload, err = github.ValidatePayload(r, secret)
+		if err == nil {
+			if i > 0 {
+				logger.Logf(false, "webhook payload is validated by previous secret, please update secret of GitHub Apps")
+			}
+			return payload, nil
+		}
+	}
+	return nil, err
+}
+
 // HandleGitHubEvent handle GitHub webhook event
 func HandleGitHubEvent(w http.ResponseWriter, r *http.Request, ds datastore.Datastore) {
-	ctx := r.Context()
 	startTime := time.Now()
 	eventType := github.WebHookType(r)
+	ctx, span := tracing.Start(r.Context(), "HandleGitHubEvent",
+		tracing.AttributeEventType.String(eventType),
+		tracing.AttributeDeliveryID.String(github.DeliveryID(r)),
+	)
+	defer span.End()
+	ctx = logger.WithFields(ctx, logger.KeyDeliveryID, github.DeliveryID(r))
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		ctx = logger.WithFields(ctx, logger.KeyTraceID, traceID)
+	}
 
-	payload, err := github.ValidatePayload(r, config.Config.GitHub.AppSecret)
+	payload, err := validateWebhookPayload(r)
 	if err != nil {
-		logger.Logf(false, "failed to validate webhook payload: %+v\n", err)
+		logger.Error(ctx, "failed to validate webhook payload", "error", err)
+		tracing.RecordError(span, err)
 		w.WriteHeader(http.StatusBadRequest)
 		metric.WebhookReceivedTotal.WithLabelValues(eventType, "invalid", "unknown").Inc()
 		return
 	}
 	webhookEvent, err := github.ParseWebHook(eventType, payload)
 	if err != nil {
-		logger.Logf(false, "failed to parse webhook payload: %+v\n", err)
+		logger.Error(ctx, "failed to parse webhook payload", "error", err)
+		tracing.RecordError(span, err)
 		w.WriteHeader(http.StatusBadRequest)
 		metric.WebhookReceivedTotal.WithLabelValues(eventType, "parse_error", "unknown").Inc()
 		return
@@ -53,7 +92,8 @@ func HandleGitHubEvent(w http.ResponseWriter, r *http.Request, ds datastore.Data
 	switch event := webhookEvent.(type) {
 	case *github.PingEvent:
 		if err := receivePingWebhook(ctx, event); err != nil {
-			logger.Logf(false, "failed to process ping event: %+v\n", err)
+			logger.Error(ctx, "failed to process ping event", "error", err)
+			tracing.RecordError(span, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			metric.WebhookReceivedTotal.WithLabelValues("ping", "error", "n/a").Inc()
 			return
@@ -70,7 +110,8 @@ func HandleGitHubEvent(w http.ResponseWriter, r *http.Request, ds datastore.Data
 		}
 
 		if err := receiveCheckRunWebhook(ctx, event, ds); err != nil {
-			logger.Logf(false, "failed to process check_run event: %+v\n", err)
+			logger.Error(ctx, "failed to process check_run event", "error", err)
+			tracing.RecordError(span, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			metric.WebhookReceivedTotal.WithLabelValues("check_run", "error", "n/a").Inc()
 			return
@@ -87,7 +128,8 @@ func HandleGitHubEvent(w http.ResponseWriter, r *http.Request, ds datastore.Data
 		}
 
 		if err := receiveWorkflowJobWebhook(ctx, event, ds); err != nil {
-			logger.Logf(false, "failed to process workflow_job event: %+v\n", err)
+			logger.Error(ctx, "failed to process workflow_job event", "error", err)
+			tracing.RecordError(span, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			metric.WebhookReceivedTotal.WithLabelValues("workflow_job", "error", runsOn).Inc()
 			return
@@ -141,6 +183,7 @@ func receiveCheckRunWebhook(ctx context.Context, event *github.CheckRunEvent, ds
 // repoName is :owner/:repo
 // repoURL is https://github.com/:owenr/:repo (in github.com) or https://github.example.com/:owner/:repo (in GitHub Enterprise)
 func processCheckRun(ctx context.Context, ds datastore.Datastore, repoName, repoURL string, installationID int64, requestJSON []byte) error {
+	receivedAt := time.Now().UTC()
 	if err := gh.CheckSignature(installationID); err != nil {
 		return fmt.Errorf("failed to create GitHub client: %w", err)
 	}
@@ -155,19 +198,24 @@ func processCheckRun(ctx context.Context, ds datastore.Datastore, repoName, repo
 		gheDomain = fmt.Sprintf("%s://%s", u.Scheme, u.Host)
 	}
 
-	logger.Logf(false, "receive webhook repository: %s/%s", gheDomain, repoName)
+	logger.Info(ctx, "receive webhook", "repo", gheDomain+"/"+repoName)
 	target, err := datastore.SearchRepo(ctx, ds, repoName)
 	if err != nil {
 		return fmt.Errorf("failed to search registered target: %w", err)
 	}
 
-	if !target.CanReceiveJob() {
-		// do nothing if status is cannot receive
-		logger.Logf(false, "%s/%s is %s now, do nothing", gheDomain, repoName, target.Status)
+	if !target.CanEnqueueJob() {
+		// do nothing if status is cannot receive, a job for paused target is queued and processed after resume
+		logger.Info(ctx, "target cannot receive job now, do nothing", logger.KeyTargetID, target.UUID.String(), "status", target.Status)
 		return nil
 	}
 
 	jobID := uuid.NewV4()
+	ctx, span := tracing.Start(ctx, "EnqueueJob",
+		tracing.AttributeTargetID.String(target.UUID.String()),
+		tracing.AttributeJobID.String(jobID.String()),
+	)
+	defer span.End()
 	j := datastore.Job{
 		UUID: jobID,
 		GHEDomain: sql.NullString{
@@ -177,15 +225,27 @@ func processCheckRun(ctx context.Context, ds datastore.Datastore, repoName, repo
 		Repository:     repoName,
 		CheckEventJSON: string(requestJSON),
 		TargetID:       target.UUID,
+		TraceParent:    tracing.TraceParent(ctx),
+		ReceivedAt:     sql.NullTime{Time: receivedAt, Valid: true},
 	}
 	if err := ds.EnqueueJob(ctx, j); err != nil {
+		tracing.RecordError(span, err)
 		return fmt.Errorf("failed to enqueue job: %w", err)
 	}
+	logger.Info(ctx, "job is enqueued", logger.KeyTargetID, target.UUID.String(), logger.KeyJobID, jobID.String())
 
 	return nil
 }
 
 func receiveWorkflowJobWebhook(ctx context.Context, event *github.WorkflowJobEvent, ds datastore.Datastore) error {
+	ctx = logger.WithFields(ctx,
+		logger.KeyGitHubRunID, event.GetWorkflowJob().GetRunID(),
+		logger.KeyGitHubJobID, event.GetWorkflowJob().GetID(),
+	)
+	trace.SpanFromContext(ctx).SetAttributes(
+		tracing.AttributeRunID.Int64(event.GetWorkflowJob().GetRunID()),
+		tracing.AttributeGitHubJob.Int64(event.GetWorkflowJob().GetID()),
+	)
 	action := event.GetAction()
 	installationID := event.GetInstallation().GetID()
 
@@ -193,15 +253,20 @@ func receiveWorkflowJobWebhook(ctx context.Context, event *github.WorkflowJobEve
 	repoName := repo.GetFullName()
 	repoURL := repo.GetHTMLURL()
 
+	if action == "in_progress" {
+		// a job that doesn't request myshoes label can be executed in runner of myshoes
+		return processWorkflowJobInProgress(ctx, event, ds)
+	}
+
 	labels := event.GetWorkflowJob().Labels
 	if !gh.IsRequestedMyshoesLabel(labels) {
 		// is not request myshoes, So will be ignored
-		logger.Logf(true, "label \"myshoes\" is not found in labels, so ignore (labels: %s)", labels)
+		logger.Debug(ctx, "label \"myshoes\" is not found in labels, so ignore", "labels", labels)
 		return nil
 	}
 
 	if action != "queued" {
-		logger.Logf(true, "workflow_job actions is not queued, ignore")
+		logger.Debug(ctx, "workflow_job actions is not queued, ignore", "action", action)
 		return nil
 	}
 
@@ -220,3 +285,79 @@ func receiveWorkflowJobWebhook(ctx context.Context, event *github.WorkflowJobEve
 
 	return nil
 }
+
+// processWorkflowJobInProgress record a job that runner actually executed.
+// a runner of ephemeral mode may execute other job than requested job.
+func processWorkflowJobInProgress(ctx context.Context, event *github.WorkflowJobEvent, ds datastore.Datastore) error {
+	workflowJob := event.GetWorkflowJob()
+	runnerName := workflowJob.GetRunnerName()
+	ctx = logger.WithFields(ctx, logger.KeyRunnerName, runnerName)
+	if !strings.HasPrefix(runnerName, runner.ToName("")) {
+		logger.Debug(ctx, "runner is not created by myshoes, ignore")
+		return nil
+	}
+	runnerID, err := runner.ToUUID(runnerName)
+	if err != nil {
+		logger.Debug(ctx, "failed to parse runner name, ignore", "error", err)
+		return nil
+	}
+
+	repoName := event.GetRepo().GetFullName()
+	executedAt := workflowJob.GetStartedAt().Time
+	if executedAt.IsZero() {
+		executedAt = time.Now().UTC()
+	}
+	err = ds.SetRunnerExecutedJob(ctx, runnerID, datastore.ExecutedJob{
+		Repository: repoName,
+		RunID:      workflowJob.GetRunID(),
+		JobID:      workflowJob.GetID(),
+		ExecutedAt: executedAt,
+	})
+	switch {
+	case errors.Is(err, datastore.ErrNotFound):
+		logger.Debug(ctx, "runner is not found in datastore, ignore")
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to set executed job (runner: %s): %w", runnerName, err)
+	}
+
+	r, err := ds.GetRunner(ctx, runnerID)
+	if err != nil {
+		return fmt.Errorf("failed to get runner (runner: %s): %w", runnerName, err)
+	}
+
+	recordJobPickup(ctx, ds, *r, executedAt)
+
+	result := "matched"
+	switch {
+	case r.RequestedJobID() == 0:
+		result = "unknown"
+	case r.IsJobMismatched():
+		result = "mismatched"
+		logger.Warn(ctx, "runner executed other job than requested job", "requested_job", r.RequestedJobID(), "repo", repoName)
+	}
+	metric.WebhookRunnerJobBinding.WithLabelValues(result, repoName).Inc()
+
+	return nil
+}
+
+// recordJobPickup observe duration from runner online to job started.
+// runner may execute a job before runner manager find it as online, then online time is set to time of job started.
+func recordJobPickup(ctx context.Context, ds datastore.Datastore, r datastore.Runner, executedAt time.Time) {
+	target, err := ds.GetTarget(ctx, r.TargetID)
+	if err != nil {
+		logger.Error(ctx, "failed to get target of runner", "error", err)
+		return
+	}
+
+	if !r.OnlineAt.Valid {
+		if err := runner.RecordRunnerOnline(ctx, ds, r, target.Scope, executedAt); err != nil {
+			logger.Error(ctx, "failed to record runner online", "error", err)
+		}
+		return
+	}
+	if executedAt.Before(r.OnlineAt.Time) {
+		return
+	}
+	runner.JobPickupDuration.WithLabelValues(target.Scope, r.ResourceType.String()).Observe(executedAt.Sub(r.OnlineAt.Time).Seconds())
+}
@@ -0,0 +1,77 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/whywaita/myshoes/pkg/datastore"
+	"github.com/whywaita/myshoes/pkg/datastore/memory"
+)
+
+func Test_processCheckRun_pausedTarget(t *testing.T) {
+	ctx := context.Background()
+	ds, err := memory.New()
+	if err != nil {
+		t.Fatalf("failed to create datastore: %+v", err)
+	}
+	targetID := uuid.NewV4()
+	if err := ds.CreateTarget(ctx, datastore.Target{
+		UUID:   targetID,
+		Scope:  "octocat",
+		Status: datastore.TargetStatusSuspend,
+	}); err != nil {
+		t.Fatalf("failed to create target: %+v", err)
+	}
+
+	if err := processCheckRun(ctx, ds, "octocat/Hello-World", "https://github.com/octocat/Hello-World", 1, []byte("{}")); err != nil {
+		t.Fatalf("failed to process webhook: %+v", err)
+	}
+	jobs, err := ds.ListJobs(ctx)
+	if err != nil {
+		t.Fatalf("failed to list jobs: %+v", err)
+	}
+	if len(jobs) != 1 || jobs[0].TargetID != targetID {
+		t.Fatalf("job for paused target must be queued, but got %+v", jobs)
+	}
+	paused, err := datastore.IsProvisioningPaused(ctx, ds, jobs[0].TargetID, time.Now())
+	if err != nil {
+		t.Fatalf("failed to check provisioning is paused: %+v", err)
+	}
+	if !paused {
+		t.Fatalf("job must not be processed while target is paused")
+	}
+
+	rec := httptest.NewRecorder()
+	NewMux(ds).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/target/"+targetID.String()+"/resume", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("failed to resume target: %d %s", rec.Code, rec.Body.String())
+	}
+
+	// queued job is processed by starter after resume
+	paused, err = datastore.IsProvisioningPaused(ctx, ds, jobs[0].TargetID, time.Now())
+	if err != nil {
+		t.Fatalf("failed to check provisioning is paused: %+v", err)
+	}
+	if paused {
+		t.Fatalf("job must be processed after resume")
+	}
+	jobs, err = ds.ListJobs(ctx)
+	if err != nil {
+		t.Fatalf("failed to list jobs: %+v", err)
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("job must be kept in queue until processed, but got %+v", jobs)
+	}
+
+	if err := ds.DeleteTarget(ctx, targetID); err != nil {
+		t.Fatalf("failed to delete target: %+v", err)
+	}
+	if err := processCheckRun(ctx, ds, "octocat/Hello-World", "https://github.com/octocat/Hello-World", 1, []byte("{}")); err == nil {
+		t.Fatalf("job for deleted target must not be queued")
+	}
+}